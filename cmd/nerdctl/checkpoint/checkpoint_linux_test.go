@@ -0,0 +1,130 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestCheckpoint(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// CRIU needs root, and docker checkpoints are not stored as images
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		nerdtest.Rootful,
+		require.Binary("criu"),
+	)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.CommonImage,
+			"sh", "-c", "date +%s%N > /started; sleep "+nerdtest.Infinity)
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+		data.Labels().Set("container", data.Identifier())
+		data.Labels().Set("started", helpers.Capture("exec", data.Identifier(), "cat", "/started"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "create stops the container",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("checkpoint", "create", data.Labels().Get("container"), "cp1")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Equals("cp1\n"),
+						func(stdout string, info string, t *testing.T) {
+							helpers.Command("inspect", "--format", "{{.State.Running}}", data.Labels().Get("container")).
+								Run(&test.Expected{Output: expect.Equals("false\n")})
+						},
+					),
+				}
+			},
+		},
+		{
+			Description: "ls lists the checkpoint",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("container", "checkpoint", "ls", "--quiet", data.Labels().Get("container"))
+			},
+			Expected: test.Expects(0, nil, expect.Equals("cp1\n")),
+		},
+		{
+			Description: "start restores the container from the checkpoint",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("start", "--checkpoint", "cp1", data.Labels().Get("container"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, info string, t *testing.T) {
+						// The process was restored, not started again
+						helpers.Command("exec", data.Labels().Get("container"), "cat", "/started").
+							Run(&test.Expected{Output: expect.Equals(data.Labels().Get("started"))})
+					},
+				}
+			},
+		},
+		{
+			Description: "create refuses an existing checkpoint name",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("checkpoint", "create", "--leave-running", data.Labels().Get("container"), "cp1")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+		{
+			Description: "create with --leave-running keeps the container running",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("checkpoint", "create", "--leave-running", data.Labels().Get("container"), "cp2")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, info string, t *testing.T) {
+						helpers.Command("inspect", "--format", "{{.State.Running}}", data.Labels().Get("container")).
+							Run(&test.Expected{Output: expect.Equals("true\n")})
+					},
+				}
+			},
+		},
+		{
+			Description: "rm removes the checkpoint",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				helpers.Ensure("checkpoint", "rm", data.Labels().Get("container"), "cp2")
+				return helpers.Command("checkpoint", "ls", "--quiet", data.Labels().Get("container"))
+			},
+			Expected: test.Expects(0, nil, expect.Equals("cp1\n")),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -19,8 +19,10 @@ package compose
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/container"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
 	"github.com/containerd/nerdctl/v2/pkg/composer"
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
 )
 
 func Command() *cobra.Command {
@@ -39,6 +41,7 @@ func Command() *cobra.Command {
 	cmd.PersistentFlags().String("env-file", "", "Specify an alternate environment file")
 	cmd.PersistentFlags().String("ipfs-address", "", "multiaddr of IPFS API (default uses $IPFS_PATH env variable if defined or local directory ~/.ipfs)")
 	cmd.PersistentFlags().StringArray("profile", []string{}, "Specify a profile to enable")
+	cmd.PersistentFlags().Bool("secrets-on-disk", false, "Allow writing the secrets and configs on the disk when a tmpfs cannot be mounted for them (e.g., in rootless mode)")
 
 	cmd.AddCommand(
 		upCommand(),
@@ -53,6 +56,7 @@ func Command() *cobra.Command {
 		pullCommand(),
 		downCommand(),
 		psCommand(),
+		lsCommand(),
 		killCommand(),
 		restartCommand(),
 		removeCommand(),
@@ -64,6 +68,7 @@ func Command() *cobra.Command {
 		unpauseCommand(),
 		topCommand(),
 		createCommand(),
+		watchCommand(),
 	)
 
 	return cmd
@@ -95,6 +100,19 @@ func getComposeOptions(cmd *cobra.Command, debugFull, experimental bool) (compos
 	if err != nil {
 		return composer.Options{}, err
 	}
+	filesOnDisk, err := cmd.Flags().GetBool("secrets-on-disk")
+	if err != nil {
+		return composer.Options{}, err
+	}
+
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return composer.Options{}, err
+	}
+	createOpt, netOpts, err := container.NewDefaultRunOptions(globalOptions, nerdctlCmd, nerdctlArgs, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	if err != nil {
+		return composer.Options{}, err
+	}
 
 	return composer.Options{
 		Project:          projectName,
@@ -107,5 +125,7 @@ func getComposeOptions(cmd *cobra.Command, debugFull, experimental bool) (compos
 		DebugPrintFull:   debugFull,
 		Experimental:     experimental,
 		IPFSAddress:      ipfsAddressStr,
+		RunDefaults:      serviceparser.Defaults{CreateOptions: createOpt, NetworkOptions: netOpts},
+		FilesOnDisk:      filesOnDisk,
 	}, nil
 }
@@ -0,0 +1,137 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/composer"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+)
+
+func lsCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:           "ls [flags]",
+		Short:         "List running compose projects",
+		Args:          cobra.NoArgs,
+		RunE:          lsAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().BoolP("all", "a", false, "Show all projects (default shows just running)")
+	cmd.Flags().StringArray("filter", []string{}, "Filter output based on conditions provided (name=NAME)")
+	cmd.Flags().String("format", "table", "Format the output. Supported values: [table|json]")
+	cmd.Flags().BoolP("quiet", "q", false, "Only display project names")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "table"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+func lsAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		return err
+	}
+	filters, err := cmd.Flags().GetStringArray("filter")
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok {
+			return fmt.Errorf("invalid argument \"%s\" for \"--filter\": bad format of filter (expected name=value)", filter)
+		}
+		// currently only the 'name' filter is supported
+		if key != "name" {
+			return fmt.Errorf("invalid filter '%s'", key)
+		}
+		names = append(names, value)
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	if format != "json" && format != "table" {
+		return fmt.Errorf("unsupported format %s, supported formats are: [table|json]", format)
+	}
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	projects, err := composer.ListProjects(ctx, client, all)
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		var filtered []composer.ProjectSummary
+		for _, p := range projects {
+			for _, name := range names {
+				// like the name filter of containers, match a part of the name
+				if strings.Contains(p.Name, name) {
+					filtered = append(filtered, p)
+					break
+				}
+			}
+		}
+		projects = filtered
+	}
+
+	if quiet {
+		for _, p := range projects {
+			fmt.Fprintln(cmd.OutOrStdout(), p.Name)
+		}
+		return nil
+	}
+	if format == "json" {
+		if projects == nil {
+			projects = []composer.ProjectSummary{}
+		}
+		outJSON, err := formatter.ToJSON(projects, "", "")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(cmd.OutOrStdout(), outJSON)
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tCONFIG FILES")
+	for _, p := range projects {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", p.Name, p.Status, p.ConfigFiles); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,108 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer"
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestComposeLs(t *testing.T) {
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+  svc1:
+    image: %s
+    command: "true"
+`, testutil.CommonImage, testutil.CommonImage)
+
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		yamlPath := data.Temp().Save(dockerComposeYAML, "compose.yaml")
+		helpers.Ensure("compose", "-f", yamlPath, "-p", data.Identifier(), "up", "-d")
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier()+"-svc0-1")
+		data.Labels().Set("project", data.Identifier())
+		data.Labels().Set("yamlPath", yamlPath)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("compose", "-f", data.Temp().Path("compose.yaml"), "-p", data.Identifier(), "down")
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "running containers",
+			Command:     test.Command("compose", "ls"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Match(regexp.MustCompile(regexp.QuoteMeta(data.Labels().Get("project")) + `\s+running\(1\)\s+` + regexp.QuoteMeta(data.Labels().Get("yamlPath")))),
+				}
+			},
+		},
+		{
+			Description: "all containers in json",
+			Command:     test.Command("compose", "ls", "--all", "--format", "json"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.JSON([]composer.ProjectSummary{}, func(projects []composer.ProjectSummary, s string, t tig.T) {
+						for _, p := range projects {
+							if p.Name == data.Labels().Get("project") {
+								assert.Equal(t, p.Status, "exited(1), running(1)", s)
+								assert.Equal(t, p.ConfigFiles, data.Labels().Get("yamlPath"), s)
+								return
+							}
+						}
+						t.Log("project not found: " + s)
+						t.FailNow()
+					}),
+				}
+			},
+		},
+		{
+			Description: "filter by name",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("compose", "ls", "--quiet", "--filter", "name="+data.Labels().Get("project"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Equals(data.Labels().Get("project") + "\n"),
+				}
+			},
+		},
+		{
+			Description: "unsupported filter",
+			Command:     test.Command("compose", "ls", "--filter", "status=running"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/cmd/compose"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/portutil"
 )
@@ -65,7 +66,7 @@ type composeContainerPrintable struct {
 	Project  string
 	Service  string
 	State    string
-	Health   string // placeholder, lack containerd support.
+	Health   string
 	ExitCode uint32
 	// `Publishers` stores docker-compatible ports and used for json output.
 	// `Ports` stores formatted ports and only used for console output.
@@ -246,6 +247,9 @@ func composeContainerPrintableTab(ctx context.Context, container containerd.Cont
 	status := formatter.ContainerStatus(ctx, container)
 	if status == "Up" {
 		status = "running" // corresponds to Docker Compose v2.0.1
+		if health := containerHealth(info.Labels); health != "" {
+			status = fmt.Sprintf("%s (%s)", status, health)
+		}
 	}
 	image, err := container.Image(ctx)
 	if err != nil {
@@ -276,6 +280,7 @@ func composeContainerPrintableJSON(ctx context.Context, container containerd.Con
 
 	var (
 		state    string
+		health   string
 		exitCode uint32
 	)
 	status, err := containerutil.ContainerStatus(ctx, container)
@@ -287,6 +292,9 @@ func composeContainerPrintableJSON(ctx context.Context, container containerd.Con
 		} else {
 			state = string(status.Status)
 		}
+		if status.Status == containerd.Running {
+			health = containerHealth(info.Labels)
+		}
 	} else {
 		state = string(containerd.Unknown)
 	}
@@ -303,12 +311,34 @@ func composeContainerPrintableJSON(ctx context.Context, container containerd.Con
 		Project:    info.Labels[labels.ComposeProject],
 		Service:    info.Labels[labels.ComposeService],
 		State:      state,
-		Health:     "",
+		Health:     health,
 		ExitCode:   exitCode,
 		Publishers: formatPublishers(info.Labels),
 	}, nil
 }
 
+// containerHealth returns the health status of a running container, i.e., "starting", "healthy" or "unhealthy".
+// An empty string is returned when the container has no healthcheck.
+func containerHealth(labelMap map[string]string) string {
+	hcJSON, ok := labelMap[labels.HealthCheck]
+	if !ok {
+		return ""
+	}
+	hc, err := healthcheck.HealthCheckFromJSON(hcJSON)
+	if err != nil || len(hc.Test) == 0 || hc.Test[0] == healthcheck.CmdNone {
+		return ""
+	}
+	stateJSON, ok := labelMap[labels.HealthState]
+	if !ok {
+		return healthcheck.Starting
+	}
+	state, err := healthcheck.HealthStateFromJSON(stateJSON)
+	if err != nil {
+		return ""
+	}
+	return state.Status
+}
+
 // PortPublisher hold status about published port
 // Use this to match the json output with docker compose
 // FYI: https://github.com/docker/compose/blob/v2.13.0/pkg/api/api.go#L305C27-L311
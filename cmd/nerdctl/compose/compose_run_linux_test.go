@@ -425,7 +425,6 @@ services:
 }
 
 func TestComposePushAndPullWithCosignVerify(t *testing.T) {
-	testutil.RequireExecutable(t, "cosign")
 	testutil.DockerIncompatible(t)
 	testutil.RequiresBuild(t)
 	testutil.RegisterBuildCacheCleanup(t)
@@ -457,6 +456,7 @@ services:
     image: %s
     x-nerdctl-verify: cosign
     x-nerdctl-cosign-public-key: %s
+    x-nerdctl-cosign-insecure-ignore-tlog: true
     x-nerdctl-sign: cosign
     x-nerdctl-cosign-private-key: %s
     entrypoint:
@@ -466,6 +466,7 @@ services:
     image: %s
     x-nerdctl-verify: cosign
     x-nerdctl-cosign-public-key: dummy_pub_key
+    x-nerdctl-cosign-insecure-ignore-tlog: true
     x-nerdctl-sign: cosign
     x-nerdctl-cosign-private-key: %s
     entrypoint:
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -51,6 +52,9 @@ func upCommand() *cobra.Command {
 	cmd.Flags().Bool("no-recreate", false, "Don't recreate containers if they exist, conflict with --force-recreate.")
 	cmd.Flags().StringArray("scale", []string{}, "Scale SERVICE to NUM instances. Overrides the `scale` setting in the Compose file if present.")
 	cmd.Flags().String("pull", "", "Pull image before running (\"always\"|\"missing\"|\"never\")")
+	cmd.Flags().BoolP("watch", "w", false, "Watch the build contexts of the services and sync, restart or rebuild their containers when files are updated. Incompatible with -d.")
+	cmd.Flags().Bool("wait", false, "Wait for services to be running and healthy. Implies detached mode.")
+	cmd.Flags().Int("wait-timeout", 0, "Maximum duration in seconds to wait for the services to be running and healthy (0 for no timeout). Requires --wait.")
 	return cmd
 }
 
@@ -120,6 +124,35 @@ func upAction(cmd *cobra.Command, services []string) error {
 	if forceRecreate && noRecreate {
 		return errors.New("flag --force-recreate and --no-recreate cannot be specified together")
 	}
+	watch, err := cmd.Flags().GetBool("watch")
+	if err != nil {
+		return err
+	}
+	if detach && watch {
+		return errors.New("--watch flag is incompatible with flag --detach")
+	}
+	wait, err := cmd.Flags().GetBool("wait")
+	if err != nil {
+		return err
+	}
+	waitTimeout, err := cmd.Flags().GetInt("wait-timeout")
+	if err != nil {
+		return err
+	}
+	if wait {
+		if abortOnContainerExit {
+			return errors.New("--wait flag is incompatible with flag --abort-on-container-exit")
+		}
+		if watch {
+			return errors.New("--wait flag is incompatible with flag --watch")
+		}
+		detach = true
+	} else if cmd.Flags().Changed("wait-timeout") {
+		return errors.New("--wait-timeout requires --wait")
+	}
+	if waitTimeout < 0 {
+		return fmt.Errorf("invalid --wait-timeout %d: must not be negative", waitTimeout)
+	}
 	scale := make(map[string]int)
 	for _, s := range scaleSlice {
 		parts := strings.Split(s, "=")
@@ -162,6 +195,9 @@ func upAction(cmd *cobra.Command, services []string) error {
 		Pull:                 pull,
 		ForceRecreate:        forceRecreate,
 		NoRecreate:           noRecreate,
+		Watch:                watch,
+		Wait:                 wait,
+		WaitTimeout:          time.Duration(waitTimeout) * time.Second,
 	}
 	return c.Up(ctx, uo, services)
 }
@@ -567,6 +567,43 @@ services:
 	c.Assert(expected)
 }
 
+func TestComposeUpDependsOnCompletedSuccessfully(t *testing.T) {
+	base := testutil.NewBase(t)
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  init:
+    image: %[1]s
+    entrypoint: /bin/sh -c "sleep 3; exit 0"
+  app:
+    image: %[1]s
+    entrypoint: /bin/sh -c "sleep infinity"
+    depends_on:
+      init:
+        condition: service_completed_successfully
+  failing:
+    image: %[1]s
+    entrypoint: /bin/sh -c "exit 1"
+  blocked:
+    image: %[1]s
+    entrypoint: /bin/sh -c "sleep infinity"
+    depends_on:
+      failing:
+        condition: service_completed_successfully
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "app").AssertOK()
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "app").AssertOutContains("running")
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "blocked").AssertFail()
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "-a", "blocked").AssertOutNotContains("running")
+}
+
 func TestComposeUpPull(t *testing.T) {
 	base := testutil.NewBase(t)
 
@@ -620,3 +657,125 @@ services:
 	base.Cmd("images").AssertOutNotContains(testutil.CommonImage)
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "up").AssertExitCode(1)
 }
+
+func TestComposeUpSecretsAndConfigsFiles(t *testing.T) {
+	base := testutil.NewBase(t)
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+    secrets:
+    - source: secret1
+      uid: "1000"
+      gid: "1001"
+      mode: 0400
+    configs:
+    - source: config1
+      target: /etc/config1
+secrets:
+  secret1:
+    environment: TEST_SECRET1
+configs:
+  config1:
+    content: content-config1
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	base.Env = append(base.Env, "TEST_SECRET1=content-secret1")
+
+	composeArgs := []string{"-f", comp.YAMLFullPath()}
+	if rootlessutil.IsRootless() {
+		// the tmpfs cannot be mounted, the files are only written on the disk when allowed
+		base.ComposeCmd(append(composeArgs, "up", "-d")...).AssertFail()
+		composeArgs = append(composeArgs, "--secrets-on-disk")
+	}
+	base.ComposeCmd(append(composeArgs, "up", "-d")...).AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	containerName := serviceparser.DefaultContainerName(projectName, "svc0", "1")
+	base.Cmd("exec", containerName, "cat", "/run/secrets/secret1").AssertOutExactly("content-secret1")
+	base.Cmd("exec", containerName, "stat", "-c", "%a %u %g", "/run/secrets/secret1").AssertOutExactly("400 1000 1001\n")
+	base.Cmd("exec", containerName, "cat", "/etc/config1").AssertOutExactly("content-config1")
+	base.Cmd("exec", containerName, "stat", "-c", "%a", "/etc/config1").AssertOutExactly("444\n")
+
+	var sources []string
+	for _, m := range base.InspectContainer(containerName).Mounts {
+		if m.Destination == "/run/secrets/secret1" || m.Destination == "/etc/config1" {
+			sources = append(sources, m.Source)
+		}
+	}
+	assert.Equal(t, len(sources), 2)
+
+	// the files are removed along with the container
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "rm", "-f", "-s", "svc0").AssertOK()
+	for _, s := range sources {
+		_, err := os.Stat(s)
+		assert.Assert(t, os.IsNotExist(err), s)
+	}
+}
+
+func TestComposeUpParallelDependencies(t *testing.T) {
+	base := testutil.NewBase(t)
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  init:
+    image: %[1]s
+    entrypoint: /bin/sh -c "sleep 3; exit 0"
+  independent:
+    image: %[2]s
+  app:
+    image: %[1]s
+    entrypoint: /bin/sh -c "sleep infinity"
+    depends_on:
+      init:
+        condition: service_completed_successfully
+      independent:
+        condition: service_started
+`, testutil.CommonImage, testutil.NginxAlpineImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	// the images are pulled concurrently, and the independent service is started while waiting for init
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertOK()
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "app").AssertOutContains("running")
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "independent").AssertOutContains("running")
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "-a", "init").AssertOutContains("exited")
+}
+
+func TestComposeUpWait(t *testing.T) {
+	base := testutil.NewBase(t)
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+    init: true
+  svc1:
+    image: %s
+    command: "sh -c 'echo svc1 is failing; exit 3'"
+`, testutil.CommonImage, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down").AssertOK()
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "--wait", "--wait-timeout", "60", "svc0").AssertOK()
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "svc0").AssertOutContains("running")
+
+	// the last logs of the failing service are printed
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "--wait", "--wait-timeout", "60", "svc1").Assert(icmd.Expected{
+		ExitCode: 1,
+		Err:      "svc1 is failing",
+	})
+}
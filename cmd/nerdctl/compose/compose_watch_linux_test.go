@@ -0,0 +1,274 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestComposeWatchSync(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    container_name: %s
+    command: "sleep infinity"
+    develop:
+      watch:
+        - path: ./app
+          action: sync
+          target: /app
+          ignore:
+            - "*.tmp"
+`, testutil.CommonImage, data.Identifier())
+
+		compYamlPath := data.Temp().Save(dockerComposeYAML, "compose.yaml")
+		data.Temp().Save("foo", "app", "existing.txt")
+		helpers.Ensure("compose", "-f", compYamlPath, "up", "-d")
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+		// there is no initial sync, the file to be removed by the watch is created in the container too
+		helpers.Ensure("exec", data.Identifier(), "sh", "-c", "mkdir -p /app && echo foo > /app/existing.txt")
+		data.Labels().Set("composeYaml", compYamlPath)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("compose", "-f", data.Temp().Path("compose.yaml"), "down", "-v")
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		cmd := helpers.Command("compose", "-f", data.Labels().Get("composeYaml"), "watch", "--no-up")
+		cmd.WithTimeout(30 * time.Second)
+		cmd.Background()
+
+		// wait for the watch to start, then update the files
+		time.Sleep(3 * time.Second)
+		data.Temp().Save("bar", "app", "sub", "new.txt")
+		data.Temp().Save("ignored", "app", "new.tmp")
+		assert.NilError(helpers.T(), os.Remove(filepath.Join(data.Temp().Path("app"), "existing.txt")))
+
+		waitFor := func(args ...string) {
+			for range 30 {
+				ok := false
+				helpers.Command(append([]string{"exec", data.Identifier()}, args...)...).Run(&test.Expected{
+					ExitCode: expect.ExitCodeNoCheck,
+					Output: func(stdout, info string, t *testing.T) {
+						ok = stdout == "ok\n"
+					},
+				})
+				if ok {
+					return
+				}
+				time.Sleep(500 * time.Millisecond)
+			}
+			helpers.T().Fatalf("timeout waiting for %v", args)
+		}
+		waitFor("sh", "-c", "[ \"$(cat /app/sub/new.txt)\" = bar ] && echo ok")
+		waitFor("sh", "-c", "[ ! -e /app/existing.txt ] && echo ok")
+		return cmd
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			ExitCode: expect.ExitCodeTimeout,
+			Output: func(stdout, info string, t *testing.T) {
+				helpers.Command("exec", data.Identifier(), "ls", "/app").Run(&test.Expected{
+					Output: expect.DoesNotContain("new.tmp"),
+				})
+			},
+		}
+	}
+
+	testCase.Run(t)
+}
+
+func TestComposeWatchSyncRestart(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    container_name: %s
+    command: "sh -c 'echo started >> /starts; sleep infinity'"
+    develop:
+      watch:
+        - path: ./app
+          action: sync+restart
+          target: /app
+`, testutil.CommonImage, data.Identifier())
+
+		compYamlPath := data.Temp().Save(dockerComposeYAML, "compose.yaml")
+		data.Temp().Save("foo", "app", "config.txt")
+		helpers.Ensure("compose", "-f", compYamlPath, "up", "-d")
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+		data.Labels().Set("composeYaml", compYamlPath)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("compose", "-f", data.Temp().Path("compose.yaml"), "down", "-v")
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		cmd := helpers.Command("compose", "-f", data.Labels().Get("composeYaml"), "watch", "--no-up")
+		cmd.WithTimeout(30 * time.Second)
+		cmd.Background()
+
+		// wait for the watch to start, then update the file
+		time.Sleep(3 * time.Second)
+		data.Temp().Save("bar", "app", "config.txt")
+
+		// the restarted container keeps its filesystem, so each start appends a line
+		for range 30 {
+			ok := false
+			helpers.Command("exec", data.Identifier(), "sh", "-c", "[ \"$(wc -l < /starts)\" -eq 2 ] && echo ok").Run(&test.Expected{
+				ExitCode: expect.ExitCodeNoCheck,
+				Output: func(stdout, info string, t *testing.T) {
+					ok = stdout == "ok\n"
+				},
+			})
+			if ok {
+				return cmd
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+		helpers.T().Fatalf("timeout waiting for the container to be restarted")
+		return cmd
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			ExitCode: expect.ExitCodeTimeout,
+			Output: func(stdout, info string, t *testing.T) {
+				// the file is synced before the container is restarted
+				helpers.Command("exec", data.Identifier(), "cat", "/app/config.txt").Run(&test.Expected{
+					Output: expect.Equals("bar"),
+				})
+			},
+		}
+	}
+
+	testCase.Run(t)
+}
+
+func TestComposeUpWatchRebuild(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		nerdtest.Build,
+	)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    build: .
+    image: %s
+    develop:
+      watch:
+        - path: ./app
+          action: rebuild
+`, data.Identifier("image"))
+		var dockerfile = fmt.Sprintf(`FROM %s
+COPY app /app
+CMD ["sh", "-c", "echo version-$(cat /app/version); sleep infinity"]
+`, testutil.CommonImage)
+
+		data.Temp().Save(dockerComposeYAML, "compose.yaml")
+		data.Temp().Save(dockerfile, "Dockerfile")
+		data.Temp().Save("1", "app", "version")
+		data.Labels().Set("composeYaml", data.Temp().Path("compose.yaml"))
+		data.Labels().Set("project", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("compose", "-f", data.Temp().Path("compose.yaml"), "-p", data.Identifier(), "down", "-v")
+		helpers.Anyhow("rmi", "-f", data.Identifier("image"))
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		cmd := helpers.Command("compose", "-f", data.Labels().Get("composeYaml"), "-p", data.Labels().Get("project"), "up", "--watch")
+		cmd.WithTimeout(90 * time.Second)
+		cmd.Background()
+
+		containerName := data.Labels().Get("project") + "-svc0-1"
+		containerID := func() string {
+			id := ""
+			helpers.Command("inspect", "--format", "{{.Id}}", containerName).Run(&test.Expected{
+				ExitCode: expect.ExitCodeNoCheck,
+				Output: func(stdout, info string, t *testing.T) {
+					id = stdout
+				},
+			})
+			return id
+		}
+		var oldID string
+		for range 60 {
+			if oldID = containerID(); oldID != "" {
+				break
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+		assert.Assert(helpers.T(), oldID != "", "the container was not created")
+
+		// wait for the watch to start, then update the build context
+		time.Sleep(3 * time.Second)
+		data.Temp().Save("2", "app", "version")
+		for range 60 {
+			if id := containerID(); id != "" && id != oldID {
+				return cmd
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+		helpers.T().Fatalf("timeout waiting for the container to be recreated")
+		return cmd
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			// `up` keeps following the logs of the recreated container instead of returning
+			ExitCode: expect.ExitCodeTimeout,
+			Output: expect.All(
+				expect.Contains("version-1"),
+				expect.Contains("version-2"),
+			),
+		}
+	}
+
+	testCase.Run(t)
+}
@@ -19,6 +19,7 @@ package container
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/checkpoint"
 	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
 )
 
@@ -50,11 +51,13 @@ func Command() *cobra.Command {
 		WaitCommand(),
 		UnpauseCommand(),
 		CommitCommand(),
+		ExportCommand(),
 		RenameCommand(),
 		pruneCommand(),
 		StatsCommand(),
 		AttachCommand(),
 		HealthCheckCommand(),
+		checkpoint.Command(),
 	)
 	AddCpCommand(cmd)
 	return cmd
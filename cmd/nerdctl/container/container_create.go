@@ -56,20 +56,25 @@ func CreateCommand() *cobra.Command {
 	return cmd
 }
 
-//revive:disable:function-length
 func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
-	var err error
-	opt := types.ContainerCreateOptions{
-		Stdout: cmd.OutOrStdout(),
-		Stderr: cmd.ErrOrStderr(),
-	}
-
-	opt.GOptions, err = helpers.ProcessRootCmdFlags(cmd)
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
 	if err != nil {
-		return opt, err
+		return types.ContainerCreateOptions{}, err
 	}
+	nerdctlCmd, nerdctlArgs := helpers.GlobalFlags(cmd)
+	return createOptionsWithGlobalOptions(cmd, globalOptions, nerdctlCmd, nerdctlArgs)
+}
 
-	opt.NerdctlCmd, opt.NerdctlArgs = helpers.GlobalFlags(cmd)
+//revive:disable:function-length
+func createOptionsWithGlobalOptions(cmd *cobra.Command, globalOptions types.GlobalCommandOptions, nerdctlCmd string, nerdctlArgs []string) (types.ContainerCreateOptions, error) {
+	var err error
+	opt := types.ContainerCreateOptions{
+		Stdout:      cmd.OutOrStdout(),
+		Stderr:      cmd.ErrOrStderr(),
+		GOptions:    globalOptions,
+		NerdctlCmd:  nerdctlCmd,
+		NerdctlArgs: nerdctlArgs,
+	}
 
 	// #region for basic flags
 	// The command `container start` doesn't support the flag `--interactive`. Set the default value of `opt.Interactive` false.
@@ -287,6 +292,10 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	opt.HealthOnFailure, err = cmd.Flags().GetString("health-on-failure")
+	if err != nil {
+		return opt, err
+	}
 	if err := helpers.ValidateHealthcheckFlags(opt); err != nil {
 		return opt, err
 	}
@@ -371,6 +380,10 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	if err != nil {
 		return opt, err
 	}
+	opt.VolumeDriver, err = cmd.Flags().GetString("volume-driver")
+	if err != nil {
+		return opt, err
+	}
 	// #endregion
 
 	// #region for rootfs flags
@@ -498,10 +511,7 @@ func createOptions(cmd *cobra.Command) (types.ContainerCreateOptions, error) {
 	// #endregion
 
 	// #region for UserNS
-	opt.UserNS, err = cmd.Flags().GetString("userns-remap")
-	if err != nil {
-		return opt, err
-	}
+	opt.UserNS = globalOptions.UsernsRemap
 
 	userns, err := cmd.Flags().GetString("userns")
 	if err != nil {
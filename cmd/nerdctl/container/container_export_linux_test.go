@@ -0,0 +1,71 @@
+/*
+Copyright The containerd Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestExportImport(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "--name", data.Identifier(), testutil.CommonImage, "sh", "-c", "echo exported > /file")
+		helpers.Ensure("export", "-o", filepath.Join(data.Temp().Path(), "rootfs.tar"), data.Identifier())
+		helpers.Ensure("import", "--change", `CMD ["cat", "/file"]`, "--message", "imported for test",
+			filepath.Join(data.Temp().Path(), "rootfs.tar"), data.Identifier())
+		data.Labels().Set("image", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("rmi", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "the imported image has the content of the container",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", data.Labels().Get("image"))
+			},
+			Expected: test.Expects(0, nil, expect.Equals("exported\n")),
+		},
+		{
+			Description: "the imported image has the commit message in its history",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("image", "history", "--no-trunc", "--format", "{{.Comment}}", data.Labels().Get("image"))
+			},
+			Expected: test.Expects(0, nil, expect.Contains("imported for test")),
+		},
+		{
+			Description: "export refuses a missing container",
+			Command:     test.Command("export", "-o", "/dev/null", "does-not-exist"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
@@ -65,7 +65,7 @@ func healthCheckAction(cmd *cobra.Command, args []string) error {
 			if found.MatchCount > 1 {
 				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
 			}
-			return container.HealthCheck(ctx, client, found.Container)
+			return container.HealthCheck(ctx, client, found.Container, globalOptions)
 		},
 	}
 
@@ -470,6 +470,104 @@ func TestLogsTailFollowRotate(t *testing.T) {
 	testCase.Run(t)
 }
 
+func TestLogsOfLocalDriver(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// tail log is not supported on Windows
+	testCase.Require = require.Not(require.Windows)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "--log-driver", "local",
+			"--log-opt", "max-size=1k", "--log-opt", "max-file=3",
+			"--name", data.Identifier(), testutil.CommonImage,
+			"sh", "-euc", "for i in `seq 1 100`; do echo line$i; done; echo err >&2")
+		data.Labels().Set("container", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "logs",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", data.Labels().Get("container"))
+			},
+			Expected: test.Expects(0, nil, expect.All(
+				expect.Contains("line100\n"),
+				expect.DoesNotContain("err"),
+			)),
+		},
+		{
+			Description: "logs --tail spanning the rotated files",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--tail", "30", data.Labels().Get("container"))
+			},
+			Expected: test.Expects(0, nil, func(stdout, info string, t *testing.T) {
+				lines := strings.Split(strings.TrimSpace(stdout), "\n")
+				assert.Equal(t, len(lines), 29, info)
+				assert.Equal(t, lines[0], "line72", info)
+				assert.Equal(t, lines[len(lines)-1], "line100", info)
+			}),
+		},
+		{
+			Description: "logs --since in the future",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", "--since=-1h", data.Labels().Get("container"))
+			},
+			Expected: test.Expects(0, nil, expect.Equals("")),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestLogsOfSyslogDriverWithCache(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.Not(require.Windows)
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "logs are read from the local cache",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				// Nothing listens on the address, the syslog messages are lost
+				helpers.Ensure("run", "--log-driver", "syslog",
+					"--log-opt", "syslog-address=udp://127.0.0.1:9",
+					"--name", data.Identifier(), testutil.CommonImage,
+					"sh", "-euc", "echo foo; echo bar >&2")
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", data.Identifier())
+			},
+			Expected: test.Expects(0, nil, expect.Equals("foo\n")),
+		},
+		{
+			Description: "cache-disabled=true",
+			Require:     require.Not(nerdtest.Docker),
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("run", "--log-driver", "syslog",
+					"--log-opt", "syslog-address=udp://127.0.0.1:9",
+					"--log-opt", "cache-disabled=true",
+					"--name", data.Identifier(), testutil.CommonImage, "echo", "foo")
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("logs", data.Identifier())
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("cache-disabled")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
 func TestLogsNoneLoggerHasNoLogURI(t *testing.T) {
 	testCase := nerdtest.Setup()
 
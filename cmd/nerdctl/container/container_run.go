@@ -19,6 +19,7 @@ package container
 import (
 	"errors"
 	"fmt"
+	"io"
 	"runtime"
 	"strings"
 
@@ -33,10 +34,12 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/config"
 	"github.com/containerd/nerdctl/v2/pkg/consoleutil"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/defaults"
 	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/logging"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
@@ -45,7 +48,7 @@ import (
 )
 
 const (
-	tiniInitBinary = "tini"
+	tiniInitBinary = container.DefaultInitBinary
 )
 
 func RunCommand() *cobra.Command {
@@ -129,6 +132,8 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().StringSlice("dns-option", nil, "Set DNS options")
 	// publish is defined as StringSlice, not StringArray, to allow specifying "--publish=80:80,443:443" (compatible with Podman)
 	cmd.Flags().StringSliceP("publish", "p", nil, "Publish a container's port(s) to the host")
+	cmd.Flags().BoolP("publish-all", "P", false, "Publish all exposed ports to random ports")
+	cmd.Flags().StringSlice("expose", nil, "Expose a port or a range of ports")
 	cmd.Flags().String("ip", "", "IPv4 address to assign to the container")
 	cmd.Flags().String("ip6", "", "IPv6 address to assign to the container")
 	cmd.Flags().StringP("hostname", "h", "", "Container host name")
@@ -227,6 +232,7 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().StringArray("mount", nil, "Attach a filesystem mount to the container")
 	// volumes-from needs to be StringArray, not StringSlice, to prevent "id1,id2" from being split to {"id1", "id2"} (compatible with Docker)
 	cmd.Flags().StringArray("volumes-from", nil, "Mount volumes from the specified container(s)")
+	cmd.Flags().String("volume-driver", "", "Volume driver for the named volumes created by -v (e.g. a volume plugin)")
 	// #endregion
 
 	// rootfs flags
@@ -235,13 +241,17 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().Bool("rootfs", false, "The first argument is not an image but the rootfs to the exploded container")
 
 	// Health check flags
-	cmd.Flags().String("health-cmd", "", "Command to run to check health")
+	cmd.Flags().String("health-cmd", "", "Command to run to check health, or a JSON array in the exec form (e.g., '[\"CMD\", \"curl\", \"-f\", \"http://localhost\"]')")
 	cmd.Flags().Duration("health-interval", 0, "Time between running the check (default: 30s)")
 	cmd.Flags().Duration("health-timeout", 0, "Maximum time to allow one check to run (default: 30s)")
 	cmd.Flags().Int("health-retries", 0, "Consecutive failures needed to report unhealthy (default: 3)")
 	cmd.Flags().Duration("health-start-period", 0, "Start period for the container to initialize before starting health-retries countdown")
 	cmd.Flags().Duration("health-start-interval", 0, "Time between running the checks during the start period")
 	cmd.Flags().Bool("no-healthcheck", false, "Disable any container-specified HEALTHCHECK")
+	cmd.Flags().String("health-on-failure", "none", "Action to take once the container turns unhealthy: none, kill, restart, or stop")
+	cmd.RegisterFlagCompletionFunc("health-on-failure", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"none", "kill", "restart", "stop"}, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	// #region env flags
 	// entrypoint needs to be StringArray, not StringSlice, to prevent "FOO=foo1,foo2" from being split to {"FOO=foo1", "foo2"}
@@ -294,6 +304,7 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().String("cosign-certificate-identity-regexp", "", "A regular expression alternative to --cosign-certificate-identity for --verify=cosign. Accepts the Go regular expression syntax described at https://golang.org/s/re2syntax. Either --cosign-certificate-identity or --cosign-certificate-identity-regexp must be set for keyless flows")
 	cmd.Flags().String("cosign-certificate-oidc-issuer", "", "The OIDC issuer expected in a valid Fulcio certificate for --verify=cosign, e.g. https://token.actions.githubusercontent.com or https://oauth2.sigstore.dev/auth. Either --cosign-certificate-oidc-issuer or --cosign-certificate-oidc-issuer-regexp must be set for keyless flows")
 	cmd.Flags().String("cosign-certificate-oidc-issuer-regexp", "", "A regular expression alternative to --certificate-oidc-issuer for --verify=cosign. Accepts the Go regular expression syntax described at https://golang.org/s/re2syntax. Either --cosign-certificate-oidc-issuer or --cosign-certificate-oidc-issuer-regexp must be set for keyless flows")
+	cmd.Flags().Bool("cosign-insecure-ignore-tlog", false, "Ignore the transparency log (rekor) for --verify=cosign, e.g., for the images signed by nerdctl with a key file. The signatures made with a key file are then verified without the cosign executable")
 	// #endregion
 
 	cmd.Flags().String("ipfs-address", "", "multiaddr of IPFS API (default uses $IPFS_PATH env variable if defined or local directory ~/.ipfs)")
@@ -313,7 +324,32 @@ func processCreateCommandFlagsInRun(cmd *cobra.Command) (types.ContainerCreateOp
 	if err != nil {
 		return opt, err
 	}
+	return processRunFlags(cmd, opt)
+}
 
+// NewDefaultRunOptions returns the options of `nerdctl run` without any flag, i.e., the defaults of the flags,
+// for calling container.Create in-process with the same defaults as `nerdctl run`, e.g., from `nerdctl compose`.
+func NewDefaultRunOptions(globalOptions types.GlobalCommandOptions, nerdctlCmd string, nerdctlArgs []string, stdout, stderr io.Writer) (types.ContainerCreateOptions, types.NetworkOptions, error) {
+	cmd := RunCommand()
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	opt, err := createOptionsWithGlobalOptions(cmd, globalOptions, nerdctlCmd, nerdctlArgs)
+	if err != nil {
+		return opt, types.NetworkOptions{}, err
+	}
+	opt, err = processRunFlags(cmd, opt)
+	if err != nil {
+		return opt, types.NetworkOptions{}, err
+	}
+	netOpts, err := loadNetworkFlags(cmd)
+	if err != nil {
+		return opt, netOpts, fmt.Errorf("failed to load networking flags: %w", err)
+	}
+	return opt, netOpts, nil
+}
+
+func processRunFlags(cmd *cobra.Command, opt types.ContainerCreateOptions) (types.ContainerCreateOptions, error) {
+	var err error
 	opt.InRun = true
 
 	opt.SigProxy, err = cmd.Flags().GetBool("sig-proxy")
@@ -429,6 +465,9 @@ func runAction(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	logURI := lab[labels.LogURI]
+	if err := containerutil.MountDriverVolumes(ctx, c); err != nil {
+		return err
+	}
 	detachC := make(chan struct{})
 	task, err := taskutil.NewTask(ctx, client, c, createOpt.Attach, createOpt.Interactive, createOpt.TTY, createOpt.Detach,
 		con, logURI, createOpt.DetachKeys, createOpt.GOptions.Namespace, detachC)
@@ -438,6 +477,9 @@ func runAction(cmd *cobra.Command, args []string) error {
 	if err := task.Start(ctx); err != nil {
 		return err
 	}
+	if err := healthcheck.CreateTimer(ctx, c, (*config.Config)(&createOpt.GOptions)); err != nil {
+		log.L.WithError(err).Warnf("failed to schedule health checks for container %s", id)
+	}
 
 	if createOpt.Detach {
 		fmt.Fprintln(createOpt.Stdout, id)
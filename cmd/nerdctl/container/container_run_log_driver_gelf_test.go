@@ -0,0 +1,122 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/logging/gelf"
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+// startGelfServer listens on a random local port and sends the first message it receives to the returned channel.
+func startGelfServer(t *testing.T, network string) (string, <-chan *gelf.Message) {
+	received := make(chan *gelf.Message, 1)
+	decode := func(b []byte) {
+		m, err := gelf.Decode(b)
+		if err != nil {
+			t.Log(err)
+			return
+		}
+		received <- m
+	}
+	if network == "udp" {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		assert.NilError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		go func() {
+			buf := make([]byte, gelf.ChunkSize)
+			if n, _, err := conn.ReadFrom(buf); err == nil {
+				decode(buf[:n])
+			}
+		}()
+		return conn.LocalAddr().String(), received
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if b, err := bufio.NewReader(conn).ReadBytes(0); err == nil {
+			decode(b[:len(b)-1])
+		}
+	}()
+	return l.Addr().String(), received
+}
+
+func TestRunLogDriverGelf(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// The logger of rootless containers runs in the network namespace of RootlessKit
+	testCase.Require = require.All(require.Not(require.Windows), nerdtest.Rootful)
+
+	for _, network := range []string{"udp", "tcp"} {
+		var received <-chan *gelf.Message
+		testCase.SubTests = append(testCase.SubTests, &test.Case{
+			Description: network,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				var addr string
+				addr, received = startGelfServer(helpers.T(), network)
+				data.Labels().Set("address", network+"://"+addr)
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("rm", "-f", data.Identifier())
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--log-driver", "gelf",
+					"--log-opt", "gelf-address="+data.Labels().Get("address"),
+					"--log-opt", "labels=com.example.team",
+					"--log-opt", "env=FOO",
+					"--label", "com.example.team=infra",
+					"--env", "FOO=bar",
+					"--name", data.Identifier(), testutil.CommonImage, "echo", "hello gelf")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout, info string, t *testing.T) {
+						select {
+						case m := <-received:
+							assert.Equal(t, m.Version, gelf.Version, info)
+							assert.Equal(t, m.ShortMessage, "hello gelf", info)
+							assert.Equal(t, m.Level, gelf.LevelInfo, info)
+							assert.Equal(t, m.Extra["container_name"], data.Identifier(), info)
+							assert.Equal(t, m.Extra["com.example.team"], "infra", info)
+							assert.Equal(t, m.Extra["FOO"], "bar", info)
+						case <-time.After(10 * time.Second):
+							t.Fatal("timeout waiting for the gelf message")
+						}
+					},
+				}
+			},
+		})
+	}
+
+	testCase.Run(t)
+}
@@ -161,5 +161,25 @@ func loadNetworkFlags(cmd *cobra.Command) (types.NetworkOptions, error) {
 	}
 	netOpts.PortMappings = portMappings
 
+	// --expose=80/tcp ...
+	exposeSlice, err := cmd.Flags().GetStringSlice("expose")
+	if err != nil {
+		return netOpts, err
+	}
+	for _, e := range strutil.DedupeStrSlice(exposeSlice) {
+		ports, err := portutil.ParseFlagExpose(e)
+		if err != nil {
+			return netOpts, err
+		}
+		netOpts.Expose = append(netOpts.Expose, ports...)
+	}
+
+	// -P/--publish-all
+	publishAll, err := cmd.Flags().GetBool("publish-all")
+	if err != nil {
+		return netOpts, err
+	}
+	netOpts.PublishAll = publishAll
+
 	return netOpts, nil
 }
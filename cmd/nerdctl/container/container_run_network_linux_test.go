@@ -257,6 +257,40 @@ func TestRunPortWithNoHostPort(t *testing.T) {
 
 }
 
+func TestRunPublishAll(t *testing.T) {
+	if rootlessutil.IsRootless() {
+		t.Skip("Auto port assign is not supported rootless mode yet")
+	}
+	base := testutil.NewBase(t)
+	testContainerName := testutil.Identifier(t)
+	defer base.Cmd("rm", "-f", testContainerName).Run()
+
+	// the nginx image exposes 80/tcp
+	base.Cmd("run", "-d", "--name", testContainerName, "-P", "--expose", "8080", testutil.NginxAlpineImage).AssertOK()
+	portMapping := base.Cmd("port", testContainerName).Run().Combined()
+	assert.Assert(t, strings.Contains(portMapping, "8080/tcp ->"), portMapping)
+	hostPort, err := extractHostPort(portMapping, "80")
+	assert.NilError(t, err)
+
+	resp, err := nettestutil.HTTPGet(fmt.Sprintf("http://127.0.0.1:%s", hostPort), 30, false)
+	assert.NilError(t, err)
+	respBody, err := io.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(respBody), testutil.NginxAlpineIndexHTMLSnippet))
+}
+
+func TestRunExposeWithoutPublishAll(t *testing.T) {
+	base := testutil.NewBase(t)
+	testContainerName := testutil.Identifier(t)
+	defer base.Cmd("rm", "-f", testContainerName).Run()
+
+	base.Cmd("run", "-d", "--name", testContainerName, "--expose", "8080-8081/udp", testutil.NginxAlpineImage).AssertOK()
+	// the ports are exposed, along with the exposed port of the image, but not published
+	base.Cmd("inspect", "--format", "{{json .Config.ExposedPorts}}", testContainerName).
+		AssertOutExactly(`{"80/tcp":{},"8080/udp":{},"8081/udp":{}}` + "\n")
+	base.Cmd("port", testContainerName).AssertOutExactly("")
+}
+
 func TestUniqueHostPortAssignement(t *testing.T) {
 	if rootlessutil.IsRootless() {
 		t.Skip("Auto port assign is not supported rootless mode yet")
@@ -39,7 +39,6 @@ CMD ["echo", "nerdctl-build-test-string"]
 	var reg *registry.Server
 
 	testCase.Require = require.All(
-		require.Binary("cosign"),
 		require.Not(nerdtest.Docker),
 		nerdtest.Build,
 		nerdtest.Registry,
@@ -69,12 +68,12 @@ CMD ["echo", "nerdctl-build-test-string"]
 
 	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
 		helpers.Fail(
-			"run", "--rm", "--verify=cosign",
+			"run", "--rm", "--verify=cosign", "--cosign-insecure-ignore-tlog",
 			"--cosign-key=dummy",
 			data.Labels().Get("image_ref"))
 
 		return helpers.Command(
-			"run", "--rm", "--verify=cosign",
+			"run", "--rm", "--verify=cosign", "--cosign-insecure-ignore-tlog",
 			"--cosign-key="+data.Labels().Get("public_key"),
 			data.Labels().Get("image_ref"))
 	}
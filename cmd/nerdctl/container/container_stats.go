@@ -45,9 +45,13 @@ func StatsCommand() *cobra.Command {
 
 func addStatsFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolP("all", "a", false, "Show all containers (default shows just running)")
-	cmd.Flags().String("format", "", "Pretty-print images using a Go template, e.g, '{{json .}}'")
+	cmd.Flags().String("format", "", "Pretty-print images using a Go template, e.g, '{{json .}}', or 'json' for a stream of raw values")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "table"}, cobra.ShellCompDirectiveNoFileComp
+	})
 	cmd.Flags().Bool("no-stream", false, "Disable streaming stats and only pull the first result")
 	cmd.Flags().Bool("no-trunc", false, "Do not truncate output")
+	cmd.Flags().StringSliceP("filter", "f", nil, "Filter output based on conditions provided (id, name, label, project)")
 }
 
 func processStatsCommandFlags(cmd *cobra.Command) (types.ContainerStatsOptions, error) {
@@ -76,6 +80,11 @@ func processStatsCommandFlags(cmd *cobra.Command) (types.ContainerStatsOptions,
 		return types.ContainerStatsOptions{}, err
 	}
 
+	filters, err := cmd.Flags().GetStringSlice("filter")
+	if err != nil {
+		return types.ContainerStatsOptions{}, err
+	}
+
 	return types.ContainerStatsOptions{
 		Stdout:   cmd.OutOrStdout(),
 		Stderr:   cmd.ErrOrStderr(),
@@ -84,6 +93,7 @@ func processStatsCommandFlags(cmd *cobra.Command) (types.ContainerStatsOptions,
 		Format:   format,
 		NoStream: noStream,
 		NoTrunc:  noTrunc,
+		Filters:  filters,
 	}, nil
 }
 
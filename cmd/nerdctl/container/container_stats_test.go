@@ -20,10 +20,14 @@ import (
 	"runtime"
 	"testing"
 
+	"gotest.tools/v3/assert"
+
 	"github.com/containerd/nerdctl/mod/tigron/expect"
 	"github.com/containerd/nerdctl/mod/tigron/require"
 	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
 
+	"github.com/containerd/nerdctl/v2/pkg/statsutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
 )
@@ -50,10 +54,14 @@ func TestStats(t *testing.T) {
 	}
 
 	testCase.Setup = func(data test.Data, helpers test.Helpers) {
-		helpers.Ensure("run", "-d", "--name", data.Identifier("container"), testutil.CommonImage, "sleep", nerdtest.Infinity)
+		helpers.Ensure("run", "-d", "--name", data.Identifier("container"),
+			"--label", "com.docker.compose.project="+data.Identifier("project"),
+			testutil.CommonImage, "sleep", nerdtest.Infinity)
 		helpers.Ensure("run", "-d", "--name", data.Identifier("memlimited"), "--memory", "1g", testutil.CommonImage, "sleep", nerdtest.Infinity)
 		helpers.Ensure("run", "--name", data.Identifier("exited"), testutil.CommonImage, "echo", "'exited'")
 		data.Labels().Set("id", data.Identifier("container"))
+		data.Labels().Set("memlimited", data.Identifier("memlimited"))
+		data.Labels().Set("project", data.Identifier("project"))
 	}
 
 	testCase.SubTests = []*test.Case{
@@ -105,6 +113,58 @@ func TestStats(t *testing.T) {
 			},
 			Expected: test.Expects(0, nil, expect.Contains("1GiB")),
 		},
+		{
+			Description: "filter by name",
+			Require:     require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("stats", "--no-stream", "--filter", "name="+data.Labels().Get("memlimited"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Contains("1GiB"),
+						expect.DoesNotContain(data.Labels().Get("id")),
+					),
+				}
+			},
+		},
+		{
+			Description: "filter by compose project",
+			Require:     require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("stats", "--no-stream", "--filter", "project="+data.Labels().Get("project"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.All(
+						expect.Contains(data.Labels().Get("id")),
+						expect.DoesNotContain(data.Labels().Get("memlimited")),
+					),
+				}
+			},
+		},
+		{
+			Description: "invalid filter",
+			Require:     require.Not(nerdtest.Docker),
+			Command:     test.Command("stats", "--no-stream", "--filter", "volume=foo"),
+			Expected:    test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+		{
+			Description: "json stream of raw values",
+			Require:     require.Not(nerdtest.Docker),
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("stats", "--no-stream", "--format", "json", data.Labels().Get("memlimited"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.JSON(statsutil.StatsEntry{}, func(entry statsutil.StatsEntry, info string, t tig.T) {
+						assert.Equal(t, entry.Name, data.Labels().Get("memlimited"), info)
+						assert.Equal(t, entry.MemoryLimit, float64(1<<30), info)
+						assert.Assert(t, !entry.Read.IsZero(), info)
+					}),
+				}
+			},
+		},
 	}
 
 	testCase.Run(t)
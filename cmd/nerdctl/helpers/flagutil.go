@@ -23,6 +23,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 )
 
 func VerifyOptions(cmd *cobra.Command) (opt types.ImageVerifyOptions, err error) {
@@ -44,6 +45,9 @@ func VerifyOptions(cmd *cobra.Command) (opt types.ImageVerifyOptions, err error)
 	if opt.CosignCertificateOidcIssuerRegexp, err = cmd.Flags().GetString("cosign-certificate-oidc-issuer-regexp"); err != nil {
 		return
 	}
+	if opt.CosignInsecureIgnoreTlog, err = cmd.Flags().GetBool("cosign-insecure-ignore-tlog"); err != nil {
+		return
+	}
 	return
 }
 
@@ -77,6 +81,12 @@ func ValidateHealthcheckFlags(options types.ContainerCreateOptions) error {
 	if options.HealthStartInterval < 0 {
 		return fmt.Errorf("--health-start-interval cannot be negative")
 	}
+	if err := healthcheck.ValidateOnFailure(options.HealthOnFailure); err != nil {
+		return err
+	}
+	if options.NoHealthcheck && options.HealthOnFailure != "" && options.HealthOnFailure != healthcheck.OnFailureNone {
+		return fmt.Errorf("--no-healthcheck conflicts with --health-on-failure")
+	}
 	return nil
 }
 
@@ -145,6 +155,14 @@ func ProcessRootCmdFlags(cmd *cobra.Command) (types.GlobalCommandOptions, error)
 	if err != nil {
 		return types.GlobalCommandOptions{}, err
 	}
+	trustPolicy, err := cmd.Flags().GetString("trust-policy")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	usernsRemap, err := cmd.Flags().GetString("userns-remap")
+	if err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
 
 	// Point to dataRoot for filesystem-helpers implementing rollback / backups.
 	err = pkg.InitFS(dataRoot)
@@ -169,6 +187,8 @@ func ProcessRootCmdFlags(cmd *cobra.Command) (types.GlobalCommandOptions, error)
 		BridgeIP:         bridgeIP,
 		KubeHideDupe:     kubeHideDupe,
 		CDISpecDirs:      cdiSpecDirs,
+		TrustPolicy:      trustPolicy,
+		UsernsRemap:      usernsRemap,
 	}, nil
 }
 
@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -28,6 +27,7 @@ import (
 
 	"gotest.tools/v3/assert"
 
+	"github.com/containerd/nerdctl/v2/pkg/signutil/cosign"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil/nettestutil"
 )
@@ -42,15 +42,13 @@ func NewCosignKeyPair(t testing.TB, path string, password string) *CosignKeyPair
 	td, err := os.MkdirTemp(t.TempDir(), path)
 	assert.NilError(t, err)
 
-	cmd := exec.Command("cosign", "generate-key-pair")
-	cmd.Dir = td
-	cmd.Env = append(cmd.Env, fmt.Sprintf("COSIGN_PASSWORD=%s", password))
-	if out, err := cmd.CombinedOutput(); err != nil {
-		t.Fatalf("failed to run %v: %v (%q)", cmd.Args, err, string(out))
-	}
+	priPEM, pubPEM, err := cosign.GenerateKeyPair([]byte(password))
+	assert.NilError(t, err)
 
 	publicKey := filepath.Join(td, "cosign.pub")
 	privateKey := filepath.Join(td, "cosign.key")
+	assert.NilError(t, os.WriteFile(publicKey, pubPEM, 0o644))
+	assert.NilError(t, os.WriteFile(privateKey, priPEM, 0o600))
 
 	return &CosignKeyPair{
 		PublicKey:  publicKey,
@@ -40,6 +40,7 @@ func Command() *cobra.Command {
 		PullCommand(),
 		PushCommand(),
 		LoadCommand(),
+		ImportCommand(),
 		SaveCommand(),
 		TagCommand(),
 		imageRemoveCommand(),
@@ -0,0 +1,91 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/completion"
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func ImportCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:           "import [flags] file|URL|- [REPOSITORY[:TAG]]",
+		Short:         "Import the contents from a tarball to create a filesystem image",
+		Args:          cobra.RangeArgs(1, 2),
+		RunE:          importAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().StringArrayP("change", "c", nil, "Apply Dockerfile instruction to the created image (supported directives: [CMD, ENTRYPOINT, ENV, EXPOSE, LABEL, STOPSIGNAL, USER, VOLUME, WORKDIR])")
+	cmd.Flags().StringP("message", "m", "", "Set commit message for imported image")
+	cmd.Flags().String("platform", "", "Set platform if server is multi-platform capable")
+	cmd.RegisterFlagCompletionFunc("platform", completion.Platforms)
+	return cmd
+}
+
+func importOptions(cmd *cobra.Command, args []string) (types.ImageImportOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImageImportOptions{}, err
+	}
+	change, err := cmd.Flags().GetStringArray("change")
+	if err != nil {
+		return types.ImageImportOptions{}, err
+	}
+	message, err := cmd.Flags().GetString("message")
+	if err != nil {
+		return types.ImageImportOptions{}, err
+	}
+	platform, err := cmd.Flags().GetString("platform")
+	if err != nil {
+		return types.ImageImportOptions{}, err
+	}
+	var reference string
+	if len(args) > 1 {
+		reference = args[1]
+	}
+	return types.ImageImportOptions{
+		Stdout:    cmd.OutOrStdout(),
+		Stdin:     cmd.InOrStdin(),
+		GOptions:  globalOptions,
+		Source:    args[0],
+		Reference: reference,
+		Message:   message,
+		Change:    change,
+		Platform:  platform,
+	}, nil
+}
+
+func importAction(cmd *cobra.Command, args []string) error {
+	options, err := importOptions(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return image.Import(ctx, client, options)
+}
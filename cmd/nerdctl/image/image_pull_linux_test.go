@@ -17,6 +17,7 @@
 package image
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -46,7 +47,6 @@ CMD ["echo", "nerdctl-build-test-string"]
 		Require: require.All(
 			require.Linux,
 			nerdtest.Build,
-			require.Binary("cosign"),
 			require.Not(nerdtest.Docker),
 			nerdtest.Registry,
 		),
@@ -86,7 +86,7 @@ CMD ["echo", "nerdctl-build-test-string"]
 				Description: "Pull with the correct key",
 				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
 					return helpers.Command(
-						"pull", "--quiet", "--verify=cosign",
+						"pull", "--quiet", "--verify=cosign", "--cosign-insecure-ignore-tlog",
 						"--cosign-key="+data.Labels().Get("public_key"),
 						data.Labels().Get("image_ref")+":one")
 				},
@@ -99,10 +99,217 @@ CMD ["echo", "nerdctl-build-test-string"]
 				},
 				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
 					_, pub := nerdtest.GenerateCosignKeyPair(data, helpers, "2")
-					return helpers.Command("pull", "--quiet", "--verify=cosign", "--cosign-key="+pub, data.Labels().Get("image_ref")+":two")
+					return helpers.Command("pull", "--quiet", "--verify=cosign", "--cosign-insecure-ignore-tlog", "--cosign-key="+pub, data.Labels().Get("image_ref")+":two")
 				},
 				Expected: test.Expects(12, nil, nil),
 			},
+			{
+				Description: "Pull without ignoring the transparency log, which has no entry for the signatures of nerdctl",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command(
+						"pull", "--quiet", "--verify=cosign",
+						"--cosign-key="+data.Labels().Get("public_key"),
+						data.Labels().Get("image_ref")+":one")
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+// TestImagePullWithCosignBinaryRoundTrip checks that the signatures of the cosign executable are verified by nerdctl,
+// and that the signatures of nerdctl are verified by the cosign executable, with a key pair generated by cosign.
+func TestImagePullWithCosignBinaryRoundTrip(t *testing.T) {
+	dockerfile := fmt.Sprintf(`FROM %s
+CMD ["echo", "nerdctl-build-test-string"]
+	`, testutil.CommonImage)
+
+	nerdtest.Setup()
+
+	var reg *registry.Server
+
+	testCase := &test.Case{
+		Require: require.All(
+			require.Linux,
+			nerdtest.Build,
+			require.Binary("cosign"),
+			require.Not(nerdtest.Docker),
+			nerdtest.Registry,
+		),
+
+		Env: map[string]string{
+			"COSIGN_PASSWORD": "1",
+		},
+
+		Setup: func(data test.Data, helpers test.Helpers) {
+			data.Temp().Save(dockerfile, "Dockerfile")
+			data.Temp().Dir("cosign-key-pair")
+			cmd := helpers.Custom("cosign", "generate-key-pair")
+			cmd.WithCwd(data.Temp().Path("cosign-key-pair"))
+			cmd.Setenv("COSIGN_PASSWORD", "1")
+			cmd.Run(&test.Expected{})
+			pri, pub := data.Temp().Path("cosign-key-pair", "cosign.key"), data.Temp().Path("cosign-key-pair", "cosign.pub")
+
+			reg = nerdtest.RegistryWithNoAuth(data, helpers, 0, false)
+			reg.Setup(data, helpers)
+			testImageRef := fmt.Sprintf("%s:%d/%s", "127.0.0.1", reg.Port, data.Identifier())
+			buildCtx := data.Temp().Path()
+
+			helpers.Ensure("build", "-t", testImageRef+":signed-by-cosign", buildCtx)
+			helpers.Ensure("build", "-t", testImageRef+":signed-by-nerdctl", buildCtx)
+			helpers.Ensure("push", testImageRef+":signed-by-cosign")
+			helpers.Ensure("push", "--sign=cosign", "--cosign-key="+pri, testImageRef+":signed-by-nerdctl")
+			helpers.Ensure("rmi", "-f", testImageRef+":signed-by-cosign", testImageRef+":signed-by-nerdctl")
+
+			cmd = helpers.Custom("cosign", "sign", "--key", pri, "--tlog-upload=false", "--allow-insecure-registry", "--yes",
+				testImageRef+":signed-by-cosign")
+			cmd.Setenv("COSIGN_PASSWORD", "1")
+			cmd.Run(&test.Expected{})
+
+			data.Labels().Set("public_key", pub)
+			data.Labels().Set("image_ref", testImageRef)
+		},
+
+		Cleanup: func(data test.Data, helpers test.Helpers) {
+			if reg != nil {
+				reg.Cleanup(data, helpers)
+				testImageRef := data.Labels().Get("image_ref")
+				helpers.Anyhow("rmi", "-f", testImageRef+":signed-by-cosign", testImageRef+":signed-by-nerdctl")
+			}
+		},
+
+		SubTests: []*test.Case{
+			{
+				Description: "Verify the signature of cosign with nerdctl",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command(
+						"pull", "--quiet", "--verify=cosign", "--cosign-insecure-ignore-tlog",
+						"--cosign-key="+data.Labels().Get("public_key"),
+						data.Labels().Get("image_ref")+":signed-by-cosign")
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
+			{
+				Description: "Verify the signature of nerdctl with cosign",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Custom("cosign", "verify", "--key", data.Labels().Get("public_key"),
+						"--insecure-ignore-tlog=true", "--allow-insecure-registry",
+						data.Labels().Get("image_ref")+":signed-by-nerdctl")
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestImagePullWithTrustPolicy(t *testing.T) {
+	dockerfile := fmt.Sprintf(`FROM %s
+CMD ["echo", "nerdctl-build-test-string"]
+	`, testutil.CommonImage)
+
+	nerdtest.Setup()
+
+	var reg *registry.Server
+
+	testCase := &test.Case{
+		Require: require.All(
+			require.Linux,
+			nerdtest.Build,
+			require.Not(nerdtest.Docker),
+			nerdtest.Registry,
+		),
+
+		Env: map[string]string{
+			"COSIGN_PASSWORD": "1",
+		},
+
+		Setup: func(data test.Data, helpers test.Helpers) {
+			data.Temp().Save(dockerfile, "Dockerfile")
+			pri, pub := nerdtest.GenerateCosignKeyPair(data, helpers, "1")
+			reg = nerdtest.RegistryWithNoAuth(data, helpers, 0, false)
+			reg.Setup(data, helpers)
+			registryScope := fmt.Sprintf("127.0.0.1:%d", reg.Port)
+			testImageRef := fmt.Sprintf("%s/%s", registryScope, data.Identifier())
+			buildCtx := data.Temp().Path()
+
+			helpers.Ensure("build", "-t", testImageRef+":signed", buildCtx)
+			helpers.Ensure("build", "-t", testImageRef+":unsigned", buildCtx)
+			helpers.Ensure("push", "--sign=cosign", "--cosign-key="+pri, testImageRef+":signed")
+			helpers.Ensure("push", testImageRef+":unsigned")
+			helpers.Ensure("rmi", "-f", testImageRef+":signed", testImageRef+":unsigned")
+
+			policy := fmt.Sprintf(`{
+  "default": [{"type": "reject"}],
+  "transports": {
+    "docker": {
+      %q: [{"type": "signedBy", "keyPath": %q}]
+    }
+  }
+}`, registryScope, pub)
+			data.Labels().Set("policy", data.Temp().Save(policy, "policy.json"))
+			data.Labels().Set("image_ref", testImageRef)
+		},
+
+		Cleanup: func(data test.Data, helpers test.Helpers) {
+			if reg != nil {
+				reg.Cleanup(data, helpers)
+				testImageRef := data.Labels().Get("image_ref")
+				helpers.Anyhow("rmi", "-f", testImageRef+":signed")
+				helpers.Anyhow("rmi", "-f", testImageRef+":unsigned")
+			}
+		},
+
+		SubTests: []*test.Case{
+			{
+				Description: "Pull a signed image",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("--trust-policy="+data.Labels().Get("policy"),
+						"pull", "--quiet", data.Labels().Get("image_ref")+":signed")
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
+			{
+				Description: "Run a signed image by its tag after pulling it by its verified digest",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("--trust-policy="+data.Labels().Get("policy"),
+						"pull", "--quiet", data.Labels().Get("image_ref")+":signed")
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("run", "--rm", "--pull=never", data.Labels().Get("image_ref")+":signed")
+				},
+				Expected: test.Expects(0, nil, expect.Contains("nerdctl-build-test-string")),
+			},
+			{
+				Description: "Pull an unsigned image",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("--trust-policy="+data.Labels().Get("policy"),
+						"pull", "--quiet", data.Labels().Get("image_ref")+":unsigned")
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("denied by the image trust policy")}, nil),
+			},
+			{
+				Description: "Run an image rejected by default",
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("--trust-policy="+data.Labels().Get("policy"),
+						"run", "--rm", "--pull=always", testutil.CommonImage)
+				},
+				Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("rejected")}, nil),
+			},
+			{
+				Description: "Run a local image without enforcing the policy",
+				Setup: func(data test.Data, helpers test.Helpers) {
+					helpers.Ensure("pull", "--quiet", testutil.CommonImage)
+				},
+				Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+					return helpers.Command("--trust-policy="+data.Labels().Get("policy"),
+						"run", "--rm", "--pull=never", testutil.CommonImage, "true")
+				},
+				Expected: test.Expects(0, nil, nil),
+			},
 		},
 	}
 
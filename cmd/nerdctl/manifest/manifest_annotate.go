@@ -0,0 +1,95 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/manifest"
+)
+
+func annotateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "annotate [flags] MANIFEST_LIST MANIFEST",
+		Short:             "Set the platform of a manifest in a local manifest list",
+		Long:              "Set the platform of a manifest in a local manifest list.\nMANIFEST is either a local image, or a digest.",
+		Args:              helpers.IsExactArgs(2),
+		RunE:              annotateAction,
+		ValidArgsFunction: imageShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().String("os", "", "Set the operating system")
+	cmd.Flags().String("arch", "", "Set the architecture")
+	cmd.Flags().String("variant", "", "Set the architecture variant")
+	cmd.Flags().String("os-version", "", "Set the operating system version")
+	cmd.Flags().StringSlice("os-features", nil, "Set the operating system features")
+	return cmd
+}
+
+func annotateOptions(cmd *cobra.Command) (types.ManifestAnnotateOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.ManifestAnnotateOptions{}, err
+	}
+	osName, err := cmd.Flags().GetString("os")
+	if err != nil {
+		return types.ManifestAnnotateOptions{}, err
+	}
+	arch, err := cmd.Flags().GetString("arch")
+	if err != nil {
+		return types.ManifestAnnotateOptions{}, err
+	}
+	variant, err := cmd.Flags().GetString("variant")
+	if err != nil {
+		return types.ManifestAnnotateOptions{}, err
+	}
+	osVersion, err := cmd.Flags().GetString("os-version")
+	if err != nil {
+		return types.ManifestAnnotateOptions{}, err
+	}
+	osFeatures, err := cmd.Flags().GetStringSlice("os-features")
+	if err != nil {
+		return types.ManifestAnnotateOptions{}, err
+	}
+	return types.ManifestAnnotateOptions{
+		GOptions:   globalOptions,
+		OS:         osName,
+		Arch:       arch,
+		Variant:    variant,
+		OSVersion:  osVersion,
+		OSFeatures: osFeatures,
+	}, nil
+}
+
+func annotateAction(cmd *cobra.Command, args []string) error {
+	options, err := annotateOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return manifest.Annotate(ctx, client, args[0], args[1], options)
+}
@@ -0,0 +1,179 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest/registry"
+)
+
+// importPlatformImages imports a tiny root filesystem as the single-platform images `ref`:amd64 and `ref`:arm64.
+func importPlatformImages(data test.Data, helpers test.Helpers, ref string) {
+	data.Temp().Save("hello\n", "rootfs", "hello")
+	tarball := data.Temp().Path("rootfs.tar")
+	helpers.Custom("tar", "-C", data.Temp().Path("rootfs"), "-cf", tarball, ".").Run(&test.Expected{})
+	helpers.Ensure("import", "--platform", "linux/amd64", tarball, ref+":amd64")
+	helpers.Ensure("import", "--platform", "linux/arm64", tarball, ref+":arm64")
+}
+
+func TestManifest(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// docker manifest lists are not stored locally as images
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		importPlatformImages(data, helpers, data.Identifier())
+		helpers.Ensure("manifest", "create", data.Identifier()+":list", data.Identifier()+":amd64", data.Identifier()+":arm64")
+		data.Labels().Set("image", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rmi", "-f", data.Identifier()+":list", data.Identifier()+":amd64", data.Identifier()+":arm64")
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "inspect shows the platforms",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("manifest", "inspect", data.Labels().Get("image")+":list")
+			},
+			Expected: test.Expects(0, nil, expect.Contains(
+				`"mediaType": "application/vnd.oci.image.index.v1+json"`,
+				`"architecture": "amd64"`,
+				`"architecture": "arm64"`,
+			)),
+		},
+		{
+			Description: "inspect --verbose shows the manifests",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("manifest", "inspect", "--verbose", data.Labels().Get("image")+":list")
+			},
+			Expected: test.Expects(0, nil, expect.Contains(`"OCIManifest"`, `"layers"`)),
+		},
+		{
+			Description: "annotate sets the platform of a manifest",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("manifest", "annotate", "--variant", "v3",
+					data.Labels().Get("image")+":list", data.Labels().Get("image")+":amd64")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("manifest", "inspect", data.Labels().Get("image")+":list")
+			},
+			Expected: test.Expects(0, nil, expect.Contains(`"variant": "v3"`)),
+		},
+		{
+			Description: "create refuses to overwrite a manifest list without --amend",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("manifest", "create", data.Labels().Get("image")+":list", data.Labels().Get("image")+":amd64")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("--amend")}, nil),
+		},
+		{
+			Description: "create --amend replaces the manifest of the same platform",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("manifest", "create", "--amend", data.Labels().Get("image")+":list", data.Labels().Get("image")+":arm64")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("manifest", "inspect", "--verbose", data.Labels().Get("image")+":list")
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, info string, t *testing.T) {
+						assert.Equal(t, strings.Count(stdout, `"Ref"`), 2, info)
+					},
+				}
+			},
+		},
+		{
+			Description: "rm refuses images that are not manifest lists",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("manifest", "rm", data.Labels().Get("image")+":amd64")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+		{
+			Description: "rm removes the manifest list",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("manifest", "rm", data.Labels().Get("image")+":list")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("image", "inspect", data.Labels().Get("image")+":list")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+func TestManifestPush(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	var reg *registry.Server
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		nerdtest.Registry,
+	)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		reg = nerdtest.RegistryWithNoAuth(data, helpers, 0, false)
+		reg.Setup(data, helpers)
+		testImageRef := fmt.Sprintf("127.0.0.1:%d/%s", reg.Port, data.Identifier())
+		importPlatformImages(data, helpers, testImageRef)
+		helpers.Ensure("manifest", "create", testImageRef+":latest", testImageRef+":amd64", testImageRef+":arm64")
+		data.Labels().Set("image_ref", testImageRef)
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		if reg != nil {
+			reg.Cleanup(data, helpers)
+			testImageRef := data.Labels().Get("image_ref")
+			helpers.Anyhow("rmi", "-f", testImageRef+":latest", testImageRef+":amd64", testImageRef+":arm64")
+		}
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		helpers.Command("manifest", "push", "--quiet", "--purge", data.Labels().Get("image_ref")+":latest").
+			Run(&test.Expected{Output: expect.Contains("sha256:")})
+		// The manifest list was purged, so it is fetched from the registry
+		return helpers.Command("manifest", "inspect", data.Labels().Get("image_ref")+":latest")
+	}
+
+	testCase.Expected = test.Expects(0, nil, expect.Contains(`"architecture": "amd64"`, `"architecture": "arm64"`))
+
+	testCase.Run(t)
+}
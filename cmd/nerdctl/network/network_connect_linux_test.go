@@ -0,0 +1,99 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package network
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestNetworkConnectDisconnect(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("network", "create", data.Identifier("net"))
+		helpers.Ensure("run", "-d", "--name", data.Identifier(), testutil.CommonImage, "sleep", nerdtest.Infinity)
+		data.Labels().Set("net", data.Identifier("net"))
+		data.Labels().Set("container", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("network", "rm", data.Identifier("net"))
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "connect attaches a new interface to the running container",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("network", "connect", "--alias", "db", data.Labels().Get("net"), data.Labels().Get("container"))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Labels().Get("container"), "ip", "addr", "show", "dev", "eth1")
+			},
+			Expected: test.Expects(0, nil, expect.Contains("inet ")),
+		},
+		{
+			Description: "inspect shows the connected network",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("inspect", "--format", "{{json .NetworkSettings.Networks}}", data.Labels().Get("container"))
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Contains(data.Labels().Get("net")),
+				}
+			},
+		},
+		{
+			Description: "connecting twice fails",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("network", "connect", data.Labels().Get("net"), data.Labels().Get("container"))
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("already connected")}, nil),
+		},
+		{
+			Description: "disconnect removes the interface from the running container",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("network", "disconnect", data.Labels().Get("net"), data.Labels().Get("container"))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("exec", data.Labels().Get("container"), "ip", "addr", "show", "dev", "eth1")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+		{
+			Description: "disconnecting the only network fails",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("network", "disconnect", "bridge", data.Labels().Get("container"))
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("only network")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
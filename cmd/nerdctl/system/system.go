@@ -33,8 +33,10 @@ func Command() *cobra.Command {
 	}
 	// versionCommand is not here
 	cmd.AddCommand(
+		dfCommand(),
 		EventsCommand(),
 		InfoCommand(),
+		metricsCommand(),
 		pruneCommand(),
 	)
 	return cmd
@@ -0,0 +1,87 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestSystemDf(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// The output of the verbose tables differs with Docker
+	testCase.Require = require.Not(nerdtest.Docker)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("volume", "create", data.Identifier())
+		helpers.Ensure("run", "-v", data.Identifier()+":/mnt", "--name", data.Identifier(), testutil.CommonImage,
+			"sh", "-c", "echo hello > /mnt/file")
+		data.Labels().Set("volume", data.Identifier())
+		data.Labels().Set("container", data.Identifier())
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		helpers.Anyhow("volume", "rm", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "summary",
+			Command:     test.Command("system", "df"),
+			Expected: test.Expects(0, nil, expect.Contains(
+				"TYPE", "RECLAIMABLE", "Images", "Containers", "Local Volumes", "Build Cache")),
+		},
+		{
+			Description: "format",
+			Command:     test.Command("system", "df", "--format", "{{.Type}}"),
+			Expected:    test.Expects(0, nil, expect.Equals("Images\nContainers\nLocal Volumes\nBuild Cache\n")),
+		},
+		{
+			Description: "verbose",
+			Command:     test.Command("system", "df", "-v"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Contains(
+						"Images space usage:", "SHARED SIZE",
+						"Containers space usage:", data.Labels().Get("container"),
+						"Local Volumes space usage:", data.Labels().Get("volume"),
+						"Build cache usage:",
+					),
+				}
+			},
+		},
+		{
+			Description: "verbose with format",
+			Command:     test.Command("system", "df", "-v", "--format", "{{range .Volumes}}{{.Name}} {{.Links}}\n{{end}}"),
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: expect.Contains(data.Labels().Get("volume") + " 1\n"),
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
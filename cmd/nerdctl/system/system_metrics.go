@@ -0,0 +1,92 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/cmd/nerdctl/helpers"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/system"
+)
+
+func metricsCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:           "metrics",
+		Short:         "Export container metrics",
+		RunE:          helpers.UnknownSubcommandAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(metricsServeCommand())
+	return cmd
+}
+
+func metricsServeCommand() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:           "serve",
+		Args:          cobra.NoArgs,
+		Short:         "Serve the statistics of the running containers in the Prometheus text format",
+		RunE:          metricsServeAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("listen", "127.0.0.1:9323", "TCP address to serve the metrics on (path: /metrics)")
+	cmd.Flags().Bool("all-namespaces", false, "Export the containers of all the containerd namespaces")
+	return cmd
+}
+
+func metricsServeOptions(cmd *cobra.Command) (types.SystemMetricsServeOptions, error) {
+	globalOptions, err := helpers.ProcessRootCmdFlags(cmd)
+	if err != nil {
+		return types.SystemMetricsServeOptions{}, err
+	}
+	listen, err := cmd.Flags().GetString("listen")
+	if err != nil {
+		return types.SystemMetricsServeOptions{}, err
+	}
+	allNamespaces, err := cmd.Flags().GetBool("all-namespaces")
+	if err != nil {
+		return types.SystemMetricsServeOptions{}, err
+	}
+	return types.SystemMetricsServeOptions{
+		Stdout:        cmd.OutOrStdout(),
+		GOptions:      globalOptions,
+		Listen:        listen,
+		AllNamespaces: allNamespaces,
+	}, nil
+}
+
+func metricsServeAction(cmd *cobra.Command, args []string) error {
+	options, err := metricsServeOptions(cmd)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	client, ctx, cancel, err := clientutil.NewClient(ctx, options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return system.MetricsServe(ctx, client, options)
+}
@@ -0,0 +1,110 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/portlock"
+)
+
+func TestSystemMetricsServe(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	testCase.Require = require.All(
+		require.Not(nerdtest.Docker),
+		nerdtest.CgroupsAccessible,
+	)
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("run", "-d", "--name", data.Identifier(),
+			"--label", "com.docker.compose.project="+data.Identifier("project"),
+			testutil.CommonImage, "sleep", nerdtest.Infinity)
+		nerdtest.EnsureContainerStarted(helpers, data.Identifier())
+		port, err := portlock.Acquire(9323)
+		assert.NilError(helpers.T(), err)
+		data.Labels().Set("port", strconv.Itoa(port))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier())
+		if port, err := strconv.Atoi(data.Labels().Get("port")); err == nil {
+			_ = portlock.Release(port)
+		}
+	}
+
+	testCase.Command = func(data test.Data, helpers test.Helpers) test.TestableCommand {
+		addr := "127.0.0.1:" + data.Labels().Get("port")
+		cmd := helpers.Command("system", "metrics", "serve", "--listen", addr)
+		cmd.WithTimeout(20 * time.Second)
+		cmd.Background()
+
+		var body string
+		for range 30 {
+			resp, err := http.Get(fmt.Sprintf("http://%s/metrics", addr))
+			if err == nil {
+				b, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if resp.StatusCode == http.StatusOK {
+					body = string(b)
+					break
+				}
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+		data.Labels().Set("metrics", body)
+		return cmd
+	}
+
+	testCase.Expected = func(data test.Data, helpers test.Helpers) *test.Expected {
+		return &test.Expected{
+			ExitCode: expect.ExitCodeTimeout,
+			Output: expect.All(
+				expect.Contains("/metrics"),
+				func(stdout, info string, t *testing.T) {
+					metrics := data.Labels().Get("metrics")
+					for _, line := range strings.Split(metrics, "\n") {
+						if strings.HasPrefix(line, "nerdctl_container_pids{") &&
+							strings.Contains(line, `name="`+data.Identifier()+`"`) {
+							assert.Assert(t, strings.Contains(line, `compose_project="`+data.Identifier("project")+`"`), line)
+							assert.Assert(t, !strings.HasSuffix(line, " 0"), line)
+							return
+						}
+					}
+					t.Log("no pids series for the container in:\n" + metrics)
+					t.FailNow()
+				},
+			),
+		}
+	}
+
+	testCase.Run(t)
+}
@@ -18,6 +18,7 @@ package volume
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -38,6 +39,8 @@ func createCommand() *cobra.Command {
 		SilenceErrors: true,
 	}
 	cmd.Flags().StringArray("label", nil, "Set a label on the volume")
+	cmd.Flags().StringP("driver", "d", "local", "Specify volume driver name")
+	cmd.Flags().StringArrayP("opt", "o", nil, "Set driver specific options (type, device, o)")
 	return cmd
 }
 
@@ -56,9 +59,25 @@ func createOptions(cmd *cobra.Command) (types.VolumeCreateOptions, error) {
 		}
 	}
 
+	driver, err := cmd.Flags().GetString("driver")
+	if err != nil {
+		return types.VolumeCreateOptions{}, err
+	}
+	opts, err := cmd.Flags().GetStringArray("opt")
+	if err != nil {
+		return types.VolumeCreateOptions{}, err
+	}
+	for _, opt := range opts {
+		if !strings.Contains(opt, "=") {
+			return types.VolumeCreateOptions{}, fmt.Errorf("invalid option %q, expected key=value (%w)", opt, errdefs.ErrInvalidArgument)
+		}
+	}
+
 	return types.VolumeCreateOptions{
 		GOptions: globalOptions,
 		Labels:   labels,
+		Driver:   driver,
+		Options:  opts,
 		Stdout:   cmd.OutOrStdout(),
 	}, nil
 }
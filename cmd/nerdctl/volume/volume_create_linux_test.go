@@ -0,0 +1,83 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volume
+
+import (
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/require"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/nerdtest"
+)
+
+func TestVolumeCreateWithOptions(t *testing.T) {
+	testCase := nerdtest.Setup()
+
+	// Docker unmounts the volume when the last container using it stops, instead of when it is removed
+	testCase.Require = require.All(nerdtest.Rootful, require.Not(nerdtest.Docker))
+
+	testCase.Setup = func(data test.Data, helpers test.Helpers) {
+		helpers.Ensure("volume", "create", "--opt", "type=tmpfs", "--opt", "device=tmpfs", "--opt", "o=size=1m", data.Identifier())
+		helpers.Ensure("run", "--name", data.Identifier("writer"), "-v", data.Identifier()+":/mnt",
+			testutil.CommonImage, "sh", "-c", "echo hello > /mnt/file")
+		data.Labels().Set("volume", data.Identifier())
+		data.Labels().Set("writer", data.Identifier("writer"))
+	}
+
+	testCase.Cleanup = func(data test.Data, helpers test.Helpers) {
+		helpers.Anyhow("rm", "-f", data.Identifier("writer"))
+		helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "the volume is mounted with the given type",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "-v", data.Labels().Get("volume")+":/mnt",
+					testutil.CommonImage, "grep", " /mnt ", "/proc/mounts")
+			},
+			Expected: test.Expects(0, nil, expect.Contains("tmpfs")),
+		},
+		{
+			Description: "the content is shared while a container uses the volume",
+			NoParallel:  true,
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "-v", data.Labels().Get("volume")+":/mnt",
+					testutil.CommonImage, "cat", "/mnt/file")
+			},
+			Expected: test.Expects(0, nil, expect.Equals("hello\n")),
+		},
+		{
+			Description: "the volume is unmounted once no container uses it",
+			NoParallel:  true,
+			Setup: func(data test.Data, helpers test.Helpers) {
+				helpers.Ensure("rm", "-f", data.Labels().Get("writer"))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("run", "--rm", "-v", data.Labels().Get("volume")+":/mnt",
+					testutil.CommonImage, "ls", "/mnt")
+			},
+			Expected: test.Expects(0, nil, expect.DoesNotContain("file")),
+		},
+	}
+
+	testCase.Run(t)
+}
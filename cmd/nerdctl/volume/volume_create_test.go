@@ -21,6 +21,8 @@ import (
 	"regexp"
 	"testing"
 
+	"gotest.tools/v3/assert"
+
 	"github.com/containerd/errdefs"
 	"github.com/containerd/nerdctl/mod/tigron/expect"
 	"github.com/containerd/nerdctl/mod/tigron/test"
@@ -87,6 +89,44 @@ func TestVolumeCreate(t *testing.T) {
 			// NOTE: docker returns 125 on this
 			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errdefs.ErrInvalidArgument}, nil),
 		},
+		{
+			Description: "success with options",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "create", "--opt", "type=tmpfs", "--opt", "device=tmpfs", "--opt", "o=size=1m", data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+			},
+			Expected: func(data test.Data, helpers test.Helpers) *test.Expected {
+				return &test.Expected{
+					Output: func(stdout string, info string, t *testing.T) {
+						assert.Equal(t, stdout, data.Identifier()+"\n", info)
+						vol := nerdtest.InspectVolume(helpers, data.Identifier())
+						assert.DeepEqual(t, vol.Options, map[string]string{"type": "tmpfs", "device": "tmpfs", "o": "size=1m"})
+					},
+				}
+			},
+		},
+		{
+			Description: "unknown option should fail",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "create", "--opt", "foo=bar", data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errdefs.ErrInvalidArgument}, nil),
+		},
+		{
+			Description: "type option without device should fail",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("volume", "create", "--opt", "type=tmpfs", data.Identifier())
+			},
+			Cleanup: func(data test.Data, helpers test.Helpers) {
+				helpers.Anyhow("volume", "rm", "-f", data.Identifier())
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
 		{
 			Description: "creating already existing volume should succeed",
 			Setup: func(data test.Data, helpers test.Helpers) {
@@ -75,6 +75,15 @@ type BuilderBuildOptions struct {
 	ExtraHosts []string
 }
 
+// BuilderDiskUsageOptions specifies options for getting the disk usage of the build cache.
+type BuilderDiskUsageOptions struct {
+	Stderr io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// BuildKitHost is the buildkit host
+	BuildKitHost string
+}
+
 // BuilderPruneOptions specifies options for `nerdctl builder prune`.
 type BuilderPruneOptions struct {
 	Stderr io.Writer
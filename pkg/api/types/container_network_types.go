@@ -46,4 +46,8 @@ type NetworkOptions struct {
 	UTSNamespace string
 	// PortMappings specifies a list of ports to publish from the container to the host
 	PortMappings []cni.PortMapping
+	// Expose specifies a list of ports to expose in addition to the exposed ports of the image (e.g., 80/tcp)
+	Expose []string
+	// PublishAll publishes all the exposed ports to random host ports
+	PublishAll bool
 }
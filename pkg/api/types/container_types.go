@@ -32,6 +32,8 @@ type ContainerStartOptions struct {
 	DetachKeys string
 	// Attach stdin
 	Interactive bool
+	// Checkpoint is the checkpoint to restore the container from
+	Checkpoint string
 }
 
 // ContainerKillOptions specifies options for `nerdctl (container) kill`.
@@ -214,6 +216,8 @@ type ContainerCreateOptions struct {
 	Mount []string
 	// VolumesFrom specifies a list of specified containers to mount from
 	VolumesFrom []string
+	// VolumeDriver specifies the driver of the named volumes created by Volume
+	VolumeDriver string
 	// #endregion
 
 	// #region for rootfs flags
@@ -292,6 +296,8 @@ type ContainerCreateOptions struct {
 	HealthStartPeriod   time.Duration
 	HealthStartInterval time.Duration
 	NoHealthcheck       bool
+	// HealthOnFailure is the action to take when the container becomes unhealthy: none, kill, restart or stop
+	HealthOnFailure string
 
 	// UserNS name for user namespace mapping of container
 	UserNS string
@@ -379,6 +385,13 @@ type ContainerInspectOptions struct {
 	Mode string
 }
 
+// ContainerExportOptions specifies options for `nerdctl (container) export`.
+type ContainerExportOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+}
+
 // ContainerCommitOptions specifies options for `nerdctl (container) commit`.
 type ContainerCommitOptions struct {
 	Stdout io.Writer
@@ -511,10 +524,12 @@ type ContainerStatsOptions struct {
 	GOptions GlobalCommandOptions
 	// Show all containers (default shows just running).
 	All bool
-	// Pretty-print images using a Go template, e.g., {{json .}}.
+	// Pretty-print images using a Go template, e.g., {{json .}}, or "json" for a stream of raw values.
 	Format string
 	// Disable streaming stats and only pull the first result.
 	NoStream bool
 	// Do not truncate output.
 	NoTrunc bool
+	// Filter matches containers based on given conditions (id, name, label, project).
+	Filters []string
 }
@@ -255,6 +255,23 @@ type ImageSaveOptions struct {
 	Platform []string
 }
 
+// ImageImportOptions specifies options for `nerdctl (image) import`.
+type ImageImportOptions struct {
+	Stdout   io.Writer
+	Stdin    io.Reader
+	GOptions GlobalCommandOptions
+	// Source is the tarball of the root filesystem: a file path, an http(s) URL, or "-" for STDIN
+	Source string
+	// Reference is the name of the imported image, e.g. "example.com/foo:latest". The image is only named by its digest if empty.
+	Reference string
+	// Message sets the comment of the history entry of the imported image
+	Message string
+	// Change applies Dockerfile instructions to the imported image
+	Change []string
+	// Platform sets the platform of the imported image, e.g. "linux/arm64" (default: the host platform)
+	Platform string
+}
+
 // ImageSignOptions contains options for signing an image. It contains options from
 // all providers. The `provider` field determines which provider is used.
 type ImageSignOptions struct {
@@ -281,6 +298,8 @@ type ImageVerifyOptions struct {
 	CosignCertificateOidcIssuer string
 	// CosignCertificateOidcIssuerRegexp A regular expression alternative to --certificate-oidc-issuer for --verify=cosign. Accepts the Go regular expression syntax described at https://golang.org/s/re2syntax. Either --cosign-certificate-oidc-issuer or --cosign-certificate-oidc-issuer-regexp must be set for keyless flows
 	CosignCertificateOidcIssuerRegexp string
+	// CosignInsecureIgnoreTlog Ignore the transparency log (rekor) for --verify=cosign. The signatures made with a key file are verified without the cosign executable
+	CosignInsecureIgnoreTlog bool
 }
 
 // SociOptions contains options for SOCI.
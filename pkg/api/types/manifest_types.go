@@ -0,0 +1,65 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import "io"
+
+// ManifestCreateOptions specifies options for `nerdctl manifest create`.
+type ManifestCreateOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Amend adds the manifests to an existing manifest list
+	Amend bool
+}
+
+// ManifestAnnotateOptions specifies options for `nerdctl manifest annotate`.
+type ManifestAnnotateOptions struct {
+	GOptions GlobalCommandOptions
+	// OS sets the operating system of the manifest
+	OS string
+	// Arch sets the architecture of the manifest
+	Arch string
+	// Variant sets the architecture variant of the manifest
+	Variant string
+	// OSVersion sets the operating system version of the manifest
+	OSVersion string
+	// OSFeatures sets the operating system features of the manifest
+	OSFeatures []string
+}
+
+// ManifestInspectOptions specifies options for `nerdctl manifest inspect`.
+type ManifestInspectOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Verbose also shows the manifests of the platforms of a manifest list
+	Verbose bool
+}
+
+// ManifestPushOptions specifies options for `nerdctl manifest push`.
+type ManifestPushOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// Purge removes the local manifest list after pushing it
+	Purge bool
+	// Quiet suppresses the progress output
+	Quiet bool
+}
+
+// ManifestRemoveOptions specifies options for `nerdctl manifest rm`.
+type ManifestRemoveOptions struct {
+	GOptions GlobalCommandOptions
+}
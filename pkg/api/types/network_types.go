@@ -80,3 +80,27 @@ type NetworkRemoveOptions struct {
 	// Networks are the networks to be removed
 	Networks []string
 }
+
+// NetworkConnectOptions specifies options for `nerdctl network connect`.
+type NetworkConnectOptions struct {
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Network is the network to connect the container to
+	Network string
+	// Container is the container name, short ID, or long ID
+	Container string
+	// IPAddress is the IPv4 address to assign to the container on the network
+	IPAddress string
+	// Aliases are the network-scoped aliases of the container
+	Aliases []string
+}
+
+// NetworkDisconnectOptions specifies options for `nerdctl network disconnect`.
+type NetworkDisconnectOptions struct {
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Network is the network to disconnect the container from
+	Network string
+	// Container is the container name, short ID, or long ID
+	Container string
+}
@@ -41,6 +41,20 @@ type SystemEventsOptions struct {
 	Filters []string
 }
 
+// SystemDfOptions specifies options for `nerdctl system df`.
+type SystemDfOptions struct {
+	Stdout io.Writer
+	Stderr io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Verbose shows detailed information on space usage
+	Verbose bool
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+	// BuildKitHost the address of BuildKit host, the build cache is not reported if empty
+	BuildKitHost string
+}
+
 // SystemPruneOptions specifies options for `nerdctl system prune`.
 type SystemPruneOptions struct {
 	Stdout io.Writer
@@ -56,3 +70,14 @@ type SystemPruneOptions struct {
 	// NetworkDriversToKeep the network drivers which need to keep
 	NetworkDriversToKeep []string
 }
+
+// SystemMetricsServeOptions specifies options for `nerdctl system metrics serve`.
+type SystemMetricsServeOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Listen is the TCP address to serve the metrics on, e.g., "127.0.0.1:9323"
+	Listen string
+	// AllNamespaces exports the containers of all the containerd namespaces, not just the current one
+	AllNamespaces bool
+}
@@ -24,6 +24,10 @@ type VolumeCreateOptions struct {
 	GOptions GlobalCommandOptions
 	// Labels are the volume labels
 	Labels []string
+	// Driver is the volume driver, only "local" is supported
+	Driver string
+	// Options are the driver specific options, as "key=value" (e.g. "type=nfs", "device=:/path", "o=addr=10.0.0.1")
+	Options []string
 }
 
 // VolumeInspectOptions specifies options for `nerdctl volume inspect`.
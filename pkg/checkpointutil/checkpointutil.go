@@ -0,0 +1,211 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package checkpointutil manages container checkpoints (CRIU), stored as images in the containerd image store.
+//
+// A checkpoint image points to an OCI index with the checkpoint of the task, the rw layer of the container,
+// the runtime options and the image of the container. It can be pushed to and pulled from a registry like any image.
+package checkpointutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// ImageName returns the name of the image storing the checkpoint `checkpoint` of the container `containerID`.
+func ImageName(containerID, checkpoint string) (string, error) {
+	parsed, err := referenceutil.Parse(fmt.Sprintf("checkpoint/%s:%s", containerID, checkpoint))
+	if err != nil || parsed.ExplicitTag != checkpoint {
+		return "", fmt.Errorf("invalid checkpoint name %q: only [a-zA-Z0-9_.-] are allowed, and it must not start with '.' or '-'", checkpoint)
+	}
+	return parsed.String(), nil
+}
+
+// List returns the checkpoint images of the container `containerID`.
+func List(ctx context.Context, client *containerd.Client, containerID string) ([]images.Image, error) {
+	return client.ImageService().List(ctx, fmt.Sprintf("labels.%q==%s", labels.CheckpointContainer, containerID))
+}
+
+// Get returns the checkpoint image named `checkpoint` of the container `containerID`.
+func Get(ctx context.Context, client *containerd.Client, containerID, checkpoint string) (*images.Image, error) {
+	checkpoints, err := List(ctx, client, containerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range checkpoints {
+		if img.Labels[labels.CheckpointName] == checkpoint {
+			return &img, nil
+		}
+	}
+	return nil, fmt.Errorf("checkpoint %q does not exist for container %s: %w", checkpoint, containerID, errdefs.ErrNotFound)
+}
+
+// Resolve returns the checkpoint image to restore the container `containerID` from.
+// `checkpoint` is either the name of a checkpoint of the container, or the reference of a checkpoint image
+// (e.g., a checkpoint of a container on another host, pulled from a registry).
+func Resolve(ctx context.Context, client *containerd.Client, containerID, checkpoint string) (containerd.Image, error) {
+	img, err := Get(ctx, client, containerID, checkpoint)
+	if err == nil {
+		return containerd.NewImage(client, *img), nil
+	} else if !errdefs.IsNotFound(err) {
+		return nil, err
+	}
+	parsed, parseErr := referenceutil.Parse(checkpoint)
+	if parseErr != nil {
+		return nil, err
+	}
+	image, getErr := client.GetImage(ctx, parsed.String())
+	if getErr != nil {
+		if errdefs.IsNotFound(getErr) {
+			return nil, err
+		}
+		return nil, getErr
+	}
+	return image, nil
+}
+
+// Create checkpoints the running task of `container` into a new checkpoint image named `checkpoint`.
+// If `exit` is true, the task exits once the checkpoint is created.
+func Create(ctx context.Context, client *containerd.Client, container containerd.Container, checkpoint string, exit bool) (containerd.Image, error) {
+	ref, err := ImageName(container.ID(), checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := Get(ctx, client, container.ID(), checkpoint); err == nil {
+		return nil, fmt.Errorf("checkpoint %q already exists for container %s: %w", checkpoint, container.ID(), errdefs.ErrAlreadyExists)
+	} else if !errdefs.IsNotFound(err) {
+		return nil, err
+	}
+
+	opts := []containerd.CheckpointOpts{
+		containerd.WithCheckpointRuntime,
+		containerd.WithCheckpointImage,
+	}
+	if exit {
+		// Must precede WithCheckpointTask, which consumes the checkpoint options
+		opts = append(opts, containerd.WithCheckpointTaskExit)
+	}
+	// The rw layer is diffed after the task is dumped, so that it is consistent with the dump when the task exits
+	opts = append(opts, containerd.WithCheckpointTask, containerd.WithCheckpointRW)
+
+	img, err := container.Checkpoint(ctx, ref, opts...)
+	if err != nil {
+		return nil, err
+	}
+	metadata := img.Metadata()
+	metadata.Labels = map[string]string{
+		labels.CheckpointContainer: container.ID(),
+		labels.CheckpointName:      checkpoint,
+	}
+	updated, err := client.ImageService().Update(ctx, metadata, "labels")
+	if err != nil {
+		return nil, errors.Join(err, client.ImageService().Delete(ctx, ref))
+	}
+	return containerd.NewImage(client, updated), nil
+}
+
+// Remove removes the checkpoint image named `checkpoint` of the container `containerID`.
+func Remove(ctx context.Context, client *containerd.Client, containerID, checkpoint string) error {
+	img, err := Get(ctx, client, containerID, checkpoint)
+	if err != nil {
+		return err
+	}
+	return client.ImageService().Delete(ctx, img.Name, images.SynchronousDelete())
+}
+
+// RemoveAll removes all the checkpoint images of the container `containerID`.
+func RemoveAll(ctx context.Context, client *containerd.Client, containerID string) error {
+	checkpoints, err := List(ctx, client, containerID)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, img := range checkpoints {
+		if err := client.ImageService().Delete(ctx, img.Name); err != nil && !errdefs.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RestoreRW applies the rw layer stored in `checkpoint` to the rootfs of `container`,
+// so that the files of the container match the ones of the checkpointed task.
+func RestoreRW(ctx context.Context, client *containerd.Client, container containerd.Container, checkpoint containerd.Image) error {
+	index, err := readIndex(ctx, client, checkpoint)
+	if err != nil {
+		return err
+	}
+	rw, err := containerd.GetIndexByMediaType(index, ocispec.MediaTypeImageLayerGzip)
+	if err != nil {
+		if errors.Is(err, containerd.ErrMediaTypeNotFound) {
+			// The checkpoint does not have a rw layer
+			return nil
+		}
+		return err
+	}
+	info, err := container.Info(ctx)
+	if err != nil {
+		return err
+	}
+	mounts, err := client.SnapshotService(info.Snapshotter).Mounts(ctx, info.SnapshotKey)
+	if err != nil {
+		return err
+	}
+	if _, err := client.DiffService().Apply(ctx, *rw, mounts); err != nil {
+		return fmt.Errorf("failed to restore the rw layer of the checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Validate checks that `checkpoint` holds the checkpoint of a task.
+func Validate(ctx context.Context, client *containerd.Client, checkpoint containerd.Image) error {
+	index, err := readIndex(ctx, client, checkpoint)
+	if err != nil {
+		return err
+	}
+	if _, err := containerd.GetIndexByMediaType(index, images.MediaTypeContainerd1Checkpoint); err != nil {
+		return fmt.Errorf("image %s is not a checkpoint: %w", checkpoint.Name(), err)
+	}
+	return nil
+}
+
+func readIndex(ctx context.Context, client *containerd.Client, checkpoint containerd.Image) (*ocispec.Index, error) {
+	target := checkpoint.Target()
+	if !images.IsIndexType(target.MediaType) {
+		return nil, fmt.Errorf("image %s is not a checkpoint: unexpected media type %s", checkpoint.Name(), target.MediaType)
+	}
+	b, err := content.ReadBlob(ctx, client.ContentStore(), target)
+	if err != nil {
+		return nil, err
+	}
+	var index ocispec.Index
+	if err := json.Unmarshal(b, &index); err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
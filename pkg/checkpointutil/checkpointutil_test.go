@@ -0,0 +1,34 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package checkpointutil
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestImageName(t *testing.T) {
+	name, err := ImageName("0123abcd", "cp-1.0_a")
+	assert.NilError(t, err)
+	assert.Equal(t, name, "docker.io/checkpoint/0123abcd:cp-1.0_a")
+
+	for _, invalid := range []string{"", "-cp", ".cp", "cp/1", "cp:1", "cp@sha256:abc", "cp 1"} {
+		_, err := ImageName("0123abcd", invalid)
+		assert.ErrorContains(t, err, "invalid checkpoint name", "checkpoint name %q", invalid)
+	}
+}
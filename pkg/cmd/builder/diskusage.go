@@ -0,0 +1,72 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
+)
+
+// DiskUsage returns the records of the build cache.
+func DiskUsage(ctx context.Context, options types.BuilderDiskUsageOptions) ([]buildkitutil.UsageInfo, error) {
+	buildctlBinary, err := buildkitutil.BuildctlBinary()
+	if err != nil {
+		return nil, err
+	}
+	buildctlArgs := buildkitutil.BuildctlBaseArgs(options.BuildKitHost)
+	buildctlArgs = append(buildctlArgs, "du", "--format={{json .}}")
+	buildctlCmd := exec.Command(buildctlBinary, buildctlArgs...)
+	log.G(ctx).Debugf("running %v", buildctlCmd.Args)
+	buildctlCmd.Stderr = options.Stderr
+	stdout, err := buildctlCmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stdout pipe for %v: %w", buildctlCmd.Args, err)
+	}
+	defer stdout.Close()
+	if err = buildctlCmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %v: %w", buildctlCmd.Args, err)
+	}
+	dec := json.NewDecoder(stdout)
+	result := make([]buildkitutil.UsageInfo, 0)
+	for {
+		var v buildkitutil.UsageInfo
+		if err := dec.Decode(&v); err == io.EOF {
+			break
+		} else if err != nil {
+			// reap buildctl, which may still be running
+			if killErr := buildctlCmd.Process.Kill(); killErr != nil {
+				log.G(ctx).WithError(killErr).Debugf("failed to kill %v", buildctlCmd.Args)
+			}
+			_ = buildctlCmd.Wait()
+			return nil, fmt.Errorf("failed to decode output from %v: %w", buildctlCmd.Args, err)
+		}
+		result = append(result, v)
+	}
+	if err = buildctlCmd.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to wait for %v to complete: %w", buildctlCmd.Args, err)
+	}
+
+	return result, nil
+}
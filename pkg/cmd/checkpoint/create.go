@@ -0,0 +1,88 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/checkpointutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+)
+
+// Create checkpoints the running container `req` into the checkpoint `checkpoint`.
+// Unless options.LeaveRunning is set, the container is stopped once the checkpoint is created.
+func Create(ctx context.Context, client *containerd.Client, req, checkpoint string, options types.CheckpointCreateOptions) error {
+	container, err := findContainer(ctx, client, req)
+	if err != nil {
+		return err
+	}
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return fmt.Errorf("container %s is not running", req)
+		}
+		return err
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Status != containerd.Running {
+		return fmt.Errorf("container %s is not running (status: %s)", req, status.Status)
+	}
+
+	exit := !options.LeaveRunning
+	var exitCh <-chan containerd.ExitStatus
+	if exit {
+		// Prevent the restart manager from restarting the container when it exits after the checkpoint
+		if err := containerutil.UpdateExplicitlyStoppedLabel(ctx, container, true); err != nil {
+			return err
+		}
+		if exitCh, err = task.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	if _, err := checkpointutil.Create(ctx, client, container, checkpoint, exit); err != nil {
+		if exit {
+			err = errors.Join(err, containerutil.UpdateExplicitlyStoppedLabel(ctx, container, false))
+		}
+		return err
+	}
+
+	if exit {
+		if err := healthcheck.RemoveTimer(ctx, container.ID()); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to remove healthcheck timer for container %q", container.ID())
+		}
+		select {
+		case <-exitCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	_, err = fmt.Fprintln(options.Stdout, checkpoint)
+	return err
+}
@@ -0,0 +1,35 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"context"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/checkpointutil"
+)
+
+// Remove removes the checkpoint `checkpoint` of the container `req`.
+func Remove(ctx context.Context, client *containerd.Client, req, checkpoint string, options types.CheckpointRemoveOptions) error {
+	container, err := findContainer(ctx, client, req)
+	if err != nil {
+		return err
+	}
+	return checkpointutil.Remove(ctx, client, container.ID(), checkpoint)
+}
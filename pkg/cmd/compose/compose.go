@@ -30,15 +30,13 @@ import (
 	"github.com/containerd/platforms"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
 	"github.com/containerd/nerdctl/v2/pkg/composer"
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
-	"github.com/containerd/nerdctl/v2/pkg/imgutil"
-	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
-	"github.com/containerd/nerdctl/v2/pkg/ipfs"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
-	"github.com/containerd/nerdctl/v2/pkg/signutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
@@ -103,7 +101,7 @@ func New(client *containerd.Client, globalOptions types.GlobalCommandOptions, op
 		return true, nil
 	}
 
-	options.EnsureImage = func(ctx context.Context, imageName, pullMode, platform string, ps *serviceparser.Service, quiet bool) error {
+	options.EnsureImage = func(ctx context.Context, imageName, pullMode, platform string, ps *serviceparser.Service, quiet bool, progressOutput io.Writer) error {
 		ocispecPlatforms := []ocispec.Platform{platforms.DefaultSpec()}
 		if platform != "" {
 			parsed, err := platforms.Parse(platform)
@@ -122,40 +120,25 @@ func New(client *containerd.Client, globalOptions types.GlobalCommandOptions, op
 			RFlags:          types.RemoteSnapshotterFlags{},
 			Stdout:          stdout,
 			Stderr:          stderr,
+			VerifyOptions:   imageVerifyOptionsFromCompose(ps),
+			IPFSAddress:     options.IPFSAddress,
 		}
-
-		parsedReference, err := referenceutil.Parse(imageName)
-		if err != nil {
-			return err
+		if progressOutput != nil {
+			imgPullOpts.Stderr = progressOutput
 		}
 
-		if parsedReference.Protocol != "" {
-			var ipfsPath string
-			if ipfsAddress := options.IPFSAddress; ipfsAddress != "" {
-				dir, err := os.MkdirTemp("", "apidirtmp")
-				if err != nil {
-					return err
-				}
-				defer os.RemoveAll(dir)
-				if err := filesystem.WriteFile(filepath.Join(dir, "api"), []byte(ipfsAddress), 0600); err != nil {
-					return err
-				}
-				ipfsPath = dir
-			}
-			_, err = ipfs.EnsureImage(ctx, client, string(parsedReference.Protocol), parsedReference.String(), ipfsPath, imgPullOpts)
-			return err
-		}
-
-		imageVerifyOptions := imageVerifyOptionsFromCompose(ps)
-		ref, err := signutil.Verify(ctx, imageName, globalOptions.HostsDir, globalOptions.Experimental, imageVerifyOptions)
-		if err != nil {
-			return err
-		}
-
-		_, err = imgutil.EnsureImage(ctx, client, ref, imgPullOpts)
+		// the trust policy, the verification and IPFS are handled the same as `nerdctl pull`
+		_, err := image.EnsureImage(ctx, client, imageName, imgPullOpts)
 		return err
 	}
 
+	dataStore, err := clientutil.DataStore(globalOptions.DataRoot, globalOptions.Address)
+	if err != nil {
+		return nil, err
+	}
+	options.FilesDir = filepath.Join(dataStore, "compose", globalOptions.Namespace)
+	options.GlobalOptions = globalOptions
+
 	return composer.New(options, client)
 }
 
@@ -183,5 +166,8 @@ func imageVerifyOptionsFromCompose(ps *serviceparser.Service) types.ImageVerifyO
 	if coirVal, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateOidcIssuerRegexp]; ok {
 		opt.CosignCertificateOidcIssuerRegexp = coirVal.(string)
 	}
+	if itVal, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignInsecureIgnoreTlog].(bool); ok {
+		opt.CosignInsecureIgnoreTlog = itVal
+	}
 	return opt
 }
@@ -27,6 +27,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -59,14 +60,20 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/logging"
 	"github.com/containerd/nerdctl/v2/pkg/maputil"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/namestore"
+	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
 	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+	"github.com/containerd/nerdctl/v2/pkg/portutil"
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/store"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
+// DefaultInitBinary is the init binary used when InitProcessFlag is set without InitBinary.
+const DefaultInitBinary = "tini"
+
 // Create will create a container.
 func Create(ctx context.Context, client *containerd.Client, args []string, netManager containerutil.NetworkOptionsManager, options types.ContainerCreateOptions) (containerd.Container, func(), error) {
 	// Acquire an exclusive lock on the volume store until we are done to avoid being raced by any other
@@ -277,6 +284,15 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 	}
 	cOpts = append(cOpts, restartOpts...)
 
+	internalLabels.exposedPorts, err = exposedPorts(netManager.NetworkOptions(), ensuredImage)
+	if err != nil {
+		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
+	}
+	netManager, err = withPublishAll(netManager, internalLabels.exposedPorts, options.GOptions, client)
+	if err != nil {
+		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), err
+	}
+
 	if err = netManager.VerifyNetworkOptions(ctx); err != nil {
 		return nil, generateRemoveStateDirFunc(ctx, id, internalLabels), fmt.Errorf("failed to verify networking settings: %w", err)
 	}
@@ -343,6 +359,9 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 	if healthcheckConfig != "" {
 		internalLabels.healthcheck = healthcheckConfig
 	}
+	if options.HealthOnFailure != healthcheck.OnFailureNone {
+		internalLabels.healthOnFailure = options.HealthOnFailure
+	}
 
 	lCOpts, err := withContainerLabels(options.Label, options.LabelFile, ensuredImage)
 	if err != nil {
@@ -514,6 +533,9 @@ func generateRootfsOpts(args []string, id string, ensured *imgutil.EnsuredImage,
 
 	if options.InitBinary != nil {
 		options.InitProcessFlag = true
+	} else if options.InitProcessFlag {
+		initBinary := DefaultInitBinary
+		options.InitBinary = &initBinary
 	}
 	if options.InitProcessFlag {
 		binaryPath, err := exec.LookPath(*options.InitBinary)
@@ -690,6 +712,7 @@ type internalLabels struct {
 	ipAddress            string
 	ip6Address           string
 	ports                []cni.PortMapping
+	exposedPorts         []string
 	macAddress           string
 	dnsServers           []string
 	dnsSearchDomains     []string
@@ -718,7 +741,8 @@ type internalLabels struct {
 
 	user string
 
-	healthcheck string
+	healthcheck     string
+	healthOnFailure string
 }
 
 // WithInternalLabels sets the internal labels for a container.
@@ -748,6 +772,13 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 		}
 		m[labels.Ports] = string(portsJSON)
 	}
+	if len(internalLabels.exposedPorts) > 0 {
+		exposedPortsJSON, err := json.Marshal(internalLabels.exposedPorts)
+		if err != nil {
+			return nil, err
+		}
+		m[labels.ExposedPorts] = string(exposedPortsJSON)
+	}
 	if internalLabels.logURI != "" {
 		m[labels.LogURI] = internalLabels.logURI
 		logConfigJSON, err := json.Marshal(internalLabels.logConfig)
@@ -846,6 +877,10 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 		m[labels.HealthCheck] = internalLabels.healthcheck
 	}
 
+	if internalLabels.healthOnFailure != "" {
+		m[labels.HealthOnFailure] = internalLabels.healthOnFailure
+	}
+
 	return containerd.WithAdditionalContainerLabels(m), nil
 }
 
@@ -876,7 +911,11 @@ func withHealthcheck(options types.ContainerCreateOptions, ensuredImage *imgutil
 
 	// Apply CLI overrides
 	if options.HealthCmd != "" {
-		hc.Test = []string{"CMD-SHELL", options.HealthCmd}
+		test, err := healthcheck.ParseHealthCmd(options.HealthCmd)
+		if err != nil {
+			return "", err
+		}
+		hc.Test = test
 	}
 	if options.HealthInterval != 0 {
 		hc.Interval = options.HealthInterval
@@ -905,6 +944,49 @@ func withHealthcheck(options types.ContainerCreateOptions, ensuredImage *imgutil
 	return hcJSON, nil
 }
 
+// exposedPorts returns the exposed ports of the container in the "80/tcp" form, i.e.,
+// the exposed ports of the image, the ports of --expose and the published container ports.
+func exposedPorts(netOpts types.NetworkOptions, ensuredImage *imgutil.EnsuredImage) ([]string, error) {
+	exposed := append([]string{}, netOpts.Expose...)
+	if ensuredImage != nil {
+		for p := range ensuredImage.ImageConfig.ExposedPorts {
+			ports, err := portutil.ParseFlagExpose(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exposed port %q in the image config: %w", p, err)
+			}
+			exposed = append(exposed, ports...)
+		}
+	}
+	for _, pm := range netOpts.PortMappings {
+		exposed = append(exposed, fmt.Sprintf("%d/%s", pm.ContainerPort, pm.Protocol))
+	}
+	exposed = strutil.DedupeStrSlice(exposed)
+	sort.Strings(exposed)
+	return exposed, nil
+}
+
+// withPublishAll returns a network manager that also publishes the exposed ports
+// to random host ports when --publish-all is set.
+func withPublishAll(netManager containerutil.NetworkOptionsManager, exposed []string, globalOptions types.GlobalCommandOptions, client *containerd.Client) (containerutil.NetworkOptionsManager, error) {
+	netOpts := netManager.NetworkOptions()
+	if !netOpts.PublishAll {
+		return netManager, nil
+	}
+	// Like Docker, the published ports are discarded unless the container is connected to a CNI network
+	if netType, err := nettype.Detect(netOpts.NetworkSlice); err != nil || netType != nettype.CNI {
+		return netManager, err
+	}
+	portMappings, err := portutil.PublishAll(exposed, netOpts.PortMappings)
+	if err != nil {
+		return nil, err
+	}
+	if len(portMappings) == 0 {
+		return netManager, nil
+	}
+	netOpts.PortMappings = append(netOpts.PortMappings, portMappings...)
+	return containerutil.NewNetworkingOptionsManager(globalOptions, netOpts, client)
+}
+
 // loadNetOpts loads network options into InternalLabels.
 func (il *internalLabels) loadNetOpts(opts types.NetworkOptions) {
 	il.hostname = opts.Hostname
@@ -928,7 +1010,7 @@ func dockercompatMounts(mountPoints []*mountutil.Processed) []dockercompat.Mount
 			Name:        mp.Name,
 			Source:      mp.Mount.Source,
 			Destination: mp.Mount.Destination,
-			Driver:      "",
+			Driver:      mp.Driver,
 			Mode:        mp.Mode,
 		}
 		result[i].RW, result[i].Propagation = dockercompat.ParseMountProperties(strings.Split(mp.Mode, ","))
@@ -938,9 +1020,8 @@ func dockercompatMounts(mountPoints []*mountutil.Processed) []dockercompat.Mount
 			result[i].Name = mp.AnonymousVolume
 		}
 
-		// volume only support local driver
-		if mp.Type == "volume" {
-			result[i].Driver = "local"
+		if mp.Type == "volume" && result[i].Driver == "" {
+			result[i].Driver = volumestore.LocalDriver
 		}
 	}
 	return result
@@ -951,8 +1032,9 @@ func processeds(mountPoints []dockercompat.MountPoint) []*mountutil.Processed {
 	for i := range mountPoints {
 		mp := mountPoints[i]
 		result[i] = &mountutil.Processed{
-			Type: mp.Type,
-			Name: mp.Name,
+			Type:   mp.Type,
+			Name:   mp.Name,
+			Driver: mp.Driver,
 			Mount: specs.Mount{
 				Source:      mp.Source,
 				Destination: mp.Destination,
@@ -0,0 +1,96 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/tarutil"
+)
+
+// Export writes the root filesystem of a container as a tar archive.
+// Volumes and other mounts of the container are not included.
+func Export(ctx context.Context, client *containerd.Client, req string, options types.ContainerExportOptions) error {
+	walker := &containerwalker.ContainerWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found containerwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			return exportContainer(ctx, client, found.Container, options.Stdout)
+		},
+	}
+	n, err := walker.Walk(ctx, req)
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no such container %s", req)
+	}
+	return nil
+}
+
+func exportContainer(ctx context.Context, client *containerd.Client, container containerd.Container, w io.Writer) error {
+	info, err := container.Info(ctx)
+	if err != nil {
+		return err
+	}
+	if info.SnapshotKey == "" {
+		return fmt.Errorf("container %q has no snapshot", container.ID())
+	}
+	mounts, err := client.SnapshotService(info.Snapshotter).Mounts(ctx, info.SnapshotKey)
+	if err != nil {
+		return err
+	}
+	// The snapshot is mounted read-only, so that this also works while the container is running
+	return mount.WithReadonlyTempMount(ctx, mounts, func(root string) error {
+		return writeTar(ctx, root, w)
+	})
+}
+
+// writeTar writes the content of the directory root as a tar archive.
+func writeTar(ctx context.Context, root string, w io.Writer) error {
+	tarBinary, isGNUTar, err := tarutil.FindTarBinary()
+	if err != nil {
+		return err
+	}
+	args := []string{"-c", "-f", "-", "-C", root}
+	if isGNUTar {
+		// Keep the uids and gids of the container, regardless of the users of the host
+		args = append(args, "--numeric-owner")
+	}
+	args = append(args, ".")
+	tarCmd := exec.CommandContext(ctx, tarBinary, args...)
+	tarCmd.Stdout = w
+	var stderr bytes.Buffer
+	tarCmd.Stderr = &stderr
+	log.G(ctx).Debugf("executing %v", tarCmd.Args)
+	if err := tarCmd.Run(); err != nil {
+		return fmt.Errorf("failed to execute %v: %w (stderr=%q)", tarCmd.Args, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
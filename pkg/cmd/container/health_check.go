@@ -18,20 +18,35 @@ package container
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"syscall"
 	"time"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/runtime/restart"
+	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/config"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
-// HealthCheck executes the health check command for a container
-func HealthCheck(ctx context.Context, client *containerd.Client, container containerd.Container) error {
+// HealthCheck executes the health check command for a container, and takes the --health-on-failure action
+// of the container once it has turned unhealthy.
+func HealthCheck(ctx context.Context, client *containerd.Client, container containerd.Container, globalOptions types.GlobalCommandOptions) error {
 	// verify container status and get task
 	task, err := isContainerRunning(ctx, container)
 	if err != nil {
+		// The container exited on its own: stop the periodic health checks, unless the restart manager will bring it back.
+		if !willBeRestarted(ctx, container) {
+			if rmErr := healthcheck.RemoveTimer(ctx, container.ID()); rmErr != nil {
+				log.G(ctx).WithError(rmErr).Warnf("failed to remove healthcheck timer for container %s", container.ID())
+			}
+		}
 		return err
 	}
 
@@ -65,7 +80,53 @@ func HealthCheck(ctx context.Context, client *containerd.Client, container conta
 	}
 
 	// Execute the health check
-	return healthcheck.ExecuteHealthCheck(ctx, task, container, hcConfig)
+	hcErr := healthcheck.ExecuteHealthCheck(ctx, task, container, hcConfig)
+	if err := handleHealthOnFailure(ctx, client, task, container, globalOptions); err != nil {
+		return errors.Join(hcErr, err)
+	}
+	return hcErr
+}
+
+// handleHealthOnFailure takes the --health-on-failure action of the container if it is unhealthy.
+func handleHealthOnFailure(ctx context.Context, client *containerd.Client, task containerd.Task, container containerd.Container, globalOptions types.GlobalCommandOptions) error {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	action := lab[labels.HealthOnFailure]
+	if action == "" || action == healthcheck.OnFailureNone {
+		return nil
+	}
+	stateJSON, ok := lab[labels.HealthState]
+	if !ok {
+		return nil
+	}
+	state, err := healthcheck.HealthStateFromJSON(stateJSON)
+	if err != nil {
+		return fmt.Errorf("failed to parse health state: %w", err)
+	}
+	if state.Status != healthcheck.Unhealthy {
+		return nil
+	}
+
+	log.G(ctx).Infof("container %s is unhealthy, taking health-on-failure action %q", container.ID(), action)
+	switch action {
+	case healthcheck.OnFailureKill:
+		// The container is not marked as explicitly stopped, so that the restart manager applies its restart policy.
+		if err := healthcheck.ResetHealthState(ctx, container); err != nil {
+			return err
+		}
+		return task.Kill(ctx, syscall.SIGKILL)
+	case healthcheck.OnFailureStop:
+		return containerutil.Stop(ctx, container, nil, "")
+	case healthcheck.OnFailureRestart:
+		if err := containerutil.Stop(ctx, container, nil, ""); err != nil {
+			return err
+		}
+		return containerutil.Start(ctx, container, false, false, client, "", (*config.Config)(&globalOptions))
+	default:
+		return fmt.Errorf("unknown health-on-failure action %q", action)
+	}
 }
 
 func isContainerRunning(ctx context.Context, container containerd.Container) (containerd.Task, error) {
@@ -87,6 +148,29 @@ func isContainerRunning(ctx context.Context, container containerd.Container) (co
 	return task, nil
 }
 
+// willBeRestarted returns true if the restart manager is expected to restart the stopped container.
+func willBeRestarted(ctx context.Context, container containerd.Container) bool {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return false
+	}
+	if _, ok := lab[restart.PolicyLabel]; !ok || lab[restart.StatusLabel] != string(containerd.Running) {
+		return false
+	}
+	if explicitlyStopped, _ := strconv.ParseBool(lab[restart.ExplicitlyStoppedLabel]); explicitlyStopped {
+		return false
+	}
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		return true
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return true
+	}
+	return restart.Reconcile(status, lab)
+}
+
 // If configuredValue is zero, use defaultValue instead.
 func timeoutWithDefault(configuredValue time.Duration, defaultValue time.Duration) time.Duration {
 	if configuredValue == 0 {
@@ -57,10 +57,7 @@ type containerFilterContext struct {
 func (cl *containerFilterContext) MatchesFilters(ctx context.Context) []containerd.Container {
 	matchesContainers := make([]containerd.Container, 0, len(cl.containers))
 	for _, container := range cl.containers {
-		if !cl.matchesInfoFilters(ctx, container) {
-			continue
-		}
-		if !cl.matchesTaskFilters(ctx, container) {
+		if !cl.matches(ctx, container) {
 			continue
 		}
 		matchesContainers = append(matchesContainers, container)
@@ -69,6 +66,10 @@ func (cl *containerFilterContext) MatchesFilters(ctx context.Context) []containe
 	return cl.containers
 }
 
+func (cl *containerFilterContext) matches(ctx context.Context, container containerd.Container) bool {
+	return cl.matchesInfoFilters(ctx, container) && cl.matchesTaskFilters(ctx, container)
+}
+
 func (cl *containerFilterContext) foldFilters(ctx context.Context, filters []string) error {
 	folders := []struct {
 		filterType string
@@ -31,12 +31,17 @@ import (
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/checkpointutil"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
 	"github.com/containerd/nerdctl/v2/pkg/ipcutil"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/namestore"
 	"github.com/containerd/nerdctl/v2/pkg/store"
@@ -229,6 +234,16 @@ func RemoveContainer(ctx context.Context, c containerd.Container, globalOptions
 			}
 		}
 
+		// Stop periodic health checks - soft failure
+		if err := healthcheck.RemoveTimer(ctx, id); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to remove healthcheck timer for container %q", id)
+		}
+
+		// Remove the checkpoints of the container - soft failure
+		if err := checkpointutil.RemoveAll(ctx, client, id); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to remove checkpoints of container %q", id)
+		}
+
 		hs, err := hostsstore.New(dataStore, containerNamespace)
 		if err != nil {
 			log.G(ctx).WithError(err).Warnf("failed to instantiate hostsstore for %q", containerNamespace)
@@ -256,6 +271,26 @@ func RemoveContainer(ctx context.Context, c containerd.Container, globalOptions
 				}
 			}
 		}
+
+		// Release the volumes of volume drivers, and unmount the local volumes created with options
+		// (e.g. `--opt type=nfs`) that are no longer used - soft failure
+		if mountsJSON, ok := containerLabels[labels.Mounts]; ok {
+			var mounts []dockercompat.MountPoint
+			if err = json.Unmarshal([]byte(mountsJSON), &mounts); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to unmarshall mount information for container %q", id)
+			} else {
+				containerutil.ReleaseDriverVolumes(ctx, id, containerLabels)
+				var volumes []string
+				for _, m := range mounts {
+					if m.Type == mountutil.Volume && (m.Driver == "" || m.Driver == volumestore.LocalDriver) {
+						volumes = append(volumes, m.Name)
+					}
+				}
+				if err = volume.UnmountUnused(ctx, client, volStore, volumes); err != nil {
+					log.G(ctx).WithError(err).Warnf("failed to unmount volumes of container %q", id)
+				}
+			}
+		}
 	}()
 
 	// Get the task.
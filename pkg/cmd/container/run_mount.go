@@ -48,6 +48,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumedriver"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
@@ -94,9 +95,18 @@ func withMounts(mounts []specs.Mount) oci.SpecOpts {
 // parseMountFlags parses --volume, --mount and --tmpfs.
 func parseMountFlags(volStore volumestore.VolumeStore, options types.ContainerCreateOptions) ([]*mountutil.Processed, error) {
 	var parsed []*mountutil.Processed //nolint:prealloc
+	var createOpts []volumestore.CreateOpt
+	if options.VolumeDriver != "" && options.VolumeDriver != volumestore.LocalDriver {
+		createOpts = append(createOpts, volumestore.WithDriver(options.VolumeDriver, nil))
+	}
 	for _, v := range strutil.DedupeStrSlice(options.Volume) {
+		if name, ok := mountutil.NamedVolume(v); ok && len(createOpts) > 0 {
+			if err := createDriverVolume(volStore, name, options.VolumeDriver); err != nil {
+				return nil, err
+			}
+		}
 		// createDir=true for -v option to allow creation of directory on host if not found.
-		x, err := mountutil.ProcessFlagV(v, volStore, true)
+		x, err := mountutil.ProcessFlagV(v, volStore, true, createOpts...)
 		if err != nil {
 			return nil, err
 		}
@@ -112,7 +122,12 @@ func parseMountFlags(volStore volumestore.VolumeStore, options types.ContainerCr
 	}
 
 	for _, v := range strutil.DedupeStrSlice(options.Mount) {
-		x, err := mountutil.ProcessFlagMount(v, volStore)
+		if name, ok := mountutil.NamedVolumeOfMount(v); ok && len(createOpts) > 0 {
+			if err := createDriverVolume(volStore, name, options.VolumeDriver); err != nil {
+				return nil, err
+			}
+		}
+		x, err := mountutil.ProcessFlagMount(v, volStore, createOpts...)
 		if err != nil {
 			return nil, err
 		}
@@ -122,6 +137,18 @@ func parseMountFlags(volStore volumestore.VolumeStore, options types.ContainerCr
 	return parsed, nil
 }
 
+// createDriverVolume creates the named volume with the given volume driver, unless the volume exists already.
+func createDriverVolume(volStore volumestore.VolumeStore, name, driverName string) error {
+	if exists, err := volStore.Exists(name); err != nil || exists {
+		return err
+	}
+	driver, err := volumedriver.Get(driverName)
+	if err != nil {
+		return err
+	}
+	return driver.Create(name, nil)
+}
+
 // generateMountOpts generates volume-related mount opts.
 // Other mounts such as procfs mount are not handled here.
 func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredImage *imgutil.EnsuredImage,
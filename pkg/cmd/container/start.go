@@ -23,6 +23,8 @@ import (
 	containerd "github.com/containerd/containerd/v2/client"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/checkpointutil"
+	"github.com/containerd/nerdctl/v2/pkg/config"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 )
@@ -32,6 +34,9 @@ func Start(ctx context.Context, client *containerd.Client, reqs []string, option
 	if options.Attach && len(reqs) > 1 {
 		return fmt.Errorf("you cannot start and attach multiple containers at once")
 	}
+	if options.Checkpoint != "" && len(reqs) > 1 {
+		return fmt.Errorf("you cannot restore multiple containers at once")
+	}
 
 	walker := &containerwalker.ContainerWalker{
 		Client: client,
@@ -40,7 +45,15 @@ func Start(ctx context.Context, client *containerd.Client, reqs []string, option
 			if found.MatchCount > 1 {
 				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
 			}
-			if err := containerutil.Start(ctx, found.Container, options.Attach, options.Interactive, client, options.DetachKeys); err != nil {
+			var taskOpts []containerd.NewTaskOpts
+			if options.Checkpoint != "" {
+				opt, err := restoreCheckpoint(ctx, client, found.Container, options.Checkpoint)
+				if err != nil {
+					return err
+				}
+				taskOpts = append(taskOpts, opt)
+			}
+			if err := containerutil.Start(ctx, found.Container, options.Attach, options.Interactive, client, options.DetachKeys, (*config.Config)(&options.GOptions), taskOpts...); err != nil {
 				return err
 			}
 			if !options.Attach {
@@ -55,3 +68,22 @@ func Start(ctx context.Context, client *containerd.Client, reqs []string, option
 
 	return walker.WalkAll(ctx, reqs, true)
 }
+
+// restoreCheckpoint restores the rootfs of `container` from `checkpoint`, and returns the option
+// to restore its task from the checkpoint.
+func restoreCheckpoint(ctx context.Context, client *containerd.Client, container containerd.Container, checkpoint string) (containerd.NewTaskOpts, error) {
+	if status, err := containerutil.ContainerStatus(ctx, container); err == nil && status.Status == containerd.Running {
+		return nil, fmt.Errorf("container %s is already running, cannot restore checkpoint %q", container.ID(), checkpoint)
+	}
+	img, err := checkpointutil.Resolve(ctx, client, container.ID(), checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkpointutil.Validate(ctx, client, img); err != nil {
+		return nil, err
+	}
+	if err := checkpointutil.RestoreRW(ctx, client, container, img); err != nil {
+		return nil, err
+	}
+	return containerd.WithTaskCheckpoint(img), nil
+}
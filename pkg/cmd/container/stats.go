@@ -19,6 +19,7 @@ package container
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -42,6 +43,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 	"github.com/containerd/nerdctl/v2/pkg/infoutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
 	"github.com/containerd/nerdctl/v2/pkg/statsutil"
 )
@@ -94,12 +96,18 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 
 	var err error
 	w := options.Stdout
-	var tmpl *template.Template
+	var (
+		tmpl       *template.Template
+		jsonFormat bool
+	)
 	switch options.Format {
 	case "", "table":
 		w = tabwriter.NewWriter(options.Stdout, 10, 1, 3, ' ', 0)
 	case "raw":
 		return errors.New("unsupported format: \"raw\"")
+	case "json":
+		// Unlike '{{json .}}', stream the raw values rather than the humanized strings
+		jsonFormat = true
 	default:
 		tmpl, err = formatter.ParseTemplate(options.Format)
 		if err != nil {
@@ -107,6 +115,14 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 		}
 	}
 
+	filterCtx, err := foldStatsFilters(ctx, options.Filters)
+	if err != nil {
+		return err
+	}
+	matchesFilters := func(c containerd.Container) bool {
+		return filterCtx == nil || filterCtx.matches(ctx, c)
+	}
+
 	// waitFirst is a WaitGroup to wait first stat data's reach for each container
 	waitFirst := &sync.WaitGroup{}
 	cStats := stats{}
@@ -147,6 +163,9 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 					continue
 				}
 			}
+			if !matchesFilters(c) {
+				continue
+			}
 			// if an error occurs when getting labels, the ID alone is sufficient for the stats screen.
 			clabels, _ := c.Labels(ctx)
 			s := statsutil.NewStats(c.ID(), containerutil.GetContainerName(clabels))
@@ -182,8 +201,14 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 			}
 			// Load the container to get its ID for retrieving the container name from Labels.
 			// if an error occurs, the ID alone is sufficient for the stats screen.
-			container, _ := client.LoadContainer(ctx, datacc.ID)
-			clabels, _ := container.Labels(ctx)
+			container, err := client.LoadContainer(ctx, datacc.ID)
+			if err == nil && !matchesFilters(container) {
+				return
+			}
+			var clabels map[string]string
+			if container != nil {
+				clabels, _ = container.Labels(ctx)
+			}
 			s := statsutil.NewStats(datacc.ID, containerutil.GetContainerName(clabels))
 			if cStats.add(s) {
 				waitFirst.Add(1)
@@ -232,6 +257,9 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 		walker := &containerwalker.ContainerWalker{
 			Client: client,
 			OnFound: func(ctx context.Context, found containerwalker.Found) error {
+				if !matchesFilters(found.Container) {
+					return nil
+				}
 				// if an error occurs when getting labels, the ID alone is sufficient for the stats screen.
 				clabels, _ := found.Container.Labels(ctx)
 				s := statsutil.NewStats(found.Container.ID(), containerutil.GetContainerName(clabels))
@@ -253,7 +281,8 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 	}
 
 	cleanScreen := func() {
-		if !options.NoStream {
+		// the json stream is meant to be consumed by programs
+		if !options.NoStream && !jsonFormat {
 			fmt.Fprint(options.Stdout, "\033[2J")
 			fmt.Fprint(options.Stdout, "\033[H")
 		}
@@ -290,7 +319,15 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 			}
 			rc := statsutil.RenderEntry(&c, options.NoTrunc)
 			if !firstTick {
-				if tmpl != nil {
+				if jsonFormat {
+					b, err := json.Marshal(c)
+					if err != nil {
+						break
+					}
+					if _, err = fmt.Fprintln(options.Stdout, string(b)); err != nil {
+						break
+					}
+				} else if tmpl != nil {
 					var b bytes.Buffer
 					if err := tmpl.Execute(&b, rc); err != nil {
 						break
@@ -340,6 +377,31 @@ func Stats(ctx context.Context, client *containerd.Client, containerIDs []string
 	return err
 }
 
+// foldStatsFilters folds the "id", "name" and "label" filters of `nerdctl ps`,
+// and the "project" filter, i.e., "label=com.docker.compose.project=<project>".
+// It returns nil when there is no filter.
+func foldStatsFilters(ctx context.Context, filters []string) (*containerFilterContext, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	folded := make([]string, 0, len(filters))
+	for _, f := range filters {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid argument \"%s\" for \"-f, --filter\": bad format of filter (expected name=value)", f)
+		}
+		switch k {
+		case "id", "name", "label":
+			folded = append(folded, f)
+		case "project":
+			folded = append(folded, "label="+labels.ComposeProject+"="+v)
+		default:
+			return nil, fmt.Errorf("invalid filter '%s'", f)
+		}
+	}
+	return foldContainerFilters(ctx, nil, folded)
+}
+
 func collect(ctx context.Context, globalOptions types.GlobalCommandOptions, s *statsutil.Stats, waitFirst *sync.WaitGroup, id string, noStream bool) {
 	log.G(ctx).Debugf("collecting stats for %s", s.ID)
 	var (
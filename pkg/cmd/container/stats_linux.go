@@ -21,14 +21,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"net"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/vishvananda/netlink"
-	"github.com/vishvananda/netns"
 
 	v1 "github.com/containerd/cgroups/v3/cgroup1/stats"
 	v2 "github.com/containerd/cgroups/v3/cgroup2/stats"
@@ -67,44 +65,10 @@ func setContainerStatsAndRenderStatsEntry(previousStats *statsutil.ContainerStat
 	var nlinks []netlink.Link
 
 	if !firstSet {
-		var (
-			nlink    netlink.Link
-			nlHandle *netlink.Handle
-			ns       netns.NsHandle
-		)
-
-		ns, err = netns.GetFromPid(pid)
-		if err != nil {
-			err = fmt.Errorf("failed to retrieve the statistics in netns %s: %v", ns, err)
-			return
-		}
-		defer func() {
-			err = ns.Close()
-		}()
-
-		nlHandle, err = netlink.NewHandleAt(ns)
+		nlinks, err = statsutil.NetworkLinks(pid, interfaces)
 		if err != nil {
-			err = fmt.Errorf("failed to retrieve the statistics in netns %s: %v", ns, err)
 			return
 		}
-		defer nlHandle.Close()
-
-		for _, v := range interfaces {
-			nlink, err = nlHandle.LinkByIndex(v.Index)
-			if err != nil {
-				err = fmt.Errorf("failed to retrieve the statistics for %s in netns %s: %v", v.Name, ns, err)
-				return
-			}
-			//exclude inactive interface
-			if nlink.Attrs().Flags&net.FlagUp != 0 {
-
-				//exclude loopback interface
-				if nlink.Attrs().Flags&net.FlagLoopback != 0 || strings.HasPrefix(nlink.Attrs().Name, "lo") {
-					continue
-				}
-				nlinks = append(nlinks, nlink)
-			}
-		}
 	}
 
 	if data != nil {
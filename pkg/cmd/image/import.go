@@ -0,0 +1,68 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/importer"
+)
+
+// Import creates an image from the tarball of a root filesystem, read from a file, a URL, or STDIN.
+func Import(ctx context.Context, client *containerd.Client, options types.ImageImportOptions) error {
+	var r io.Reader
+	switch {
+	case options.Source == "-":
+		r = options.Stdin
+	case strings.HasPrefix(options.Source, "http://") || strings.HasPrefix(options.Source, "https://"):
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, options.Source, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("failed to download %q: %s", options.Source, resp.Status)
+		}
+		r = resp.Body
+	default:
+		f, err := os.Open(options.Source)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	imageID, err := importer.Import(ctx, client, r, options)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(options.Stdout, imageID)
+	return err
+}
@@ -23,13 +23,17 @@ import (
 	"path/filepath"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/errdefs"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
 	"github.com/containerd/nerdctl/v2/pkg/ipfs"
+	"github.com/containerd/nerdctl/v2/pkg/platformutil"
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 	"github.com/containerd/nerdctl/v2/pkg/signutil"
+	"github.com/containerd/nerdctl/v2/pkg/trustpolicy"
 )
 
 // Pull pulls an image specified by `rawRef`.
@@ -51,6 +55,26 @@ func EnsureImage(ctx context.Context, client *containerd.Client, rawRef string,
 		return nil, err
 	}
 
+	// The trust policy is enforced when the image is pulled, so that the local images can be used
+	// without contacting the registry, e.g., offline or with --pull=never.
+	// The images on IPFS are always pulled, and are only allowed by the requirements that do not verify signatures.
+	ref := rawRef
+	if options.GOptions.TrustPolicy != "" {
+		pull := true
+		if parsedReference.Protocol == "" {
+			pull, err = needsPull(ctx, client, rawRef, options)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if pull {
+			ref, err = trustpolicy.Enforce(ctx, rawRef, options.GOptions)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	if parsedReference.Protocol != "" {
 		if options.VerifyOptions.Provider != "none" {
 			return nil, errors.New("--verify flag is not supported on IPFS as of now")
@@ -76,7 +100,7 @@ func EnsureImage(ctx context.Context, client *containerd.Client, rawRef string,
 		return ensured, nil
 	}
 
-	ref, err := signutil.Verify(ctx, rawRef, options.GOptions.HostsDir, options.GOptions.Experimental, options.VerifyOptions)
+	ref, err = signutil.Verify(ctx, ref, options.GOptions.HostsDir, options.GOptions.Experimental, options.VerifyOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -85,5 +109,61 @@ func EnsureImage(ctx context.Context, client *containerd.Client, rawRef string,
 	if err != nil {
 		return nil, err
 	}
+	if ref != rawRef {
+		if err := tagPinnedImage(ctx, client, ensured, rawRef, options); err != nil {
+			return nil, err
+		}
+	}
 	return ensured, err
 }
+
+// tagPinnedImage stores the image pulled by its verified digest also under the reference `rawRef` given by the user,
+// so that it can be used by that reference afterwards, e.g., `nerdctl run alpine:3.21` after `nerdctl pull alpine:3.21`.
+func tagPinnedImage(ctx context.Context, client *containerd.Client, ensured *imgutil.EnsuredImage, rawRef string, options types.ImagePullOptions) error {
+	parsedReference, err := referenceutil.Parse(rawRef)
+	if err != nil {
+		return err
+	}
+	name := parsedReference.String()
+	if ensured.Image.Name() == name {
+		return nil
+	}
+	img := images.Image{
+		Name:   name,
+		Target: ensured.Image.Target(),
+		Labels: ensured.Image.Labels(),
+	}
+	imageService := client.ImageService()
+	if _, err := imageService.Update(ctx, img, "target", "labels"); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return err
+		}
+		if _, err := imageService.Create(ctx, img); err != nil {
+			return err
+		}
+	}
+	ensured.Ref = name
+	ensured.Image = containerd.NewImageWithPlatform(client, img, platformutil.NewMatchComparerFromOCISpecPlatformSlice(options.OCISpecPlatform))
+	return nil
+}
+
+// needsPull returns true if imgutil.EnsureImage pulls the image, i.e., if it is not used from the local store.
+func needsPull(ctx context.Context, client *containerd.Client, rawRef string, options types.ImagePullOptions) (bool, error) {
+	switch options.Mode {
+	case "never":
+		return false, nil
+	case "always":
+		return true, nil
+	}
+	if len(options.OCISpecPlatform) != 1 {
+		return true, nil
+	}
+	_, err := imgutil.GetExistingImage(ctx, client, options.GOptions.Snapshotter, rawRef, options.OCISpecPlatform[0])
+	if err == nil {
+		return false, nil
+	}
+	if errdefs.IsNotFound(err) {
+		return true, nil
+	}
+	return false, err
+}
@@ -204,7 +204,8 @@ func Push(ctx context.Context, client *containerd.Client, rawRef string, options
 		return err
 	}
 	signRef := fmt.Sprintf("%s@%s", refSpec.String(), img.Target.Digest.String())
-	if err = signutil.Sign(signRef,
+	if err = signutil.Sign(ctx, signRef,
+		options.GOptions.HostsDir,
 		options.GOptions.Experimental,
 		options.SignOptions); err != nil {
 		return err
@@ -0,0 +1,105 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// Annotate updates the platform of the manifest `manifestRef` in the manifest list `listRef`.
+func Annotate(ctx context.Context, client *containerd.Client, listRef, manifestRef string, options types.ManifestAnnotateOptions) error {
+	img, idx, err := getList(ctx, client, listRef)
+	if err != nil {
+		return err
+	}
+	dgst, err := manifestDigest(ctx, client, manifestRef)
+	if err != nil {
+		return err
+	}
+	i := -1
+	for j, m := range idx.Manifests {
+		if m.Digest == dgst {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		return fmt.Errorf("manifest %s (%s) is not in manifest list %s", manifestRef, dgst, listRef)
+	}
+
+	var platform ocispec.Platform
+	if p := idx.Manifests[i].Platform; p != nil {
+		platform = *p
+	}
+	if options.OS != "" {
+		platform.OS = options.OS
+	}
+	if options.Arch != "" {
+		platform.Architecture = options.Arch
+	}
+	if options.Variant != "" {
+		platform.Variant = options.Variant
+	}
+	if options.OSVersion != "" {
+		platform.OSVersion = options.OSVersion
+	}
+	if len(options.OSFeatures) > 0 {
+		platform.OSFeatures = options.OSFeatures
+	}
+	if platform.OS == "" || platform.Architecture == "" {
+		return fmt.Errorf("manifest %s must have an OS and an architecture", manifestRef)
+	}
+	normalized := platforms.Normalize(platform)
+	idx.Manifests[i].Platform = &normalized
+
+	_, err = saveList(ctx, client, img.Name, *idx)
+	return err
+}
+
+// manifestDigest returns the digest of the manifest `rawRef`, which is either a digest,
+// a reference with a digest, or a local image whose target is a manifest.
+func manifestDigest(ctx context.Context, client *containerd.Client, rawRef string) (digest.Digest, error) {
+	if dgst, err := digest.Parse(rawRef); err == nil {
+		return dgst, nil
+	}
+	parsedReference, err := referenceutil.Parse(rawRef)
+	if err != nil {
+		return "", err
+	}
+	if parsedReference.Digest != "" {
+		return parsedReference.Digest, nil
+	}
+	img, err := client.ImageService().Get(ctx, parsedReference.String())
+	if err != nil {
+		return "", err
+	}
+	if !images.IsManifestType(img.Target.MediaType) {
+		return "", fmt.Errorf("image %s is a multi-platform image, specify its manifest by digest", rawRef)
+	}
+	return img.Target.Digest, nil
+}
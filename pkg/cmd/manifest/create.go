@@ -0,0 +1,157 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// attestationManifestAnnotation is set by BuildKit on the attestation manifests of image indexes.
+const attestationManifestAnnotation = "vnd.docker.reference.type"
+
+// Create creates the manifest list `listRef` from the platform manifests of the local images `manifestRefs`.
+func Create(ctx context.Context, client *containerd.Client, listRef string, manifestRefs []string, options types.ManifestCreateOptions) error {
+	parsedReference, err := referenceutil.Parse(listRef)
+	if err != nil {
+		return err
+	}
+	if parsedReference.Digest != "" {
+		return fmt.Errorf("manifest list %s must not be referenced by digest", listRef)
+	}
+
+	var idx ocispec.Index
+	if _, existing, err := getList(ctx, client, listRef); err == nil {
+		if !options.Amend {
+			return fmt.Errorf("refusing to amend an existing manifest list with no --amend flag: %s", listRef)
+		}
+		idx = *existing
+	} else if !errdefs.IsNotFound(err) {
+		return err
+	} else if _, err := client.ImageService().Get(ctx, parsedReference.String()); err == nil {
+		return fmt.Errorf("image %s already exists and is not a manifest list", listRef)
+	}
+
+	for _, manifestRef := range manifestRefs {
+		descs, err := platformManifests(ctx, client, manifestRef)
+		if err != nil {
+			return err
+		}
+		for _, desc := range descs {
+			idx.Manifests = addManifest(idx.Manifests, desc)
+		}
+	}
+
+	if _, err := saveList(ctx, client, parsedReference.String(), idx); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(options.Stdout, "Created manifest list %s\n", parsedReference.String())
+	return err
+}
+
+// platformManifests returns the descriptors of the platform manifests of the local image `rawRef`,
+// with their platforms.
+// For a multi-platform image, only the manifests available in the content store are returned.
+func platformManifests(ctx context.Context, client *containerd.Client, rawRef string) ([]ocispec.Descriptor, error) {
+	parsedReference, err := referenceutil.Parse(rawRef)
+	if err != nil {
+		return nil, err
+	}
+	img, err := client.ImageService().Get(ctx, parsedReference.String())
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, fmt.Errorf("image %s not found, it must be pulled before creating a manifest list: %w", rawRef, err)
+		}
+		return nil, err
+	}
+	cimg := containerd.NewImage(client, img)
+	target := img.Target
+
+	if images.IsManifestType(target.MediaType) {
+		config, _, err := imgutil.ReadImageConfig(ctx, cimg)
+		if err != nil {
+			return nil, err
+		}
+		return []ocispec.Descriptor{{
+			MediaType: target.MediaType,
+			Digest:    target.Digest,
+			Size:      target.Size,
+			Platform:  &config.Platform,
+		}}, nil
+	}
+
+	idx, _, err := imgutil.ReadIndex(ctx, cimg)
+	if err != nil {
+		return nil, err
+	}
+	if idx == nil {
+		return nil, fmt.Errorf("image %s has an unsupported media type %s", rawRef, target.MediaType)
+	}
+	var descs []ocispec.Descriptor
+	for _, m := range idx.Manifests {
+		if !images.IsManifestType(m.MediaType) || m.Annotations[attestationManifestAnnotation] != "" {
+			continue
+		}
+		if m.Platform == nil {
+			log.G(ctx).Warnf("ignoring manifest %s of %s, which has no platform", m.Digest, rawRef)
+			continue
+		}
+		if _, err := client.ContentStore().Info(ctx, m.Digest); err != nil {
+			log.G(ctx).WithError(err).Debugf("ignoring manifest %s of %s (%s), which is not available locally", m.Digest, rawRef, m.Platform.Architecture)
+			continue
+		}
+		descs = append(descs, ocispec.Descriptor{
+			MediaType: m.MediaType,
+			Digest:    m.Digest,
+			Size:      m.Size,
+			Platform:  m.Platform,
+		})
+	}
+	if len(descs) == 0 {
+		return nil, fmt.Errorf("no platform manifest of %s is available locally", rawRef)
+	}
+	return descs, nil
+}
+
+// addManifest adds `desc` to `manifests`, replacing the manifest with the same digest or the same platform.
+func addManifest(manifests []ocispec.Descriptor, desc ocispec.Descriptor) []ocispec.Descriptor {
+	for i, m := range manifests {
+		if m.Digest == desc.Digest || samePlatform(m.Platform, desc.Platform) {
+			manifests[i] = desc
+			return manifests
+		}
+	}
+	return append(manifests, desc)
+}
+
+func samePlatform(a, b *ocispec.Platform) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.OS == b.OS && a.Architecture == b.Architecture && a.Variant == b.Variant && a.OSVersion == b.OSVersion
+}
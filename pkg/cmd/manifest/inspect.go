@@ -0,0 +1,182 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// maxManifestSize is the maximum size of the manifests fetched from registries.
+const maxManifestSize = 4 << 20
+
+// manifestEntry is an item of `nerdctl manifest inspect --verbose`, in the format of `docker manifest inspect --verbose`.
+type manifestEntry struct {
+	Ref              string
+	Descriptor       ocispec.Descriptor
+	SchemaV2Manifest *ocispec.Manifest `json:",omitempty"`
+	OCIManifest      *ocispec.Manifest `json:",omitempty"`
+}
+
+type fetchFunc func(ctx context.Context, desc ocispec.Descriptor) ([]byte, error)
+
+// Inspect prints the manifest list or the manifest `rawRef`.
+// Manifest lists created by `nerdctl manifest create` are read locally, other references are fetched from their registry.
+func Inspect(ctx context.Context, client *containerd.Client, rawRef string, options types.ManifestInspectOptions) error {
+	parsedReference, err := referenceutil.Parse(rawRef)
+	if err != nil {
+		return err
+	}
+
+	var (
+		target ocispec.Descriptor
+		fetch  fetchFunc
+	)
+	if img, _, err := getList(ctx, client, rawRef); err == nil {
+		target = img.Target
+		fetch = func(ctx context.Context, desc ocispec.Descriptor) ([]byte, error) {
+			return content.ReadBlob(ctx, client.ContentStore(), desc)
+		}
+	} else if errdefs.IsNotFound(err) {
+		target, fetch, err = remoteFetcher(ctx, parsedReference, options.GOptions)
+		if err != nil {
+			return err
+		}
+	} else {
+		return err
+	}
+
+	b, err := fetch(ctx, target)
+	if err != nil {
+		return err
+	}
+	if !options.Verbose {
+		return printJSON(options.Stdout, json.RawMessage(b))
+	}
+
+	if !images.IsIndexType(target.MediaType) {
+		entry, err := newManifestEntry(parsedReference.Name(), target, b)
+		if err != nil {
+			return err
+		}
+		return printJSON(options.Stdout, entry)
+	}
+	var idx ocispec.Index
+	if err := json.Unmarshal(b, &idx); err != nil {
+		return err
+	}
+	entries := []manifestEntry{}
+	for _, m := range idx.Manifests {
+		if !images.IsManifestType(m.MediaType) {
+			continue
+		}
+		mb, err := fetch(ctx, m)
+		if err != nil {
+			return fmt.Errorf("failed to fetch manifest %s: %w", m.Digest, err)
+		}
+		entry, err := newManifestEntry(parsedReference.Name(), m, mb)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, *entry)
+	}
+	return printJSON(options.Stdout, entries)
+}
+
+func newManifestEntry(name string, desc ocispec.Descriptor, b []byte) (*manifestEntry, error) {
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return nil, err
+	}
+	entry := &manifestEntry{
+		Ref:        name + "@" + desc.Digest.String(),
+		Descriptor: desc,
+	}
+	if desc.MediaType == images.MediaTypeDockerSchema2Manifest {
+		entry.SchemaV2Manifest = &manifest
+	} else {
+		entry.OCIManifest = &manifest
+	}
+	return entry, nil
+}
+
+// remoteFetcher resolves the reference in its registry, and returns its descriptor and a function to fetch it and its manifests.
+func remoteFetcher(ctx context.Context, parsedReference *referenceutil.ImageReference, globalOptions types.GlobalCommandOptions) (ocispec.Descriptor, fetchFunc, error) {
+	var dOpts []dockerconfigresolver.Opt
+	if globalOptions.InsecureRegistry {
+		log.G(ctx).Warnf("skipping verifying HTTPS certs for %q", parsedReference.Domain)
+		dOpts = append(dOpts, dockerconfigresolver.WithSkipVerifyCerts(true))
+	}
+	dOpts = append(dOpts, dockerconfigresolver.WithHostsDirs(globalOptions.HostsDir))
+	resolver, err := dockerconfigresolver.New(ctx, parsedReference.Domain, dOpts...)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	name, desc, err := resolver.Resolve(ctx, parsedReference.String())
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	fetcher, err := resolver.Fetcher(ctx, name)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	fetch := func(ctx context.Context, desc ocispec.Descriptor) ([]byte, error) {
+		if err := desc.Digest.Validate(); err != nil {
+			return nil, err
+		}
+		if desc.Size > maxManifestSize {
+			return nil, fmt.Errorf("manifest %s is too large (%d bytes)", desc.Digest, desc.Size)
+		}
+		rc, err := fetcher.Fetch(ctx, desc)
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		b, err := io.ReadAll(io.LimitReader(rc, desc.Size))
+		if err != nil {
+			return nil, err
+		}
+		if desc.Digest.Algorithm().FromBytes(b) != desc.Digest {
+			return nil, fmt.Errorf("digest mismatch for manifest %s", desc.Digest)
+		}
+		return b, nil
+	}
+	return desc, fetch, nil
+}
+
+func printJSON(w io.Writer, x any) error {
+	b, err := json.MarshalIndent(x, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
@@ -0,0 +1,122 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package manifest implements `nerdctl manifest`.
+//
+// Manifest lists are stored as images in containerd, labeled with labels.ManifestList,
+// so that they can be pushed like any other multi-platform image.
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// getList returns the manifest list `rawRef` created by `nerdctl manifest create`.
+// The returned error wraps errdefs.ErrNotFound when there is no such manifest list.
+func getList(ctx context.Context, client *containerd.Client, rawRef string) (images.Image, *ocispec.Index, error) {
+	parsedReference, err := referenceutil.Parse(rawRef)
+	if err != nil {
+		return images.Image{}, nil, err
+	}
+	img, err := client.ImageService().Get(ctx, parsedReference.String())
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return images.Image{}, nil, fmt.Errorf("no such manifest list: %s: %w", rawRef, errdefs.ErrNotFound)
+		}
+		return images.Image{}, nil, err
+	}
+	if _, ok := img.Labels[labels.ManifestList]; !ok {
+		return images.Image{}, nil, fmt.Errorf("%s is not a manifest list created by `nerdctl manifest create`: %w", rawRef, errdefs.ErrNotFound)
+	}
+	idx, _, err := imgutil.ReadIndex(ctx, containerd.NewImage(client, img))
+	if err != nil {
+		return images.Image{}, nil, err
+	}
+	if idx == nil {
+		return images.Image{}, nil, fmt.Errorf("manifest list %s is not an index (%s)", rawRef, img.Target.MediaType)
+	}
+	return img, idx, nil
+}
+
+// saveList writes the manifest list `idx` into the content store, and creates or updates the image `name` pointing to it.
+func saveList(ctx context.Context, client *containerd.Client, name string, idx ocispec.Index) (ocispec.Descriptor, error) {
+	idx.SchemaVersion = 2
+	idx.MediaType = listMediaType(idx.Manifests)
+	b, err := json.Marshal(idx)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc := ocispec.Descriptor{
+		MediaType: idx.MediaType,
+		Digest:    digest.FromBytes(b),
+		Size:      int64(len(b)),
+	}
+	// The manifest list references the manifests, so that they are not garbage collected
+	// even when the images they come from are removed
+	gcLabels := make(map[string]string, len(idx.Manifests))
+	for i, m := range idx.Manifests {
+		gcLabels[fmt.Sprintf("containerd.io/gc.ref.content.m.%d", i)] = m.Digest.String()
+	}
+	if err := content.WriteBlob(ctx, client.ContentStore(), desc.Digest.String(), bytes.NewReader(b), desc, content.WithLabels(gcLabels)); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	img := images.Image{
+		Name:      name,
+		Target:    desc,
+		Labels:    map[string]string{labels.ManifestList: "true"},
+		CreatedAt: time.Now(),
+	}
+	if _, err := client.ImageService().Update(ctx, img, "target", "labels"); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return ocispec.Descriptor{}, err
+		}
+		if _, err := client.ImageService().Create(ctx, img); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	return desc, nil
+}
+
+// listMediaType returns the Docker manifest list media type when all the manifests are Docker manifests,
+// and the OCI image index media type otherwise.
+func listMediaType(manifests []ocispec.Descriptor) string {
+	if len(manifests) == 0 {
+		return ocispec.MediaTypeImageIndex
+	}
+	for _, m := range manifests {
+		if m.MediaType != images.MediaTypeDockerSchema2Manifest {
+			return ocispec.MediaTypeImageIndex
+		}
+	}
+	return images.MediaTypeDockerSchema2ManifestList
+}
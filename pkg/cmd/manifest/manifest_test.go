@@ -0,0 +1,60 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/images"
+)
+
+func TestAddManifest(t *testing.T) {
+	amd64 := ocispec.Descriptor{
+		MediaType: images.MediaTypeDockerSchema2Manifest,
+		Digest:    digest.FromString("amd64"),
+		Platform:  &ocispec.Platform{OS: "linux", Architecture: "amd64"},
+	}
+	arm64 := ocispec.Descriptor{
+		MediaType: images.MediaTypeDockerSchema2Manifest,
+		Digest:    digest.FromString("arm64"),
+		Platform:  &ocispec.Platform{OS: "linux", Architecture: "arm64"},
+	}
+	newARM64 := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromString("arm64-new"),
+		Platform:  &ocispec.Platform{OS: "linux", Architecture: "arm64"},
+	}
+
+	var manifests []ocispec.Descriptor
+	manifests = addManifest(manifests, amd64)
+	manifests = addManifest(manifests, arm64)
+	assert.DeepEqual(t, manifests, []ocispec.Descriptor{amd64, arm64})
+	assert.Equal(t, listMediaType(manifests), images.MediaTypeDockerSchema2ManifestList)
+
+	// Same digest
+	manifests = addManifest(manifests, amd64)
+	assert.DeepEqual(t, manifests, []ocispec.Descriptor{amd64, arm64})
+
+	// Same platform
+	manifests = addManifest(manifests, newARM64)
+	assert.DeepEqual(t, manifests, []ocispec.Descriptor{amd64, newARM64})
+	assert.Equal(t, listMediaType(manifests), ocispec.MediaTypeImageIndex)
+}
@@ -0,0 +1,65 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+// Push pushes the manifest list `listRef` and the manifests it contains.
+func Push(ctx context.Context, client *containerd.Client, listRef string, options types.ManifestPushOptions) error {
+	img, idx, err := getList(ctx, client, listRef)
+	if err != nil {
+		return err
+	}
+	if len(idx.Manifests) == 0 {
+		return fmt.Errorf("manifest list %s is empty", listRef)
+	}
+	for _, m := range idx.Manifests {
+		if m.Platform == nil || m.Platform.OS == "" || m.Platform.Architecture == "" {
+			return fmt.Errorf("manifest %s of manifest list %s has no platform, see `nerdctl manifest annotate`", m.Digest, listRef)
+		}
+	}
+
+	pushOptions := types.ImagePushOptions{
+		Stdout:       options.Stdout,
+		GOptions:     options.GOptions,
+		AllPlatforms: true,
+		Quiet:        options.Quiet,
+	}
+	if options.Quiet {
+		// Only print the digest, like `docker manifest push`, not the reference printed by image.Push in quiet mode
+		pushOptions.Stdout = io.Discard
+	}
+	if err := image.Push(ctx, client, img.Name, pushOptions); err != nil {
+		return err
+	}
+	if options.Purge {
+		if err := client.ImageService().Delete(ctx, img.Name); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintln(options.Stdout, img.Target.Digest)
+	return err
+}
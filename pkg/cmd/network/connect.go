@@ -0,0 +1,189 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
+)
+
+// Connect connects a container to a network.
+// When the container is running, the network is attached to it right away, otherwise it is attached on the next start.
+func Connect(ctx context.Context, client *containerd.Client, options types.NetworkConnectOptions) error {
+	if options.IPAddress != "" && net.ParseIP(options.IPAddress).To4() == nil {
+		return fmt.Errorf("invalid IPv4 address: %q", options.IPAddress)
+	}
+	cniEnv, err := netutil.NewCNIEnv(options.GOptions.CNIPath, options.GOptions.CNINetConfPath,
+		netutil.WithNamespace(options.GOptions.Namespace), netutil.WithDefaultNetwork(options.GOptions.BridgeIP))
+	if err != nil {
+		return err
+	}
+	netConf, err := cniEnv.NetworkByNameOrID(options.Network)
+	if err != nil {
+		return err
+	}
+
+	walker := &containerwalker.ContainerWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found containerwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			return connectContainer(ctx, found.Container, cniEnv, netConf, options)
+		},
+	}
+	if n, err := walker.Walk(ctx, options.Container); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no such container %s", options.Container)
+	}
+	return nil
+}
+
+func connectContainer(ctx context.Context, container containerd.Container, cniEnv *netutil.CNIEnv, netConf *netutil.NetworkConfig, options types.NetworkConnectOptions) error {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	networks, aliases, err := containerNetworks(lab)
+	if err != nil {
+		return err
+	}
+	if networkIndex(cniEnv, networks, netConf.Name) >= 0 {
+		return fmt.Errorf("container %s is already connected to network %s", container.ID(), netConf.Name)
+	}
+
+	running, err := isRunning(ctx, container)
+	if err != nil {
+		return err
+	}
+	if running {
+		if err := attachNetwork(ctx, container, lab, networks, cniEnv, netConf, options); err != nil {
+			return err
+		}
+	} else if options.IPAddress != "" {
+		return fmt.Errorf("--ip can only be specified when connecting a running container")
+	}
+
+	if len(options.Aliases) > 0 {
+		aliases[netConf.Name] = options.Aliases
+	}
+	if err := updateNetworkLabels(ctx, container, append(networks, netConf.Name), aliases); err != nil {
+		if running {
+			if detachErr := detachNetwork(ctx, container, lab, networks, cniEnv, netConf, options.GOptions); detachErr != nil {
+				log.G(ctx).WithError(detachErr).Warnf("failed to detach network %s from container %s", netConf.Name, container.ID())
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// containerNetworks returns the networks and the network aliases of a container using CNI networking.
+func containerNetworks(lab map[string]string) ([]string, map[string][]string, error) {
+	var networks []string
+	if err := json.Unmarshal([]byte(lab[labels.Networks]), &networks); err != nil {
+		return nil, nil, err
+	}
+	netType, err := nettype.Detect(networks)
+	if err != nil {
+		return nil, nil, err
+	}
+	if netType != nettype.CNI {
+		return nil, nil, fmt.Errorf("container networking mode %q does not support connecting or disconnecting networks", networks[0])
+	}
+	aliases := make(map[string][]string)
+	if aliasesJSON := lab[labels.NetworkAliases]; aliasesJSON != "" {
+		if err := json.Unmarshal([]byte(aliasesJSON), &aliases); err != nil {
+			return nil, nil, err
+		}
+	}
+	return networks, aliases, nil
+}
+
+// networkIndex returns the index of the network named name in networks, which may contain network IDs, or -1.
+func networkIndex(cniEnv *netutil.CNIEnv, networks []string, name string) int {
+	for i, netstr := range networks {
+		if netstr == name {
+			return i
+		}
+		if netw, err := cniEnv.NetworkByNameOrID(netstr); err == nil && netw.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func isRunning(ctx context.Context, container containerd.Container) (bool, error) {
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return false, err
+	}
+	switch status.Status {
+	case containerd.Running:
+		return true, nil
+	case containerd.Paused, containerd.Pausing:
+		return false, fmt.Errorf("container %s is paused, unpause the container before connecting or disconnecting networks", container.ID())
+	default:
+		return false, nil
+	}
+}
+
+// updateNetworkLabels persists the networks of the container, both in the container labels and in the OCI spec
+// annotations, which are read by the OCI hook when the container is (re)started.
+func updateNetworkLabels(ctx context.Context, container containerd.Container, networks []string, aliases map[string][]string) error {
+	networksJSON, err := json.Marshal(networks)
+	if err != nil {
+		return err
+	}
+	aliasesJSON, err := json.Marshal(aliases)
+	if err != nil {
+		return err
+	}
+	lbls := map[string]string{
+		labels.Networks:       string(networksJSON),
+		labels.NetworkAliases: string(aliasesJSON),
+	}
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return err
+	}
+	return container.Update(ctx,
+		containerd.UpdateContainerOpts(containerd.WithAdditionalContainerLabels(lbls)),
+		containerd.UpdateContainerOpts(containerd.WithSpec(spec, oci.WithAnnotations(lbls))),
+	)
+}
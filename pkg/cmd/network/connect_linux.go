@@ -0,0 +1,196 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/containernetworking/cni/libcni"
+	types100 "github.com/containernetworking/cni/pkg/types/100"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/go-cni"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
+	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+	"github.com/containerd/nerdctl/v2/pkg/ocihook/state"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+// attachNetwork invokes CNI ADD for netConf against the network namespace of the running container,
+// and records the new interface in the container state and in the hosts store.
+func attachNetwork(ctx context.Context, container containerd.Container, lab map[string]string, networks []string,
+	cniEnv *netutil.CNIEnv, netConf *netutil.NetworkConfig, options types.NetworkConnectOptions) error {
+	netNSPath, err := containerutil.ContainerNetNSPath(ctx, container)
+	if err != nil {
+		return err
+	}
+	lf, err := state.New(lab[labels.StateDir])
+	if err != nil {
+		return err
+	}
+	// See the CNI concurrency lock in the OCI hook
+	lock, err := filesystem.Lock(filepath.Join(options.GOptions.CNINetConfPath, ".cni-concurrency.lock"))
+	if err != nil {
+		return err
+	}
+	defer filesystem.Unlock(lock)
+
+	var result *types100.Result
+	err = lf.Transform(func(lf *state.Store) error {
+		if lf.Interfaces == nil {
+			lf.Interfaces = bootInterfaces(networks)
+		}
+		ifName := netutil.NextInterfaceName(lf.Interfaces)
+		rt := &libcni.RuntimeConf{
+			ContainerID: lab[labels.Namespace] + "-" + container.ID(),
+			NetNS:       netNSPath,
+			IfName:      ifName,
+			Args: [][2]string{
+				{"IgnoreUnknown", "1"},
+				{"NERDCTL_CNI_DHCP_HOSTNAME", lab[labels.Hostname]},
+			},
+		}
+		if options.IPAddress != "" {
+			rt.Args = append(rt.Args, [2]string{"IP", options.IPAddress})
+		}
+		err := rootlessutil.WithDetachedNetNSIfAny(func() error {
+			var err error
+			result, err = cniEnv.AttachNetwork(ctx, netConf, rt)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		lf.Interfaces[netConf.Name] = ifName
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	hs, err := hostsStore(lab, options.GOptions)
+	if err == nil {
+		err = hs.AttachNetwork(container.ID(), netConf.Name, result, options.Aliases)
+	}
+	if err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to update the hosts files for container %s", container.ID())
+	}
+	return nil
+}
+
+// detachNetwork invokes CNI DEL for netConf against the network namespace of the running container,
+// and removes the interface from the container state and from the hosts store.
+func detachNetwork(ctx context.Context, container containerd.Container, lab map[string]string, networks []string,
+	cniEnv *netutil.CNIEnv, netConf *netutil.NetworkConfig, gOptions types.GlobalCommandOptions) error {
+	netNSPath, err := containerutil.ContainerNetNSPath(ctx, container)
+	if err != nil {
+		return err
+	}
+	lf, err := state.New(lab[labels.StateDir])
+	if err != nil {
+		return err
+	}
+	var ports []cni.PortMapping
+	if portsJSON := lab[labels.Ports]; portsJSON != "" {
+		if err := json.Unmarshal([]byte(portsJSON), &ports); err != nil {
+			return err
+		}
+	}
+	lock, err := filesystem.Lock(filepath.Join(gOptions.CNINetConfPath, ".cni-concurrency.lock"))
+	if err != nil {
+		return err
+	}
+	defer filesystem.Unlock(lock)
+
+	var hostsKey string
+	err = lf.Transform(func(lf *state.Store) error {
+		if lf.Interfaces == nil {
+			lf.Interfaces = bootInterfaces(networks)
+		}
+		for netstr := range lf.Interfaces {
+			if networkIndex(cniEnv, []string{netstr}, netConf.Name) == 0 {
+				hostsKey = netstr
+				break
+			}
+		}
+		if hostsKey == "" {
+			return fmt.Errorf("container %s has no interface on network %s", container.ID(), netConf.Name)
+		}
+		rt := &libcni.RuntimeConf{
+			ContainerID: lab[labels.Namespace] + "-" + container.ID(),
+			NetNS:       netNSPath,
+			IfName:      lf.Interfaces[hostsKey],
+			Args:        [][2]string{{"IgnoreUnknown", "1"}},
+		}
+		if len(ports) > 0 {
+			rt.CapabilityArgs = map[string]interface{}{"portMappings": ports}
+		}
+		err := rootlessutil.WithDetachedNetNSIfAny(func() error {
+			return cniEnv.DetachNetwork(ctx, netConf, rt)
+		})
+		if err != nil {
+			return err
+		}
+		delete(lf.Interfaces, hostsKey)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	hs, err := hostsStore(lab, gOptions)
+	if err == nil {
+		err = hs.DetachNetwork(container.ID(), hostsKey)
+	}
+	if err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to update the hosts files for container %s", container.ID())
+	}
+	return nil
+}
+
+// bootInterfaces returns the interfaces created by the OCI hook when the container was started,
+// i.e., one interface per network, named after the index of the network.
+func bootInterfaces(networks []string) map[string]string {
+	interfaces := make(map[string]string, len(networks))
+	for i, netstr := range networks {
+		interfaces[netstr] = netutil.InterfaceName(i)
+	}
+	return interfaces
+}
+
+func hostsStore(lab map[string]string, gOptions types.GlobalCommandOptions) (hostsstore.Store, error) {
+	dataStore, err := clientutil.DataStore(gOptions.DataRoot, gOptions.Address)
+	if err != nil {
+		return nil, err
+	}
+	namespace := lab[labels.Namespace]
+	if namespace == "" {
+		return nil, errors.New("namespace label is not set")
+	}
+	return hostsstore.New(dataStore, namespace)
+}
@@ -0,0 +1,40 @@
+//go:build !linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"fmt"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+)
+
+func attachNetwork(ctx context.Context, container containerd.Container, lab map[string]string, networks []string,
+	cniEnv *netutil.CNIEnv, netConf *netutil.NetworkConfig, options types.NetworkConnectOptions) error {
+	return fmt.Errorf("connecting a running container to a network: %w", errdefs.ErrNotImplemented)
+}
+
+func detachNetwork(ctx context.Context, container containerd.Container, lab map[string]string, networks []string,
+	cniEnv *netutil.CNIEnv, netConf *netutil.NetworkConfig, gOptions types.GlobalCommandOptions) error {
+	return fmt.Errorf("disconnecting a running container from a network: %w", errdefs.ErrNotImplemented)
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+)
+
+// Disconnect disconnects a container from a network.
+// When the container is running, the network is detached from it right away.
+func Disconnect(ctx context.Context, client *containerd.Client, options types.NetworkDisconnectOptions) error {
+	cniEnv, err := netutil.NewCNIEnv(options.GOptions.CNIPath, options.GOptions.CNINetConfPath,
+		netutil.WithNamespace(options.GOptions.Namespace), netutil.WithDefaultNetwork(options.GOptions.BridgeIP))
+	if err != nil {
+		return err
+	}
+	netConf, err := cniEnv.NetworkByNameOrID(options.Network)
+	if err != nil {
+		return err
+	}
+
+	walker := &containerwalker.ContainerWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found containerwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			return disconnectContainer(ctx, found.Container, cniEnv, netConf, options)
+		},
+	}
+	if n, err := walker.Walk(ctx, options.Container); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no such container %s", options.Container)
+	}
+	return nil
+}
+
+func disconnectContainer(ctx context.Context, container containerd.Container, cniEnv *netutil.CNIEnv, netConf *netutil.NetworkConfig, options types.NetworkDisconnectOptions) error {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	networks, aliases, err := containerNetworks(lab)
+	if err != nil {
+		return err
+	}
+	idx := networkIndex(cniEnv, networks, netConf.Name)
+	if idx < 0 {
+		return fmt.Errorf("container %s is not connected to network %s", container.ID(), netConf.Name)
+	}
+	if len(networks) == 1 {
+		return fmt.Errorf("cannot disconnect container %s from its only network %s", container.ID(), netConf.Name)
+	}
+
+	running, err := isRunning(ctx, container)
+	if err != nil {
+		return err
+	}
+	if running {
+		if err := detachNetwork(ctx, container, lab, networks, cniEnv, netConf, options.GOptions); err != nil {
+			return err
+		}
+	}
+
+	delete(aliases, networks[idx])
+	delete(aliases, netConf.Name)
+	return updateNetworkLabels(ctx, container, slices.Delete(slices.Clone(networks), idx, idx+1), aliases)
+}
@@ -0,0 +1,529 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/go-units"
+	"github.com/opencontainers/image-spec/identity"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/snapshots"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/builder"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+	"github.com/containerd/nerdctl/v2/pkg/containerdutil"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+)
+
+// dfSummary is a row of the default output of `nerdctl system df`
+type dfSummary struct {
+	Type        string
+	TotalCount  int
+	Active      int
+	Size        string
+	Reclaimable string
+}
+
+type dfImage struct {
+	Repository   string
+	Tag          string
+	ID           string
+	CreatedAt    string
+	CreatedSince string
+	Size         string
+	SharedSize   string
+	UniqueSize   string
+	Containers   int
+}
+
+type dfContainer struct {
+	ID           string
+	Image        string
+	Command      string
+	LocalVolumes int
+	Size         string
+	CreatedAt    string
+	RunningFor   string
+	Status       string
+	Names        string
+}
+
+type dfVolume struct {
+	Name  string
+	Links int
+	Size  string
+}
+
+type dfBuildCache struct {
+	ID            string
+	CacheType     string
+	Size          string
+	CreatedAt     string
+	CreatedSince  string
+	LastUsedAt    string
+	LastUsedSince string
+	UsageCount    int
+	InUse         bool
+	Shared        bool
+	Description   string
+}
+
+// dfVerbose is the output of `nerdctl system df -v`
+type dfVerbose struct {
+	Images     []dfImage
+	Containers []dfContainer
+	Volumes    []dfVolume
+	BuildCache []dfBuildCache
+}
+
+// usage aggregates the size of a category of objects
+type usage struct {
+	total       int
+	active      int
+	size        int64
+	reclaimable int64
+}
+
+func (u usage) summary(typ string) dfSummary {
+	reclaimable := units.HumanSize(float64(u.reclaimable))
+	if u.size > 0 {
+		reclaimable = fmt.Sprintf("%s (%d%%)", reclaimable, u.reclaimable*100/u.size)
+	}
+	return dfSummary{
+		Type:        typ,
+		TotalCount:  u.total,
+		Active:      u.active,
+		Size:        units.HumanSize(float64(u.size)),
+		Reclaimable: reclaimable,
+	}
+}
+
+// DiskUsage shows the disk space used by images, containers, volumes and the build cache.
+func DiskUsage(ctx context.Context, client *containerd.Client, options types.SystemDfOptions) error {
+	var tmpl *template.Template
+	switch options.Format {
+	case "", "table":
+	case "raw":
+		return errors.New("unsupported format: \"raw\"")
+	default:
+		var err error
+		tmpl, err = formatter.ParseTemplate(options.Format)
+		if err != nil {
+			return err
+		}
+	}
+
+	containers, err := client.Containers(ctx)
+	if err != nil {
+		return err
+	}
+	verbose := dfVerbose{}
+	containersUsage, imageUsers, err := containersDiskUsage(ctx, client, containers, &verbose)
+	if err != nil {
+		return err
+	}
+	imagesUsage, err := imagesDiskUsage(ctx, client, options.GOptions.Snapshotter, imageUsers, &verbose)
+	if err != nil {
+		return err
+	}
+	volumesUsage, err := volumesDiskUsage(ctx, containers, options, &verbose)
+	if err != nil {
+		return err
+	}
+	buildCacheUsage, err := buildCacheDiskUsage(ctx, options, &verbose)
+	if err != nil {
+		return err
+	}
+	summaries := []dfSummary{
+		imagesUsage.summary("Images"),
+		containersUsage.summary("Containers"),
+		volumesUsage.summary("Local Volumes"),
+		buildCacheUsage.summary("Build Cache"),
+	}
+
+	w := options.Stdout
+	if tmpl != nil {
+		if options.Verbose {
+			return executeTemplate(w, tmpl, verbose)
+		}
+		for _, s := range summaries {
+			if err := executeTemplate(w, tmpl, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if options.Verbose {
+		return printVerboseDiskUsage(w, verbose, buildCacheUsage.size)
+	}
+	tw := tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tTOTAL\tACTIVE\tSIZE\tRECLAIMABLE")
+	for _, s := range summaries {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\n", s.Type, s.TotalCount, s.Active, s.Size, s.Reclaimable)
+	}
+	return tw.Flush()
+}
+
+func executeTemplate(w io.Writer, tmpl *template.Template, x any) error {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, x); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, b.String())
+	return err
+}
+
+// containersDiskUsage returns the usage of the writable layers of the containers,
+// and the number of containers using each image (by name).
+func containersDiskUsage(ctx context.Context, client *containerd.Client, containers []containerd.Container, verbose *dfVerbose) (usage, map[string]int, error) {
+	var u usage
+	imageUsers := make(map[string]int)
+	for _, c := range containers {
+		info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return u, nil, err
+		}
+		imageUsers[info.Image]++
+
+		var size int64
+		if info.Snapshotter != "" && info.SnapshotKey != "" {
+			// The usage of the snapshot itself, without its parents (i.e., the image)
+			if rw, err := client.SnapshotService(info.Snapshotter).Usage(ctx, info.SnapshotKey); err == nil {
+				size = rw.Size
+			} else {
+				log.G(ctx).WithError(err).Debugf("failed to get the size of the writable layer of container %q", c.ID())
+			}
+		}
+		u.total++
+		u.size += size
+		status := formatter.ContainerStatus(ctx, c)
+		if strings.HasPrefix(status, "Up") {
+			u.active++
+		} else {
+			u.reclaimable += size
+		}
+
+		var command string
+		if spec, err := c.Spec(ctx); err == nil {
+			command = formatter.InspectContainerCommandTrunc(spec)
+		}
+		verbose.Containers = append(verbose.Containers, dfContainer{
+			ID:           stringid.TruncateID(c.ID()),
+			Image:        info.Image,
+			Command:      command,
+			LocalVolumes: localVolumes(info.Labels),
+			Size:         units.HumanSize(float64(size)),
+			CreatedAt:    info.CreatedAt.Round(time.Second).Local().String(),
+			RunningFor:   formatter.TimeSinceInHuman(info.CreatedAt),
+			Status:       status,
+			Names:        info.Labels[labels.Name],
+		})
+	}
+	return u, imageUsers, nil
+}
+
+func localVolumes(containerLabels map[string]string) int {
+	mountsJSON, ok := containerLabels[labels.Mounts]
+	if !ok {
+		return 0
+	}
+	var mounts []dockercompat.MountPoint
+	if err := json.Unmarshal([]byte(mountsJSON), &mounts); err != nil {
+		return 0
+	}
+	count := 0
+	for _, m := range mounts {
+		if m.Type == mountutil.Volume {
+			count++
+		}
+	}
+	return count
+}
+
+// imageResources returns the size of the blobs of the image in the content store, and of its unpacked snapshots,
+// by resource. Blobs and snapshots shared by several images are only stored once.
+func imageResources(ctx context.Context, client *containerd.Client, cs content.Store, sn snapshots.Snapshotter, img images.Image) (map[string]int64, error) {
+	resources := make(map[string]int64)
+	handler := images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		info, err := cs.Info(ctx, desc.Digest)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				// e.g., the blobs of other platforms
+				return nil, images.ErrSkipDesc
+			}
+			return nil, err
+		}
+		resources["content:"+desc.Digest.String()] = info.Size
+		return images.Children(ctx, cs, desc)
+	})
+	if err := images.Walk(ctx, handler, img.Target); err != nil {
+		return nil, err
+	}
+
+	diffIDs, err := containerd.NewImage(client, img).RootFS(ctx)
+	if err != nil {
+		// The image is not available for the current platform, there is no snapshot
+		log.G(ctx).WithError(err).Debugf("failed to get the rootfs of image %q", img.Name)
+		return resources, nil
+	}
+	for _, chainID := range identity.ChainIDs(diffIDs) {
+		u, err := sn.Usage(ctx, chainID.String())
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				// Not unpacked
+				continue
+			}
+			return nil, err
+		}
+		resources["snapshot:"+chainID.String()] = u.Size
+	}
+	return resources, nil
+}
+
+func imagesDiskUsage(ctx context.Context, client *containerd.Client, snapshotter string, imageUsers map[string]int, verbose *dfVerbose) (usage, error) {
+	var u usage
+	imageList, err := client.ImageService().List(ctx)
+	if err != nil {
+		return u, err
+	}
+	cs := client.ContentStore()
+	sn := containerdutil.SnapshotService(client, snapshotter)
+
+	imageResourcesList := make([]map[string]int64, len(imageList))
+	// refs counts the images using each resource
+	refs := make(map[string]int)
+	// activeResources are the resources of the images used by containers
+	activeResources := make(map[string]struct{})
+	for i, img := range imageList {
+		resources, err := imageResources(ctx, client, cs, sn, img)
+		if err != nil {
+			return u, err
+		}
+		imageResourcesList[i] = resources
+		for r := range resources {
+			refs[r]++
+			if imageUsers[img.Name] > 0 {
+				activeResources[r] = struct{}{}
+			}
+		}
+	}
+
+	sizes := make(map[string]int64)
+	for i, img := range imageList {
+		var size, shared int64
+		for r, s := range imageResourcesList[i] {
+			sizes[r] = s
+			size += s
+			if refs[r] > 1 {
+				shared += s
+			}
+		}
+		u.total++
+		if imageUsers[img.Name] > 0 {
+			u.active++
+		}
+
+		var repository, tag string
+		// cri plugin will create an image named digest of image's config, skip parsing.
+		if img.Target.Digest.String() != img.Name {
+			repository, tag = imgutil.ParseRepoTag(img.Name)
+		}
+		if repository == "" {
+			repository = "<none>"
+		}
+		if tag == "" {
+			tag = "<none>"
+		}
+		verbose.Images = append(verbose.Images, dfImage{
+			Repository:   repository,
+			Tag:          tag,
+			ID:           img.Target.Digest.Encoded()[:12],
+			CreatedAt:    img.CreatedAt.Round(time.Second).Local().String(),
+			CreatedSince: formatter.TimeSinceInHuman(img.CreatedAt),
+			Size:         units.HumanSize(float64(size)),
+			SharedSize:   units.HumanSize(float64(shared)),
+			UniqueSize:   units.HumanSize(float64(size - shared)),
+			Containers:   imageUsers[img.Name],
+		})
+	}
+	// Shared resources are only counted once in the total
+	for r, s := range sizes {
+		u.size += s
+		if _, ok := activeResources[r]; !ok {
+			u.reclaimable += s
+		}
+	}
+	return u, nil
+}
+
+func volumesDiskUsage(ctx context.Context, containers []containerd.Container, options types.SystemDfOptions, verbose *dfVerbose) (usage, error) {
+	var u usage
+	vols, err := volume.Volumes(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address, true, nil)
+	if err != nil {
+		return u, err
+	}
+	links, err := volume.Links(ctx, containers)
+	if err != nil {
+		return u, err
+	}
+	names := make([]string, 0, len(vols))
+	for name := range vols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		vol := vols[name]
+		u.total++
+		u.size += vol.Size
+		if links[name] > 0 {
+			u.active++
+		} else {
+			u.reclaimable += vol.Size
+		}
+		verbose.Volumes = append(verbose.Volumes, dfVolume{
+			Name:  name,
+			Links: links[name],
+			Size:  units.HumanSize(float64(vol.Size)),
+		})
+	}
+	return u, nil
+}
+
+func buildCacheDiskUsage(ctx context.Context, options types.SystemDfOptions, verbose *dfVerbose) (usage, error) {
+	var u usage
+	if options.BuildKitHost == "" {
+		return u, nil
+	}
+	records, err := builder.DiskUsage(ctx, types.BuilderDiskUsageOptions{
+		Stderr:       options.Stderr,
+		GOptions:     options.GOptions,
+		BuildKitHost: options.BuildKitHost,
+	})
+	if err != nil {
+		// The other usages are still worth reporting when buildctl or buildkitd is broken
+		log.G(ctx).WithError(err).Warn("failed to get the build cache usage, the build cache is unavailable")
+		return u, nil
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt.Before(records[j].CreatedAt)
+	})
+	for _, r := range records {
+		u.total++
+		if r.InUse {
+			u.active++
+		}
+		// Shared records are also accounted for as images (e.g. when using the containerd worker)
+		if !r.Shared {
+			u.size += r.Size
+			if !r.InUse {
+				u.reclaimable += r.Size
+			}
+		}
+		verbose.BuildCache = append(verbose.BuildCache, buildCachePrintable(r))
+	}
+	return u, nil
+}
+
+func buildCachePrintable(r buildkitutil.UsageInfo) dfBuildCache {
+	p := dfBuildCache{
+		ID:           r.ID,
+		CacheType:    string(r.RecordType),
+		Size:         units.HumanSize(float64(r.Size)),
+		CreatedAt:    r.CreatedAt.Round(time.Second).Local().String(),
+		CreatedSince: formatter.TimeSinceInHuman(r.CreatedAt),
+		UsageCount:   r.UsageCount,
+		InUse:        r.InUse,
+		Shared:       r.Shared,
+		Description:  r.Description,
+	}
+	if r.LastUsedAt != nil {
+		p.LastUsedAt = r.LastUsedAt.Round(time.Second).Local().String()
+		p.LastUsedSince = formatter.TimeSinceInHuman(*r.LastUsedAt)
+	}
+	return p
+}
+
+func printVerboseDiskUsage(w io.Writer, verbose dfVerbose, buildCacheSize int64) error {
+	fmt.Fprintf(w, "Images space usage:\n\n")
+	tw := tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(tw, "REPOSITORY\tTAG\tIMAGE ID\tCREATED\tSIZE\tSHARED SIZE\tUNIQUE SIZE\tCONTAINERS")
+	for _, img := range verbose.Images {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+			img.Repository, img.Tag, img.ID, img.CreatedSince, img.Size, img.SharedSize, img.UniqueSize, img.Containers)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nContainers space usage:\n\n")
+	tw = tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(tw, "CONTAINER ID\tIMAGE\tCOMMAND\tLOCAL VOLUMES\tSIZE\tCREATED\tSTATUS\tNAMES")
+	for _, c := range verbose.Containers {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			c.ID, c.Image, c.Command, c.LocalVolumes, c.Size, c.RunningFor, c.Status, c.Names)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nLocal Volumes space usage:\n\n")
+	tw = tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(tw, "VOLUME NAME\tLINKS\tSIZE")
+	for _, v := range verbose.Volumes {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", v.Name, v.Links, v.Size)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nBuild cache usage: %s\n\n", units.HumanSize(float64(buildCacheSize)))
+	tw = tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(tw, "CACHE ID\tCACHE TYPE\tSIZE\tCREATED\tLAST USED\tUSAGE\tSHARED")
+	for _, b := range verbose.BuildCache {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%t\n",
+			b.ID, b.CacheType, b.Size, b.CreatedSince, b.LastUsedSince, b.UsageCount, b.Shared)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,154 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/infoutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/statsutil"
+)
+
+// MetricsServe serves the statistics of the running containers in the Prometheus text format on /metrics,
+// until ctx is done.
+func MetricsServe(ctx context.Context, client *containerd.Client, options types.SystemMetricsServeOptions) error {
+	// NOTE: rootless container does not rely on cgroupv1.
+	// more details about possible ways to resolve this concern: #223
+	if rootlessutil.IsRootless() && infoutil.CgroupsVersion() == "1" {
+		return errors.New("metrics requires cgroup v2 for rootless containers, see https://rootlesscontaine.rs/getting-started/common/cgroup2/")
+	}
+
+	listener, err := net.Listen("tcp", options.Listen)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		samples, err := collectMetrics(r.Context(), client, options)
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("failed to collect the container metrics")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var buf bytes.Buffer
+		if err := statsutil.WriteMetrics(&buf, samples); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", statsutil.MetricsContentType)
+		w.Write(buf.Bytes())
+	})
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+	fmt.Fprintf(options.Stdout, "Serving container metrics on http://%s/metrics\n", listener.Addr())
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+// collectMetrics returns the samples of the running containers of the current namespace,
+// or of all the namespaces when options.AllNamespaces is set.
+func collectMetrics(ctx context.Context, client *containerd.Client, options types.SystemMetricsServeOptions) ([]statsutil.LabeledSample, error) {
+	nsList := []string{options.GOptions.Namespace}
+	if options.AllNamespaces {
+		var err error
+		nsList, err = client.NamespaceService().List(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var samples []statsutil.LabeledSample
+	for _, ns := range nsList {
+		nsCtx := namespaces.WithNamespace(ctx, ns)
+		containers, err := client.Containers(nsCtx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range containers {
+			task, err := c.Task(nsCtx, nil)
+			if err != nil {
+				// The container has no task, i.e., it is not running
+				continue
+			}
+			status, err := task.Status(nsCtx)
+			if err != nil || status.Status != containerd.Running {
+				continue
+			}
+			sample, err := containerSample(nsCtx, c, task)
+			if err != nil {
+				// The container may have exited in the meantime
+				log.G(ctx).WithError(err).Debugf("failed to collect the metrics of container %s", c.ID())
+				continue
+			}
+			sample.Labels.Namespace = ns
+			samples = append(samples, sample)
+		}
+	}
+	return samples, nil
+}
+
+func containerSample(ctx context.Context, c containerd.Container, task containerd.Task) (statsutil.LabeledSample, error) {
+	info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
+	if err != nil {
+		return statsutil.LabeledSample{}, err
+	}
+	sample, err := collectSample(ctx, task)
+	if err != nil {
+		return statsutil.LabeledSample{}, err
+	}
+	return statsutil.LabeledSample{
+		Labels: statsutil.MetricLabels{
+			ID:             c.ID(),
+			Name:           containerutil.GetContainerName(info.Labels),
+			Image:          info.Image,
+			ComposeProject: info.Labels[labels.ComposeProject],
+		},
+		Sample: sample,
+	}, nil
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"context"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/typeurl/v2"
+
+	"github.com/containerd/nerdctl/v2/pkg/containerinspector"
+	"github.com/containerd/nerdctl/v2/pkg/statsutil"
+)
+
+func collectSample(ctx context.Context, task containerd.Task) (statsutil.Sample, error) {
+	metric, err := task.Metrics(ctx)
+	if err != nil {
+		return statsutil.Sample{}, err
+	}
+	anydata, err := typeurl.UnmarshalAny(metric.Data)
+	if err != nil {
+		return statsutil.Sample{}, err
+	}
+	netNS, err := containerinspector.InspectNetNS(ctx, int(task.Pid()))
+	if err != nil {
+		return statsutil.Sample{}, err
+	}
+	links, err := statsutil.NetworkLinks(int(task.Pid()), netNS.Interfaces)
+	if err != nil {
+		return statsutil.Sample{}, err
+	}
+	return statsutil.NewSample(anydata, links)
+}
@@ -21,9 +21,13 @@ import (
 
 	"github.com/docker/docker/pkg/stringid"
 
+	"github.com/containerd/log"
+
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumedriver"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
@@ -37,8 +41,23 @@ func Create(name string, options types.VolumeCreateOptions) (*native.Volume, err
 		return nil, err
 	}
 	labels := strutil.DedupeStrSlice(options.Labels)
-	vol, err := volStore.Create(name, labels)
+	opts := strutil.ConvertKVStringsToMap(options.Options)
+	var driver volumedriver.VolumeDriver
+	if options.Driver != "" && options.Driver != volumestore.LocalDriver {
+		if driver, err = volumedriver.Get(options.Driver); err != nil {
+			return nil, err
+		}
+		if err = driver.Create(name, opts); err != nil {
+			return nil, err
+		}
+	}
+	vol, err := volStore.Create(name, labels, volumestore.WithDriver(options.Driver, opts))
 	if err != nil {
+		if driver != nil {
+			if rmErr := driver.Remove(name); rmErr != nil {
+				log.L.WithError(rmErr).Warnf("failed to remove volume %q from driver %q", name, driver.Name())
+			}
+		}
 		return nil, err
 	}
 	fmt.Fprintln(options.Stdout, name)
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
@@ -64,6 +65,10 @@ func Prune(ctx context.Context, client *containerd.Client, options types.VolumeP
 					continue
 				}
 			}
+			if err := removeFromDriver(volume); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to remove volume %q", volume.Name)
+				continue
+			}
 			toRemove = append(toRemove, volume.Name)
 		}
 
@@ -28,8 +28,11 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumedriver"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 )
 
 func Remove(ctx context.Context, client *containerd.Client, volumes []string, options types.VolumeRemoveOptions) error {
@@ -43,6 +46,12 @@ func Remove(ctx context.Context, client *containerd.Client, volumes []string, op
 		return err
 	}
 
+	// The driver of a volume cannot change while it exists, so it is fine to look them up before locking
+	vols, err := volStore.List(false)
+	if err != nil {
+		return err
+	}
+
 	// Note: to avoid racy behavior, this is called by volStore.Remove *inside a lock*
 	removableVolumes := func() (volumeNames []string, cannotRemove []error, err error) {
 		usedVolumesList, err := usedVolumes(ctx, containers)
@@ -55,6 +64,12 @@ func Remove(ctx context.Context, client *containerd.Client, volumes []string, op
 				cannotRemove = append(cannotRemove, fmt.Errorf("volume %q is in use (%w)", name, errdefs.ErrFailedPrecondition))
 				continue
 			}
+			if vol, ok := vols[name]; ok {
+				if err := removeFromDriver(&vol); err != nil {
+					cannotRemove = append(cannotRemove, err)
+					continue
+				}
+			}
 			volumeNames = append(volumeNames, name)
 		}
 
@@ -79,8 +94,65 @@ func Remove(ctx context.Context, client *containerd.Client, volumes []string, op
 	return nil
 }
 
+// UnmountUnused unmounts the given volumes, if they were created with options and no container uses them anymore.
+func UnmountUnused(ctx context.Context, client *containerd.Client, volStore volumestore.VolumeStore, volumes []string) error {
+	if len(volumes) == 0 {
+		return nil
+	}
+	if err := volStore.Lock(); err != nil {
+		return err
+	}
+	defer volStore.Release()
+
+	containers, err := client.Containers(ctx)
+	if err != nil {
+		return err
+	}
+	usedVolumesList, err := usedVolumes(ctx, containers)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, name := range volumes {
+		if _, ok := usedVolumesList[name]; ok {
+			continue
+		}
+		if err := volStore.UnmountWithoutLock(name); err != nil && !errors.Is(err, errdefs.ErrNotFound) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// removeFromDriver removes the data of a volume managed by a volume driver other than "local".
+// The data of local volumes is removed along with the volume by the volume store.
+func removeFromDriver(vol *native.Volume) error {
+	if vol.Driver == "" || vol.Driver == volumestore.LocalDriver {
+		return nil
+	}
+	driver, err := volumedriver.Get(vol.Driver)
+	if err != nil {
+		return err
+	}
+	return driver.Remove(vol.Name)
+}
+
 func usedVolumes(ctx context.Context, containers []containerd.Container) (map[string]struct{}, error) {
-	usedVolumesList := make(map[string]struct{})
+	links, err := Links(ctx, containers)
+	if err != nil {
+		return nil, err
+	}
+	usedVolumesList := make(map[string]struct{}, len(links))
+	for name := range links {
+		usedVolumesList[name] = struct{}{}
+	}
+	return usedVolumesList, nil
+}
+
+// Links returns the number of containers using each volume, for the volumes used by at least one container.
+func Links(ctx context.Context, containers []containerd.Container) (map[string]int, error) {
+	links := make(map[string]int)
 	for _, c := range containers {
 		l, err := c.Labels(ctx)
 		if err != nil {
@@ -104,9 +176,9 @@ func usedVolumes(ctx context.Context, containers []containerd.Container) (map[st
 		}
 		for _, m := range mounts {
 			if m.Type == mountutil.Volume {
-				usedVolumesList[m.Name] = struct{}{}
+				links[m.Name]++
 			}
 		}
 	}
-	return usedVolumesList, nil
+	return links, nil
 }
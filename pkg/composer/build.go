@@ -20,11 +20,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/compose-spec/compose-go/v2/types"
 
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/composer/pipetagger"
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
 )
 
@@ -32,11 +34,13 @@ type BuildOptions struct {
 	Args     []string // --build-arg strings
 	NoCache  bool
 	Progress string
+	tag      string // tag of the lines of the output, for concurrent builds
+	tagWidth int
 }
 
 func (c *Composer) Build(ctx context.Context, bo BuildOptions, services []string) error {
 	return c.project.ForEachService(services, func(names string, svc *types.ServiceConfig) error {
-		ps, err := serviceparser.Parse(c.project, *svc)
+		ps, err := c.parseService(*svc)
 		if err != nil {
 			return err
 		}
@@ -62,6 +66,9 @@ func (c *Composer) buildServiceImage(ctx context.Context, image string, b *servi
 	}
 	if bo.Progress != "" {
 		args = append(args, "--progress="+bo.Progress)
+	} else if bo.tag != "" {
+		// the tty progress cannot be tagged
+		args = append(args, "--progress=plain")
 	}
 	args = append(args, b.BuildArgs...)
 
@@ -69,9 +76,43 @@ func (c *Composer) buildServiceImage(ctx context.Context, image string, b *servi
 	if c.DebugPrintFull {
 		log.G(ctx).Debugf("Running %v", cmd.Args)
 	}
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if bo.tag == "" {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("error while building image %s: %w", image, err)
+		}
+		return nil
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error while building image %s: %w", image, err)
+	}
+	var taggerWG sync.WaitGroup
+	for _, x := range []*pipetagger.PipeTagger{
+		pipetagger.New(os.Stdout, stdout, bo.tag, bo.tagWidth, false),
+		pipetagger.New(os.Stderr, stderr, bo.tag, bo.tagWidth, false),
+	} {
+		x := x
+		taggerWG.Add(1)
+		go func() {
+			defer taggerWG.Done()
+			if err := x.Run(); err != nil {
+				log.G(ctx).WithError(err).Debug("failed to read the output of the build")
+			}
+		}()
+	}
+	// the pipes must be read entirely before calling Wait
+	taggerWG.Wait()
+	if err := cmd.Wait(); err != nil {
 		return fmt.Errorf("error while building image %s: %w", image, err)
 	}
 	return nil
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os/exec"
 
 	composecli "github.com/compose-spec/compose-go/v2/cli"
@@ -29,6 +30,7 @@ import (
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
 	"github.com/containerd/nerdctl/v2/pkg/identifiers"
 	"github.com/containerd/nerdctl/v2/pkg/reflectutil"
@@ -48,10 +50,16 @@ type Options struct {
 	NetworkExists    func(string) (bool, error)
 	VolumeExists     func(string) (bool, error)
 	ImageExists      func(ctx context.Context, imageName string) (bool, error)
-	EnsureImage      func(ctx context.Context, imageName, pullMode, platform string, ps *serviceparser.Service, quiet bool) error
+	EnsureImage      func(ctx context.Context, imageName, pullMode, platform string, ps *serviceparser.Service, quiet bool, progressOutput io.Writer) error
 	DebugPrintFull   bool // full debug print, may leak secret env var to logs
 	Experimental     bool // enable experimental features
 	IPFSAddress      string
+	FilesDir         string // directory for the secrets and configs written for the containers, e.g., "<DATAROOT>/<ADDRHASH>/compose/<NAMESPACE>"
+	FilesOnDisk      bool   // allow writing the secrets and configs on the disk when a tmpfs cannot be mounted
+	GlobalOptions    types.GlobalCommandOptions
+	// RunDefaults are the options of `nerdctl run` without any flag, on which the options of the containers
+	// created in-process are built.
+	RunDefaults serviceparser.Defaults
 }
 
 func New(o Options, client *containerd.Client) (*Composer, error) {
@@ -145,12 +153,17 @@ func (c *Composer) runNerdctlCmd(ctx context.Context, args ...string) error {
 	return nil
 }
 
+// parseService parses the service, building the options of its containers on RunDefaults.
+func (c *Composer) parseService(svc compose.ServiceConfig) (*serviceparser.Service, error) {
+	return serviceparser.Parse(c.project, svc, c.RunDefaults)
+}
+
 // Services returns the parsed Service objects in dependency order.
 func (c *Composer) Services(ctx context.Context, svcs ...string) ([]*serviceparser.Service, error) {
 	var services []*serviceparser.Service
 
 	if err := c.project.ForEachService(svcs, func(name string, svc *compose.ServiceConfig) error {
-		parsed, err := serviceparser.Parse(c.project, *svc)
+		parsed, err := c.parseService(*svc)
 		if err != nil {
 			return err
 		}
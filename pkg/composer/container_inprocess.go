@@ -0,0 +1,160 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/portutil"
+)
+
+// The containers are created, started, stopped and removed in-process with the containerd client of the composer,
+// rather than by running NerdctlCmd, which costs seconds per container on large projects.
+// serviceparser builds the options of container.Create on the defaults of `nerdctl run` (Options.RunDefaults),
+// and the services attached to the terminal (`tty` or `stdin_open`) are attached to the stdio of the process.
+
+// createContainer creates a container like `nerdctl create`, or like `nerdctl run` when start is true,
+// and returns its ID. Unless the container is detached, it is attached to the stdio of the process until it exits.
+func (c *Composer) createContainer(ctx context.Context, parsed serviceparser.Container, start bool) (string, error) {
+	opt := parsed.CreateOptions
+	netOpts := parsed.NetworkOptions
+	if !start {
+		// same as `nerdctl create`
+		opt.InRun = false
+		opt.Detach = true
+		opt.Interactive = false
+	}
+
+	// the ports are parsed when creating the container, as the ports without a host port are allocated
+	portMappings := slices.Clone(netOpts.PortMappings)
+	for _, p := range parsed.Publish {
+		pm, err := portutil.ParseFlagP(p)
+		if err != nil {
+			return "", err
+		}
+		portMappings = append(portMappings, pm...)
+	}
+	netOpts.PortMappings = portMappings
+
+	if c.DebugPrintFull {
+		log.G(ctx).Debugf("Creating container %s with %+v and %+v from %v", parsed.Name, opt, netOpts, parsed.Args)
+	}
+	netManager, err := containerutil.NewNetworkingOptionsManager(opt.GOptions, netOpts, c.client)
+	if err != nil {
+		return "", err
+	}
+	ctr, gc, err := container.Create(ctx, c.client, parsed.Args, netManager, opt)
+	if err != nil {
+		if gc != nil {
+			gc()
+		}
+		return "", err
+	}
+	if !start {
+		return ctr.ID(), nil
+	}
+	if !opt.Detach {
+		if err := c.attachContainer(ctx, ctr.ID(), opt); err != nil {
+			return "", err
+		}
+		return ctr.ID(), nil
+	}
+	if err := c.startContainer(ctx, ctr.ID()); err != nil {
+		containerutil.UpdateErrorLabel(ctx, ctr, err)
+		return "", err
+	}
+	return ctr.ID(), nil
+}
+
+// composeLabels returns the metadata labels of the containers of the service,
+// https://github.com/compose-spec/compose-spec/blob/master/spec.md#labels
+func (c *Composer) composeLabels(service *serviceparser.Service) []string {
+	return []string{
+		fmt.Sprintf("%s=%s", labels.ComposeProject, c.project.Name),
+		fmt.Sprintf("%s=%s", labels.ComposeService, service.Unparsed.Name),
+		fmt.Sprintf("%s=%s", labels.ComposeConfigFiles, strings.Join(c.project.ComposeFiles, ",")),
+		fmt.Sprintf("%s=%s", labels.ComposeWorkingDir, c.project.WorkingDir),
+	}
+}
+
+// attachContainer starts a created or stopped container attached to the stdio of the process, like
+// `nerdctl start --attach`, with stdin when opt.Interactive is set, and waits for it to exit.
+func (c *Composer) attachContainer(ctx context.Context, id string, opt types.ContainerCreateOptions) error {
+	err := container.Start(ctx, c.client, []string{id}, types.ContainerStartOptions{
+		Stdout:      io.Discard,
+		GOptions:    c.GlobalOptions,
+		Attach:      true,
+		Interactive: opt.Interactive,
+		DetachKeys:  opt.DetachKeys,
+	})
+	// the exit status of the container is not an exit status of compose, which prints the error
+	var exitErr errutil.ExitCoder
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("exit status %d", exitErr.ExitCode())
+	}
+	return err
+}
+
+// startContainer starts a created or stopped container without attaching to it.
+func (c *Composer) startContainer(ctx context.Context, id string) error {
+	return container.Start(ctx, c.client, []string{id}, types.ContainerStartOptions{
+		Stdout:   io.Discard,
+		GOptions: c.GlobalOptions,
+	})
+}
+
+// stopContainer stops a container, like `nerdctl stop`. The timeout defaults to 10 seconds.
+func (c *Composer) stopContainer(ctx context.Context, id string, timeout *time.Duration) error {
+	return container.Stop(ctx, c.client, []string{id}, types.ContainerStopOptions{
+		Stdout:   io.Discard,
+		Stderr:   io.Discard,
+		GOptions: c.GlobalOptions,
+		Timeout:  timeout,
+	})
+}
+
+// removeContainer removes a container forcibly, like `nerdctl rm -f`,
+// along with the secrets and configs written for the container named `name`.
+func (c *Composer) removeContainer(ctx context.Context, id, name string, volumes bool) error {
+	if err := container.Remove(ctx, c.client, []string{id}, types.ContainerRemoveOptions{
+		Stdout:   io.Discard,
+		GOptions: c.GlobalOptions,
+		Force:    true,
+		Volumes:  volumes,
+	}); err != nil {
+		return err
+	}
+	// soft failure, the files are removed again on `compose down`
+	if err := c.removeContainerFiles(ctx, name); err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to remove the secrets and configs of container %s", name)
+	}
+	return nil
+}
@@ -19,9 +19,7 @@ package composer
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
+	"slices"
 
 	"github.com/compose-spec/compose-go/v2/types"
 	"golang.org/x/sync/errgroup"
@@ -29,7 +27,6 @@ import (
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
-	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
 // FYI: https://github.com/docker/compose/blob/v2.14.1/pkg/api/api.go#L423
@@ -113,15 +110,12 @@ func (c *Composer) Create(ctx context.Context, opt CreateOptions, services []str
 	}
 
 	// ensure images
-	// TODO: parallelize loop for ensuring images (make sure not to mess up tty)
 	parsedServices, err := c.Services(ctx, services...)
 	if err != nil {
 		return err
 	}
-	for _, ps := range parsedServices {
-		if err := c.ensureServiceImage(ctx, ps, !opt.NoBuild, opt.Build, BuildOptions{}, false, ""); err != nil {
-			return err
-		}
+	if err := c.ensureServiceImages(ctx, parsedServices, !opt.NoBuild, opt.Build, BuildOptions{}, false, ""); err != nil {
+		return err
 	}
 
 	for _, ps := range parsedServices {
@@ -149,7 +143,7 @@ func (c *Composer) createService(ctx context.Context, ps *serviceparser.Service,
 	return runEG.Wait()
 }
 
-// createServiceContainer must be called after ensureServiceImage
+// createServiceContainer must be called after ensureServiceImages
 // createServiceContainer returns container ID
 // TODO(djdongjin): refactor needed:
 // 1. the logic is similar to `upServiceContainer`, need to decouple some of the logic.
@@ -170,8 +164,7 @@ func (c *Composer) createServiceContainer(ctx context.Context, service *servicep
 		}
 
 		log.G(ctx).Debugf("Container %q already exists and force-created is enabled, deleting", container.Name)
-		delCmd := c.createNerdctlCmd(ctx, "rm", "-f", container.Name)
-		if err = delCmd.Run(); err != nil {
+		if err = c.removeContainer(ctx, container.Name, container.Name, false); err != nil {
 			return "", fmt.Errorf("could not delete container %q: %w", container.Name, err)
 		}
 		log.G(ctx).Infof("Re-creating container %s", container.Name)
@@ -179,38 +172,22 @@ func (c *Composer) createServiceContainer(ctx context.Context, service *servicep
 		log.G(ctx).Infof("Creating container %s", container.Name)
 	}
 
-	tempDir, err := os.MkdirTemp(os.TempDir(), "compose-")
+	fileVolumes, err := c.writeFiles(ctx, container)
 	if err != nil {
-		return "", fmt.Errorf("error while creating/re-creating container %s: %w", container.Name, err)
-	}
-	defer os.RemoveAll(tempDir)
-	cidFilename := filepath.Join(tempDir, "cid")
-
-	//add metadata labels to container https://github.com/compose-spec/compose-spec/blob/master/spec.md#labels
-	container.RunArgs = append([]string{
-		"--cidfile=" + cidFilename,
-		fmt.Sprintf("-l=%s=%s", labels.ComposeProject, c.project.Name),
-		fmt.Sprintf("-l=%s=%s", labels.ComposeService, service.Unparsed.Name),
-	}, container.RunArgs...)
-
-	cmd := c.createNerdctlCmd(ctx, append([]string{"create"}, container.RunArgs...)...)
-	if c.DebugPrintFull {
-		log.G(ctx).Debugf("Running %v", cmd.Args)
+		return "", fmt.Errorf("error while writing the secrets and configs of container %s: %w", container.Name, err)
 	}
+	container.CreateOptions.Volume = slices.Concat(fileVolumes, container.CreateOptions.Volume)
+
+	container.CreateOptions.Label = slices.Concat(c.composeLabels(service), container.CreateOptions.Label)
 
 	// FIXME
 	if service.Unparsed.StdinOpen != service.Unparsed.Tty {
 		return "", fmt.Errorf("currently StdinOpen(-i) and Tty(-t) should be same")
 	}
 
-	err = cmd.Run()
-	if err != nil {
-		return "", fmt.Errorf("error while creating container %s: %w", container.Name, err)
-	}
-
-	cid, err := os.ReadFile(cidFilename)
+	cid, err := c.createContainer(ctx, container, false)
 	if err != nil {
 		return "", fmt.Errorf("error while creating container %s: %w", container.Name, err)
 	}
-	return strings.TrimSpace(string(cid)), nil
+	return cid, nil
 }
@@ -83,6 +83,10 @@ func (c *Composer) Down(ctx context.Context, downOptions DownOptions) error {
 		}
 	}
 
+	if err := c.removeFiles(ctx); err != nil {
+		log.G(ctx).WithError(err).Warn("failed to remove the secrets and configs")
+	}
+
 	return nil
 }
 
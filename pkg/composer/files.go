@@ -0,0 +1,138 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+)
+
+// filesDir returns the directory of the secrets and configs written for the container.
+func (c *Composer) filesDir(containerName string) string {
+	return filepath.Join(c.FilesDir, c.project.Name, containerName)
+}
+
+// writeFiles writes the secrets and configs of the container that cannot be bind-mounted from the host,
+// and returns the volumes (`-v`) for mounting them.
+//
+// The files are written on a tmpfs, so as to never reach the disk, unless FilesOnDisk allows writing them on the disk
+// when the tmpfs cannot be mounted. They are removed with the container, and by removeFiles on `compose down`.
+func (c *Composer) writeFiles(ctx context.Context, container serviceparser.Container) ([]string, error) {
+	if len(container.Files) == 0 {
+		return nil, nil
+	}
+	if c.FilesDir == "" {
+		return nil, errors.New("got empty directory for the secrets and configs")
+	}
+	dir := c.filesDir(container.Name)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	if err := mountFilesDir(dir); err != nil {
+		if !c.FilesOnDisk {
+			return nil, fmt.Errorf("failed to mount a tmpfs on %q for the secrets and configs of container %s "+
+				"(Hint: specify --secrets-on-disk to write them on the disk instead): %w", dir, container.Name, err)
+		}
+		log.G(ctx).WithError(err).Warnf("failed to mount a tmpfs on %q, the secrets and configs of container %s are written on the disk", dir, container.Name)
+		if err := removeDirContent(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	var volumes []string
+	for _, f := range container.Files {
+		content := f.Content
+		if content == nil {
+			b, err := os.ReadFile(f.Source)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %q: %w", f.Source, err)
+			}
+			content = b
+		}
+		p := filepath.Join(dir, f.Name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(p, content, f.Mode); err != nil {
+			return nil, err
+		}
+		// os.WriteFile does not apply the mode when the umask masks it
+		if err := os.Chmod(p, f.Mode); err != nil {
+			return nil, err
+		}
+		if f.UID >= 0 || f.GID >= 0 {
+			if err := os.Chown(p, f.UID, f.GID); err != nil {
+				return nil, fmt.Errorf("failed to change the owner of %q: %w", f.Name, err)
+			}
+		}
+		volumes = append(volumes, fmt.Sprintf("%s:%s:ro", p, f.Target))
+	}
+	return volumes, nil
+}
+
+// removeFiles removes the secrets and configs written for the containers of the project.
+func (c *Composer) removeFiles(ctx context.Context) error {
+	if c.FilesDir == "" {
+		return nil
+	}
+	projectDir := filepath.Join(c.FilesDir, c.project.Name)
+	entries, err := os.ReadDir(projectDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		if err := c.removeContainerFiles(ctx, e.Name()); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(projectDir)
+}
+
+// removeContainerFiles removes the secrets and configs written for the container, if any.
+func (c *Composer) removeContainerFiles(ctx context.Context, containerName string) error {
+	if c.FilesDir == "" || containerName == "" {
+		return nil
+	}
+	dir := c.filesDir(containerName)
+	if err := unmountFilesDir(dir); err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to unmount %q", dir)
+	}
+	return os.RemoveAll(dir)
+}
+
+func removeDirContent(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
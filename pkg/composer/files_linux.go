@@ -0,0 +1,40 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// mountFilesDir mounts a new tmpfs on dir, replacing the one of a previous run if any.
+func mountFilesDir(dir string) error {
+	if err := unmountFilesDir(dir); err != nil {
+		return err
+	}
+	return unix.Mount("tmpfs", dir, "tmpfs", uintptr(unix.MS_NOEXEC|unix.MS_NOSUID|unix.MS_NODEV), "mode=0700")
+}
+
+// unmountFilesDir unmounts the tmpfs of dir, if it is mounted.
+func unmountFilesDir(dir string) error {
+	// the files may still be bind-mounted in running containers, hence MNT_DETACH
+	if err := unix.Unmount(dir, unix.MNT_DETACH); err != nil && !errors.Is(err, unix.EINVAL) && !errors.Is(err, unix.ENOENT) {
+		return err
+	}
+	return nil
+}
@@ -46,3 +46,13 @@ func Lock(dataRoot string, address string) error {
 func Unlock() error {
 	return filesystem.Unlock(locked)
 }
+
+// withLock runs fn while holding the global lock, for the operations of the long-running commands that have released it,
+// e.g., the rebuilds of `compose watch`.
+func withLock(dataRoot string, address string, fn func() error) error {
+	dataStore, err := clientutil.DataStore(dataRoot, address)
+	if err != nil {
+		return err
+	}
+	return filesystem.WithLock(dataStore, fn)
+}
@@ -42,6 +42,17 @@ type LogsOptions struct {
 	NoColor              bool
 	NoLogPrefix          bool
 	LatestRun            bool
+
+	// recreated is used by `up --watch` to follow the containers recreated by a rebuild
+	recreated <-chan recreateEvent
+}
+
+// recreateEvent is sent to logs before and after the containers of a service are recreated,
+// so that the logs of the new containers are followed, instead of reporting the old ones as exited.
+type recreateEvent struct {
+	service    string
+	done       bool                   // false before the recreation
+	containers []containerd.Container // the containers of the service after the recreation
 }
 
 func (c *Composer) Logs(ctx context.Context, lo LogsOptions, services []string) error {
@@ -72,36 +83,47 @@ func (c *Composer) logs(ctx context.Context, containers []containerd.Container,
 	var logTagMaxLen int
 	type containerState struct {
 		name      string
+		service   string
 		logTag    string
 		logCmd    *exec.Cmd
 		startedAt string
 	}
 
-	containerStates := make(map[string]containerState, len(containers)) // key: containerID
-	for _, container := range containers {
+	containerStates := make(map[string]*containerState, len(containers)) // key: containerID
+	newState := func(container containerd.Container) (*containerState, error) {
 		info, err := container.Info(ctx, containerd.WithoutRefreshedMetadata)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		name := info.Labels[labels.Name]
-		logTag := strings.TrimPrefix(name, c.project.Name+serviceparser.Separator)
-		if l := len(logTag); l > logTagMaxLen {
-			logTagMaxLen = l
-		}
 		ts, err := info.UpdatedAt.MarshalText()
 		if err != nil {
-			return err
+			return nil, err
 		}
-
-		containerStates[container.ID()] = containerState{
+		return &containerState{
 			name:      name,
-			logTag:    logTag,
+			service:   info.Labels[labels.ComposeService],
+			logTag:    strings.TrimPrefix(name, c.project.Name+serviceparser.Separator),
 			startedAt: string(ts),
+		}, nil
+	}
+	for _, container := range containers {
+		state, err := newState(container)
+		if err != nil {
+			return err
 		}
+		if l := len(state.logTag); l > logTagMaxLen {
+			logTagMaxLen = l
+		}
+		containerStates[container.ID()] = state
 	}
 
-	logsEOFChan := make(chan string) // value: container name
-	for id, state := range containerStates {
+	type logsEOF struct {
+		id   string
+		name string
+	}
+	logsEOFChan := make(chan logsEOF)
+	startLogs := func(id string, state *containerState, since string) error {
 		// TODO: show logs without executing `nerdctl logs`
 		args := []string{"logs"}
 		if lo.Follow {
@@ -118,8 +140,8 @@ func (c *Composer) logs(ctx context.Context, containers []containerd.Container,
 				args = append(args, lo.Tail)
 			}
 		}
-		if lo.LatestRun {
-			args = append(args, fmt.Sprintf("--since=%s", state.startedAt))
+		if since != "" {
+			args = append(args, fmt.Sprintf("--since=%s", since))
 		}
 
 		args = append(args, id)
@@ -147,15 +169,31 @@ func (c *Composer) logs(ctx context.Context, containers []containerd.Container,
 		containerName := state.name
 		go func() {
 			stdoutTagger.Run()
-			logsEOFChan <- containerName
+			logsEOFChan <- logsEOF{id: id, name: containerName}
 		}()
 		go stderrTagger.Run()
+		return nil
+	}
+	for id, state := range containerStates {
+		var since string
+		if lo.LatestRun {
+			since = state.startedAt
+		}
+		if err := startLogs(id, state, since); err != nil {
+			return err
+		}
 	}
 
 	interruptChan := make(chan os.Signal, 1)
 	signal.Notify(interruptChan, os.Interrupt)
+	defer signal.Stop(interruptChan)
 
 	logsEOFMap := make(map[string]struct{}) // key: container name
+	recreating := make(map[string]struct{}) // key: service name
+	currentIDs := make(map[string]string)   // key: container name, value: ID of the followed container
+	for id, state := range containerStates {
+		currentIDs[state.name] = id
+	}
 	var containerError error
 selectLoop:
 	for {
@@ -164,21 +202,53 @@ selectLoop:
 		case sig := <-interruptChan:
 			log.G(ctx).Debugf("Received signal: %s", sig)
 			break selectLoop
-		case containerName := <-logsEOFChan:
+		case ev := <-lo.recreated:
+			if !ev.done {
+				recreating[ev.service] = struct{}{}
+				continue
+			}
+			delete(recreating, ev.service)
+			for _, container := range ev.containers {
+				if _, ok := containerStates[container.ID()]; ok {
+					// not recreated
+					continue
+				}
+				state, err := newState(container)
+				if err != nil {
+					log.G(ctx).WithError(err).Warnf("failed to follow the logs of the containers of service %s", ev.service)
+					continue
+				}
+				containerStates[container.ID()] = state
+				currentIDs[state.name] = container.ID()
+				delete(logsEOFMap, state.name)
+				// the new container only has the logs of its own run
+				if err := startLogs(container.ID(), state, ""); err != nil {
+					log.G(ctx).WithError(err).Warnf("failed to follow the logs of container %s", state.name)
+				}
+			}
+		case eof := <-logsEOFChan:
+			if currentIDs[eof.name] != eof.id {
+				log.G(ctx).Debugf("Logs for the replaced container %q reached EOF", eof.name)
+				continue
+			}
 			if lo.Follow {
+				if _, ok := recreating[containerStates[eof.id].service]; ok {
+					log.G(ctx).Debugf("Container %q is being recreated", eof.name)
+					continue
+				}
 				// When `nerdctl logs -f` has exited, we can assume that the container has exited
-				log.G(ctx).Infof("Container %q exited", containerName)
+				log.G(ctx).Infof("Container %q exited", eof.name)
 				// In case a container has exited and the parameter --abort-on-container-exit,
 				// we break the loop and set an error, so we can exit the program with 1
 				if lo.AbortOnContainerExit {
-					containerError = fmt.Errorf("container %q exited", containerName)
+					containerError = fmt.Errorf("container %q exited", eof.name)
 					break selectLoop
 				}
 			} else {
-				log.G(ctx).Debugf("Logs for container %q reached EOF", containerName)
+				log.G(ctx).Debugf("Logs for container %q reached EOF", eof.name)
 			}
-			logsEOFMap[containerName] = struct{}{}
-			if len(logsEOFMap) == len(containerStates) {
+			logsEOFMap[eof.name] = struct{}{}
+			if len(logsEOFMap) == len(currentIDs) {
 				if lo.Follow {
 					log.G(ctx).Info("All the containers have exited")
 				} else {
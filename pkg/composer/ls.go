@@ -0,0 +1,115 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// ProjectSummary is a compose project, as recorded in the labels of its containers.
+type ProjectSummary struct {
+	Name string
+	// Status counts the containers by status, e.g., "running(2), exited(1)"
+	Status      string
+	ConfigFiles string
+}
+
+// ListProjects lists the compose projects of the containers of the namespace, sorted by name.
+// Unless all is set, only the running containers are taken into account.
+// Unlike the other operations, it does not need a compose file.
+func ListProjects(ctx context.Context, client *containerd.Client, all bool) ([]ProjectSummary, error) {
+	containers, err := client.Containers(ctx, fmt.Sprintf("labels.%q", labels.ComposeProject))
+	if err != nil {
+		return nil, err
+	}
+	type project struct {
+		statuses    map[string]int
+		configFiles []string
+	}
+	projects := make(map[string]*project)
+	for _, container := range containers {
+		info, err := container.Info(ctx, containerd.WithoutRefreshedMetadata)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		status := projectContainerStatus(ctx, container)
+		if !all && status != string(containerd.Running) {
+			continue
+		}
+		name := info.Labels[labels.ComposeProject]
+		p, ok := projects[name]
+		if !ok {
+			p = &project{statuses: make(map[string]int)}
+			projects[name] = p
+		}
+		p.statuses[status]++
+		for _, f := range strings.Split(info.Labels[labels.ComposeConfigFiles], ",") {
+			if f != "" && !slices.Contains(p.configFiles, f) {
+				p.configFiles = append(p.configFiles, f)
+			}
+		}
+	}
+
+	summaries := make([]ProjectSummary, 0, len(projects))
+	for name, p := range projects {
+		statuses := make([]string, 0, len(p.statuses))
+		for status, n := range p.statuses {
+			statuses = append(statuses, fmt.Sprintf("%s(%d)", status, n))
+		}
+		sort.Strings(statuses)
+		summaries = append(summaries, ProjectSummary{
+			Name:        name,
+			Status:      strings.Join(statuses, ", "),
+			ConfigFiles: strings.Join(p.configFiles, ","),
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries, nil
+}
+
+// projectContainerStatus returns the status of the container as shown by Docker Compose, e.g., "exited" rather than "stopped".
+func projectContainerStatus(ctx context.Context, container containerd.Container) string {
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return string(containerd.Created)
+		}
+		return string(containerd.Unknown)
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return string(containerd.Unknown)
+	}
+	if status.Status == containerd.Stopped {
+		return "exited"
+	}
+	return string(status.Status)
+}
@@ -34,7 +34,7 @@ type PullOptions struct {
 
 func (c *Composer) Pull(ctx context.Context, po PullOptions, services []string) error {
 	return c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
-		ps, err := serviceparser.Parse(c.project, *svc)
+		ps, err := c.parseService(*svc)
 		if err != nil {
 			return err
 		}
@@ -70,6 +70,9 @@ func (c *Composer) pullServiceImage(ctx context.Context, image string, platform
 	if certificateOidcIssuerRegexp, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateOidcIssuerRegexp]; ok {
 		args = append(args, "--cosign-certificate-oidc-issuer-regexp="+certificateOidcIssuerRegexp.(string))
 	}
+	if ignoreTlog, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignInsecureIgnoreTlog].(bool); ok && ignoreTlog {
+		args = append(args, "--cosign-insecure-ignore-tlog")
+	}
 
 	if c.Options.Experimental {
 		args = append(args, "--experimental")
@@ -33,7 +33,7 @@ type PushOptions struct {
 
 func (c *Composer) Push(ctx context.Context, po PushOptions, services []string) error {
 	return c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
-		ps, err := serviceparser.Parse(c.project, *svc)
+		ps, err := c.parseService(*svc)
 		if err != nil {
 			return err
 		}
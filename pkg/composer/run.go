@@ -182,7 +182,7 @@ func (c *Composer) Run(ctx context.Context, ro RunOptions) error {
 
 	parsedServices := make([]*serviceparser.Service, 0)
 	for _, svc := range svcs {
-		ps, err := serviceparser.Parse(c.project, svc)
+		ps, err := c.parseService(svc)
 		if err != nil {
 			return err
 		}
@@ -213,11 +213,8 @@ func (c *Composer) runServices(ctx context.Context, parsedServices []*servicepar
 		return errors.New("no service was provided")
 	}
 
-	// TODO: parallelize loop for ensuring images (make sure not to mess up tty)
-	for _, ps := range parsedServices {
-		if err := c.ensureServiceImage(ctx, ps, !ro.NoBuild, ro.ForceBuild, BuildOptions{}, ro.QuietPull, ""); err != nil {
-			return err
-		}
+	if err := c.ensureServiceImages(ctx, parsedServices, !ro.NoBuild, ro.ForceBuild, BuildOptions{}, ro.QuietPull, ""); err != nil {
+		return err
 	}
 
 	var (
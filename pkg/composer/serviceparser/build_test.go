@@ -71,7 +71,7 @@ secrets:
 	fooSvc, err := project.GetService("foo")
 	assert.NilError(t, err)
 
-	foo, err := Parse(project, fooSvc)
+	foo, err := Parse(project, fooSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("foo: %+v", foo)
@@ -82,7 +82,7 @@ secrets:
 	barSvc, err := project.GetService("bar")
 	assert.NilError(t, err)
 
-	bar, err := Parse(project, barSvc)
+	bar, err := Parse(project, barSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("bar: %+v", bar)
@@ -19,11 +19,14 @@ package serviceparser
 import (
 	"bytes"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -33,8 +36,12 @@ import (
 	"github.com/containerd/containerd/v2/contrib/nvidia"
 	"github.com/containerd/log"
 
+	apitypes "github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/identifiers"
+	"github.com/containerd/nerdctl/v2/pkg/portutil"
 	"github.com/containerd/nerdctl/v2/pkg/reflectutil"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
 // ComposeExtensionKey defines fields used to implement extension features.
@@ -47,6 +54,8 @@ const (
 	ComposeCosignCertificateIdentityRegexp   = "x-nerdctl-cosign-certificate-identity-regexp"
 	ComposeCosignCertificateOidcIssuer       = "x-nerdctl-cosign-certificate-oidc-issuer"
 	ComposeCosignCertificateOidcIssuerRegexp = "x-nerdctl-cosign-certificate-oidc-issuer-regexp"
+	ComposeCosignInsecureIgnoreTlog          = "x-nerdctl-cosign-insecure-ignore-tlog"
+	ComposeHealthOnFailure                   = "x-nerdctl-health-on-failure"
 )
 
 // Separator is used for naming components (e.g., service image or container)
@@ -69,6 +78,7 @@ func warnUnknownFields(svc types.ServiceConfig) {
 		"ContainerName",
 		"DependsOn",
 		"Deploy",
+		"Develop", // handled by `compose watch`
 		"Devices",
 		"Dockerfile", // handled by the loader (normalizer)
 		"DNS",
@@ -76,9 +86,11 @@ func warnUnknownFields(svc types.ServiceConfig) {
 		"DNSOpts",
 		"Entrypoint",
 		"Environment",
+		"Expose",
 		"Extends", // handled by the loader
 		"Extensions",
 		"ExtraHosts",
+		"HealthCheck",
 		"Hostname",
 		"Image",
 		"Init",
@@ -125,11 +137,12 @@ func warnUnknownFields(svc types.ServiceConfig) {
 	for depName, dep := range svc.DependsOn {
 		if unknown := reflectutil.UnknownNonEmptyFields(&dep,
 			"Condition",
+			"Required",
 		); len(unknown) > 0 {
 			log.L.Warnf("Ignoring: service %s: depends_on: %s: %+v", svc.Name, depName, unknown)
 		}
 		switch dep.Condition {
-		case "", types.ServiceConditionStarted:
+		case "", types.ServiceConditionStarted, types.ServiceConditionHealthy, types.ServiceConditionCompletedSuccessfully:
 			// NOP
 		default:
 			log.L.Warnf("Ignoring: service %s: depends_on: %s: condition %s", svc.Name, depName, dep.Condition)
@@ -189,11 +202,37 @@ func warnUnknownFields(svc types.ServiceConfig) {
 }
 
 type Container struct {
-	Name    string   // e.g., "compose-wordpress_wordpress_1"
-	RunArgs []string // {"--pull=never", ...}
-	Mkdir   []string // For Bind.CreateHostPath
+	Name           string                          // e.g., "compose-wordpress_wordpress_1"
+	CreateOptions  apitypes.ContainerCreateOptions // the options of container.Create, built on Defaults.CreateOptions
+	NetworkOptions apitypes.NetworkOptions         // built on Defaults.NetworkOptions, without the published ports
+	Publish        []string                        // {"8080:80/tcp", ...}, parsed when creating the container, as they may allocate host ports
+	Args           []string                        // the image and the command, e.g., {"wordpress:5.7"}
+	Mkdir          []string                        // For Bind.CreateHostPath
+	Files          []File                          // For the secrets and configs that are not bind-mounted from the host
 }
 
+// Defaults are the options of `nerdctl run` without any flag, on which the options of the containers are built,
+// so that the fields that are not set by the service have the same defaults as `nerdctl run`.
+type Defaults struct {
+	CreateOptions  apitypes.ContainerCreateOptions
+	NetworkOptions apitypes.NetworkOptions
+}
+
+// File is a secret or a config that is written by nerdctl for the container, rather than bind-mounted from the host,
+// because its content comes from an environment variable or is inline, or for applying its uid, gid and mode.
+type File struct {
+	Name    string // relative to the directory of the files of the container, e.g., "secrets/foo"
+	Target  string // e.g., "/run/secrets/foo"
+	Source  string // path of the file on the host, when Content is nil
+	Content []byte
+	UID     int // -1 for unchanged
+	GID     int // -1 for unchanged
+	Mode    os.FileMode
+}
+
+// defaultFileMode is the mode of the secrets and configs, when not specified.
+const defaultFileMode = 0o444
+
 type Build struct {
 	Force     bool     // force build even if already present
 	BuildArgs []string // {"-t", "example.com/foo", "--target", "foo", "/path/to/ctx"}
@@ -356,6 +395,64 @@ func getRestart(svc types.ServiceConfig) (string, error) {
 	return restartFlag, nil
 }
 
+// setHealthcheck sets the healthcheck options of `nerdctl run`, i.e., `--health-*` and `--no-healthcheck`
+//
+// healthcheck: {test, interval, timeout, retries, start_period, start_interval, disable} (https://github.com/compose-spec/compose-spec/blob/master/spec.md#healthcheck)
+func setHealthcheck(opt *apitypes.ContainerCreateOptions, svc types.ServiceConfig) error {
+	hc := svc.HealthCheck
+	if hc == nil {
+		return nil
+	}
+	if hc.Disable {
+		if len(hc.Test) > 0 {
+			return fmt.Errorf("healthcheck.disable and healthcheck.test must not be set together")
+		}
+		opt.NoHealthcheck = true
+		return nil
+	}
+
+	if len(hc.Test) > 0 {
+		switch hc.Test[0] {
+		case "NONE":
+			opt.NoHealthcheck = true
+			return nil
+		case "CMD-SHELL":
+			if len(hc.Test) != 2 {
+				return fmt.Errorf("healthcheck.test: CMD-SHELL requires exactly one argument, got %q", hc.Test[1:])
+			}
+			opt.HealthCmd = hc.Test[1]
+		case "CMD":
+			if len(hc.Test) < 2 {
+				return fmt.Errorf("healthcheck.test: CMD requires at least one argument")
+			}
+			// the exec form of `--health-cmd`, which is not run by the shell
+			healthCmd, err := json.Marshal(hc.Test)
+			if err != nil {
+				return err
+			}
+			opt.HealthCmd = string(healthCmd)
+		default:
+			return fmt.Errorf("healthcheck.test: unknown type %q, must be one of NONE, CMD, CMD-SHELL", hc.Test[0])
+		}
+	}
+	if hc.Interval != nil {
+		opt.HealthInterval = time.Duration(*hc.Interval)
+	}
+	if hc.Timeout != nil {
+		opt.HealthTimeout = time.Duration(*hc.Timeout)
+	}
+	if hc.Retries != nil {
+		opt.HealthRetries = int(*hc.Retries)
+	}
+	if hc.StartPeriod != nil {
+		opt.HealthStartPeriod = time.Duration(*hc.StartPeriod)
+	}
+	if hc.StartInterval != nil {
+		opt.HealthStartInterval = time.Duration(*hc.StartInterval)
+	}
+	return nil
+}
+
 type networkNamePair struct {
 	shortNetworkName string
 	fullName         string
@@ -409,7 +506,7 @@ func getNetworks(project *types.Project, svc types.ServiceConfig) ([]networkName
 	return fullNames, nil
 }
 
-func Parse(project *types.Project, svc types.ServiceConfig) (*Service, error) {
+func Parse(project *types.Project, svc types.ServiceConfig, defaults Defaults) (*Service, error) {
 	warnUnknownFields(svc)
 
 	replicas, err := getReplicas(svc)
@@ -454,7 +551,7 @@ func Parse(project *types.Project, svc types.ServiceConfig) (*Service, error) {
 	}
 
 	for i := 0; i < replicas; i++ {
-		container, err := newContainer(project, parsed, i)
+		container, err := newContainer(project, parsed, i, defaults)
 		if err != nil {
 			return nil, err
 		}
@@ -464,7 +561,7 @@ func Parse(project *types.Project, svc types.ServiceConfig) (*Service, error) {
 	return parsed, nil
 }
 
-func newContainer(project *types.Project, parsed *Service, i int) (*Container, error) {
+func newContainer(project *types.Project, parsed *Service, i int, defaults Defaults) (*Container, error) {
 	svc := *parsed.Unparsed
 	var c Container
 	c.Name = DefaultContainerName(project.Name, svc.Name, strconv.Itoa(i+1))
@@ -475,110 +572,125 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 		c.Name = svc.ContainerName
 	}
 
-	c.RunArgs = []string{
-		"--name=" + c.Name,
-		"--pull=never", // because image will be ensured before running replicas with `nerdctl run`.
-	}
+	// the defaults are copied, and their slices are never appended to in place, as they are shared by the containers
+	opt := defaults.CreateOptions
+	netOpts := defaults.NetworkOptions
 
-	for k, v := range svc.Annotations {
-		if v == "" {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("--annotation=%s", k))
-		} else {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("--annotation=%s=%s", k, v))
+	opt.Name = c.Name
+	opt.Pull = "never" // because image will be ensured before creating the replicas
+
+	if len(svc.Annotations) > 0 {
+		annotations := slices.Clone(opt.Annotations)
+		for k, v := range svc.Annotations {
+			if v == "" {
+				annotations = append(annotations, k)
+			} else {
+				annotations = append(annotations, fmt.Sprintf("%s=%s", k, v))
+			}
 		}
+		opt.Annotations = annotations
 	}
 
 	if svc.BlkioConfig != nil && svc.BlkioConfig.Weight != 0 {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--blkio-weight=%d", svc.BlkioConfig.Weight))
+		opt.BlkioWeight = svc.BlkioConfig.Weight
 	}
 
-	for _, v := range svc.CapAdd {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--cap-add=%s", v))
-	}
-
-	for _, v := range svc.CapDrop {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--cap-drop=%s", v))
-	}
+	opt.CapAdd = slices.Concat(opt.CapAdd, svc.CapAdd)
+	opt.CapDrop = slices.Concat(opt.CapDrop, svc.CapDrop)
 
 	if cpuLimit, err := getCPULimit(svc); err != nil {
 		return nil, err
 	} else if cpuLimit != "" {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--cpus=%s", cpuLimit))
+		opt.CPUs, err = strconv.ParseFloat(cpuLimit, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpus %q: %w", cpuLimit, err)
+		}
 	}
 
 	if svc.CPUSet != "" {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--cpuset-cpus=%s", svc.CPUSet))
+		opt.CPUSetCPUs = svc.CPUSet
 	}
 
 	if svc.CPUShares != 0 {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--cpu-shares=%d", svc.CPUShares))
+		opt.CPUShares = uint64(svc.CPUShares)
 	}
 
-	for _, v := range svc.Devices {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--device=%s:%s:%s", v.Source, v.Target, v.Permissions))
+	if len(svc.Devices) > 0 {
+		devices := slices.Clone(opt.Device)
+		for _, v := range svc.Devices {
+			devices = append(devices, fmt.Sprintf("%s:%s:%s", v.Source, v.Target, v.Permissions))
+		}
+		opt.Device = devices
 	}
 
-	for _, v := range svc.DNS {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--dns=%s", v))
-	}
-	for _, v := range svc.DNSSearch {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--dns-search=%s", v))
-	}
-	for _, v := range svc.DNSOpts {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--dns-option=%s", v))
-	}
+	netOpts.DNSServers = strutil.DedupeStrSlice(slices.Concat(netOpts.DNSServers, []string(svc.DNS)))
+	netOpts.DNSSearchDomains = strutil.DedupeStrSlice(slices.Concat(netOpts.DNSSearchDomains, []string(svc.DNSSearch)))
+	netOpts.DNSResolvConfOptions = strutil.DedupeStrSlice(slices.Concat(netOpts.DNSResolvConfOptions, []string(svc.DNSOpts)))
 
-	for _, v := range svc.Entrypoint {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--entrypoint=%s", v))
+	if len(svc.Entrypoint) > 0 {
+		opt.EntrypointChanged = true
+		opt.Entrypoint = slices.Clone([]string(svc.Entrypoint))
 	}
 
-	for k, v := range svc.Environment {
-		if v == nil {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("-e=%s", k))
-		} else {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("-e=%s=%s", k, *v))
+	if len(svc.Environment) > 0 {
+		env := slices.Clone(opt.Env)
+		for k, v := range svc.Environment {
+			if v == nil {
+				env = append(env, k)
+			} else {
+				env = append(env, fmt.Sprintf("%s=%s", k, *v))
+			}
 		}
+		opt.Env = env
 	}
-	for k, v := range svc.ExtraHosts {
-		for _, h := range v {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("--add-host=%s:%s", k, h))
+	if len(svc.ExtraHosts) > 0 {
+		addHost := slices.Clone(netOpts.AddHost)
+		for k, v := range svc.ExtraHosts {
+			for _, h := range v {
+				addHost = append(addHost, fmt.Sprintf("%s:%s", k, h))
+			}
 		}
+		netOpts.AddHost = addHost
 	}
 
 	if svc.Init != nil && *svc.Init {
-		c.RunArgs = append(c.RunArgs, "--init")
+		opt.InitProcessFlag = true
 	}
 
 	if memLimit, err := getMemLimit(svc); err != nil {
 		return nil, err
 	} else if memLimit > 0 {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("-m=%d", memLimit))
+		opt.Memory = fmt.Sprintf("%d", memLimit)
 	}
 
 	if gpuReqs, err := getGPUs(svc); err != nil {
 		return nil, err
 	} else if len(gpuReqs) > 0 {
-		for _, gpus := range gpuReqs {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("--gpus=%s", gpus))
-		}
+		opt.GPUs = slices.Concat(opt.GPUs, gpuReqs)
 	}
 
-	for k, v := range svc.Labels {
-		if v == "" {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("-l=%s", k))
-		} else {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("-l=%s=%s", k, v))
+	if len(svc.Labels) > 0 {
+		labels := slices.Clone(opt.Label)
+		for k, v := range svc.Labels {
+			if v == "" {
+				labels = append(labels, k)
+			} else {
+				labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+			}
 		}
+		opt.Label = labels
 	}
 
 	if svc.Logging != nil {
 		if svc.Logging.Driver != "" {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("--log-driver=%s", svc.Logging.Driver))
+			opt.LogDriver = svc.Logging.Driver
 		}
 		if svc.Logging.Options != nil {
+			logOpt := slices.Clone(opt.LogOpt)
 			for k, v := range svc.Logging.Options {
-				c.RunArgs = append(c.RunArgs, fmt.Sprintf("--log-opt=%s=%s", k, v))
+				logOpt = append(logOpt, fmt.Sprintf("%s=%s", k, v))
 			}
+			opt.LogOpt = logOpt
 		}
 	}
 
@@ -587,20 +699,28 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 		return nil, err
 	}
 	netTypeContainer := false
-	for _, net := range networks {
-		if strings.HasPrefix(net.fullName, "container:") {
+	var netSlice []string
+	for _, nw := range networks {
+		if strings.HasPrefix(nw.fullName, "container:") {
 			netTypeContainer = true
 		}
-		c.RunArgs = append(c.RunArgs, "--net="+net.fullName)
-		if value, ok := svc.Networks[net.shortNetworkName]; ok {
+		netSlice = append(netSlice, nw.fullName)
+		if value, ok := svc.Networks[nw.shortNetworkName]; ok {
 			if value != nil && value.Ipv4Address != "" {
-				c.RunArgs = append(c.RunArgs, "--ip="+value.Ipv4Address)
+				netOpts.IPAddress = value.Ipv4Address
 			}
 			if value != nil && value.MacAddress != "" {
-				c.RunArgs = append(c.RunArgs, "--mac-address="+value.MacAddress)
+				if _, err := net.ParseMAC(value.MacAddress); err != nil {
+					return nil, err
+				}
+				netOpts.MACAddress = value.MacAddress
 			}
 		}
 	}
+	if len(netSlice) > 0 {
+		// like `--net`, the networks of the service replace the default network
+		netOpts.NetworkSlice = strutil.DedupeStrSlice(netSlice)
+	}
 
 	if netTypeContainer && svc.Hostname != "" {
 		return nil, fmt.Errorf("conflicting options: hostname and container network mode")
@@ -610,29 +730,31 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 		if hostname == "" {
 			hostname = svc.Name
 		}
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--hostname=%s", hostname))
+		netOpts.Hostname = hostname
 	}
 
 	if svc.Pid != "" {
-		c.RunArgs = append(c.RunArgs, "--pid="+svc.Pid)
+		opt.Pid = svc.Pid
 	}
 
 	if svc.PidsLimit > 0 {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--pids-limit=%d", svc.PidsLimit))
+		opt.PidsLimit = svc.PidsLimit
 	}
 
 	if svc.Ulimits != nil {
+		ulimits := slices.Clone(opt.Ulimit)
 		for utype, ulimit := range svc.Ulimits {
 			if ulimit.Single != 0 {
-				c.RunArgs = append(c.RunArgs, fmt.Sprintf("--ulimit=%s=%d", utype, ulimit.Single))
+				ulimits = append(ulimits, fmt.Sprintf("%s=%d", utype, ulimit.Single))
 			} else {
-				c.RunArgs = append(c.RunArgs, fmt.Sprintf("--ulimit=%s=%d:%d", utype, ulimit.Soft, ulimit.Hard))
+				ulimits = append(ulimits, fmt.Sprintf("%s=%d:%d", utype, ulimit.Soft, ulimit.Hard))
 			}
 		}
+		opt.Ulimit = ulimits
 	}
 
 	if svc.Platform != "" {
-		c.RunArgs = append(c.RunArgs, "--platform="+svc.Platform)
+		opt.Platform = svc.Platform
 	}
 
 	for _, p := range svc.Ports {
@@ -640,100 +762,137 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 		if err != nil {
 			return nil, err
 		}
-		c.RunArgs = append(c.RunArgs, "-p="+pStr)
+		c.Publish = append(c.Publish, pStr)
+	}
+	c.Publish = strutil.DedupeStrSlice(c.Publish)
+
+	if len(svc.Expose) > 0 {
+		expose := slices.Clone(netOpts.Expose)
+		for _, e := range strutil.DedupeStrSlice(svc.Expose) {
+			ports, err := portutil.ParseFlagExpose(e)
+			if err != nil {
+				return nil, err
+			}
+			expose = append(expose, ports...)
+		}
+		netOpts.Expose = expose
 	}
 
 	if svc.Privileged {
-		c.RunArgs = append(c.RunArgs, "--privileged")
+		if opt.UserNS != "" {
+			// same as `nerdctl run --privileged`
+			return nil, errors.New("privileged flag cannot be used with userns-remap")
+		}
+		opt.Privileged = true
 	}
 
 	if svc.ReadOnly {
-		c.RunArgs = append(c.RunArgs, "--read-only")
+		opt.ReadOnly = true
 	}
 
 	if svc.StopGracePeriod != nil {
 		timeout := time.Duration(*svc.StopGracePeriod)
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--stop-timeout=%d", int(timeout.Seconds())))
+		opt.StopTimeout = int(timeout.Seconds())
 	}
 	if svc.StopSignal != "" {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--stop-signal=%s", svc.StopSignal))
+		opt.StopSignal = svc.StopSignal
 	}
 
 	if restart, err := getRestart(svc); err != nil {
 		return nil, err
 	} else if restart != "" {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--restart=%s", restart))
+		opt.Restart = restart
+	}
+
+	if err := setHealthcheck(&opt, svc); err != nil {
+		return nil, err
+	}
+
+	if onFailure, ok := svc.Extensions[ComposeHealthOnFailure]; ok {
+		opt.HealthOnFailure = fmt.Sprintf("%v", onFailure)
+		if err := healthcheck.ValidateOnFailure(opt.HealthOnFailure); err != nil {
+			return nil, err
+		}
 	}
 
 	if svc.Runtime != "" {
-		c.RunArgs = append(c.RunArgs, "--runtime="+svc.Runtime)
+		opt.Runtime = svc.Runtime
 	}
 
 	if svc.ShmSize > 0 {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--shm-size=%d", svc.ShmSize))
+		opt.ShmSize = fmt.Sprintf("%d", svc.ShmSize)
 	}
 
-	for _, v := range svc.SecurityOpt {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--security-opt=%s", v))
-	}
+	opt.SecurityOpt = slices.Concat(opt.SecurityOpt, svc.SecurityOpt)
 
-	for k, v := range svc.Sysctls {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--sysctl=%s=%s", k, v))
+	if len(svc.Sysctls) > 0 {
+		sysctls := slices.Clone(opt.Sysctl)
+		for k, v := range svc.Sysctls {
+			sysctls = append(sysctls, fmt.Sprintf("%s=%s", k, v))
+		}
+		opt.Sysctl = sysctls
 	}
 
 	if svc.StdinOpen {
-		c.RunArgs = append(c.RunArgs, "--interactive")
+		opt.Interactive = true
 	}
 
 	if svc.User != "" {
-		c.RunArgs = append(c.RunArgs, "--user="+svc.User)
+		opt.User = svc.User
 	}
 
-	for _, v := range svc.GroupAdd {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--group-add=%s", v))
-	}
+	opt.GroupAdd = slices.Concat(opt.GroupAdd, svc.GroupAdd)
 
+	volumes := slices.Clone(opt.Volume)
 	for _, v := range svc.Volumes {
 		vStr, mkdir, err := serviceVolumeConfigToFlagV(v, project)
 		if err != nil {
 			return nil, err
 		}
-		c.RunArgs = append(c.RunArgs, "-v="+vStr)
+		volumes = append(volumes, vStr)
 		c.Mkdir = mkdir
 	}
 
 	for _, config := range svc.Configs {
 		fileRef := types.FileReferenceConfig(config)
-		vStr, err := fileReferenceConfigToFlagV(fileRef, project, false)
+		vStr, file, err := fileReferenceConfigToFlagV(fileRef, project, false)
 		if err != nil {
 			return nil, err
 		}
-		c.RunArgs = append(c.RunArgs, "-v="+vStr)
+		if file != nil {
+			c.Files = append(c.Files, *file)
+		} else {
+			volumes = append(volumes, vStr)
+		}
 	}
 
 	for _, secret := range svc.Secrets {
 		fileRef := types.FileReferenceConfig(secret)
-		vStr, err := fileReferenceConfigToFlagV(fileRef, project, true)
+		vStr, file, err := fileReferenceConfigToFlagV(fileRef, project, true)
 		if err != nil {
 			return nil, err
 		}
-		c.RunArgs = append(c.RunArgs, "-v="+vStr)
+		if file != nil {
+			c.Files = append(c.Files, *file)
+		} else {
+			volumes = append(volumes, vStr)
+		}
 	}
+	opt.Volume = volumes
 
-	for _, tmpfs := range svc.Tmpfs {
-		c.RunArgs = append(c.RunArgs, "--tmpfs="+tmpfs)
-	}
+	opt.Tmpfs = slices.Concat(opt.Tmpfs, []string(svc.Tmpfs))
 
 	if svc.Tty {
-		c.RunArgs = append(c.RunArgs, "--tty")
+		opt.TTY = true
 	}
 
 	if svc.WorkingDir != "" {
-		c.RunArgs = append(c.RunArgs, "-w="+svc.WorkingDir)
+		opt.Workdir = svc.WorkingDir
 	}
 
-	c.RunArgs = append(c.RunArgs, parsed.Image) // NOT svc.Image
-	c.RunArgs = append(c.RunArgs, svc.Command...)
+	c.CreateOptions = opt
+	c.NetworkOptions = netOpts
+	c.Args = append([]string{parsed.Image}, svc.Command...) // NOT svc.Image
 	return &c, nil
 }
 
@@ -846,7 +1005,9 @@ func serviceVolumeConfigToFlagV(c types.ServiceVolumeConfig, project *types.Proj
 	return s, mkdir, nil
 }
 
-func fileReferenceConfigToFlagV(c types.FileReferenceConfig, project *types.Project, secret bool) (string, error) {
+// fileReferenceConfigToFlagV returns the -v flag for bind-mounting a secret or a config from the host,
+// or the File to write, when the secret or the config does not come from a file, or has a uid, a gid or a mode.
+func fileReferenceConfigToFlagV(c types.FileReferenceConfig, project *types.Project, secret bool) (string, *File, error) {
 	objType := "config"
 	if secret {
 		objType = "secret"
@@ -858,29 +1019,23 @@ func fileReferenceConfigToFlagV(c types.FileReferenceConfig, project *types.Proj
 	}
 
 	if err := identifiers.ValidateDockerCompat(c.Source); err != nil {
-		return "", fmt.Errorf("invalid source name for %s: %w", objType, err)
+		return "", nil, fmt.Errorf("invalid source name for %s: %w", objType, err)
 	}
 
 	var obj types.FileObjectConfig
 	if secret {
 		secret, ok := project.Secrets[c.Source]
 		if !ok {
-			return "", fmt.Errorf("secret %s is undefined", c.Source)
+			return "", nil, fmt.Errorf("secret %s is undefined", c.Source)
 		}
 		obj = types.FileObjectConfig(secret)
 	} else {
 		config, ok := project.Configs[c.Source]
 		if !ok {
-			return "", fmt.Errorf("config %s is undefined", c.Source)
+			return "", nil, fmt.Errorf("config %s is undefined", c.Source)
 		}
 		obj = types.FileObjectConfig(config)
 	}
-	src := project.RelativePath(obj.File)
-	var err error
-	src, err = filepath.Abs(src)
-	if err != nil {
-		return "", fmt.Errorf("%s %s: invalid relative path %q: %w", objType, c.Source, src, err)
-	}
 
 	target := c.Target
 	if target == "" {
@@ -895,24 +1050,63 @@ func fileReferenceConfigToFlagV(c types.FileReferenceConfig, project *types.Proj
 			if secret {
 				target = filepath.Join("/run/secrets", target)
 			} else {
-				return "", fmt.Errorf("config %s: target %q must be an absolute path", c.Source, c.Target)
+				return "", nil, fmt.Errorf("config %s: target %q must be an absolute path", c.Source, c.Target)
 			}
 		}
 	}
 
+	file := &File{
+		Name:   filepath.Join(objType+"s", c.Source),
+		Target: target,
+		UID:    -1,
+		GID:    -1,
+		Mode:   defaultFileMode,
+	}
+	var err error
 	if c.UID != "" {
 		// Raise an error rather than ignoring the value, for avoiding any security issue
-		return "", fmt.Errorf("%s %s: unsupported field: UID", objType, c.Source)
+		if file.UID, err = strconv.Atoi(c.UID); err != nil || file.UID < 0 {
+			return "", nil, fmt.Errorf("%s %s: invalid uid %q, must be numeric", objType, c.Source, c.UID)
+		}
 	}
 	if c.GID != "" {
-		return "", fmt.Errorf("%s %s: unsupported field: GID", objType, c.Source)
+		if file.GID, err = strconv.Atoi(c.GID); err != nil || file.GID < 0 {
+			return "", nil, fmt.Errorf("%s %s: invalid gid %q, must be numeric", objType, c.Source, c.GID)
+		}
 	}
 	if c.Mode != nil {
-		return "", fmt.Errorf("%s %s: unsupported field: Mode", objType, c.Source)
+		if *c.Mode < 0 || *c.Mode > 0o7777 {
+			return "", nil, fmt.Errorf("%s %s: invalid mode %o", objType, c.Source, *c.Mode)
+		}
+		file.Mode = os.FileMode(*c.Mode)
+	}
+
+	switch {
+	case obj.Environment != "":
+		v, ok := project.Environment[obj.Environment]
+		if !ok {
+			return "", nil, fmt.Errorf("%s %s: environment variable %q is not set", objType, c.Source, obj.Environment)
+		}
+		file.Content = []byte(v)
+		return "", file, nil
+	case obj.Content != "":
+		file.Content = []byte(obj.Content)
+		return "", file, nil
+	}
+
+	src := project.RelativePath(obj.File)
+	src, err = filepath.Abs(src)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s %s: invalid relative path %q: %w", objType, c.Source, src, err)
+	}
+	if c.UID != "" || c.GID != "" || c.Mode != nil {
+		// the host file is copied, as its owner and its mode cannot be changed by bind-mounting it
+		file.Source = src
+		return "", file, nil
 	}
 
 	s := fmt.Sprintf("%s:%s:ro", src, target)
-	return s, nil
+	return s, nil, nil
 }
 
 // DefaultImageName returns the image name following compose naming logic.
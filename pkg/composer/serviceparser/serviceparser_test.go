@@ -21,11 +21,14 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
 	"gotest.tools/v3/assert"
 
+	apitypes "github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/internal/filesystem"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
@@ -100,6 +103,8 @@ services:
     restart: always
     ports:
       - 8080:80
+    expose:
+      - "9000"
     extra_hosts:
       test.com: 172.19.1.1
       test2.com: 172.19.1.2
@@ -153,7 +158,7 @@ volumes:
 	wpSvc, err := project.GetService("wordpress")
 	assert.NilError(t, err)
 
-	wp, err := Parse(project, wpSvc)
+	wp, err := Parse(project, wpSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("wordpress: %+v", wp)
@@ -162,44 +167,104 @@ volumes:
 	assert.Assert(t, len(wp.Containers) == 1)
 	wp1 := wp.Containers[0]
 	assert.Assert(t, wp1.Name == DefaultContainerName(project.Name, "wordpress", "1"))
-	assert.Assert(t, in(wp1.RunArgs, "--name="+wp1.Name))
-	assert.Assert(t, in(wp1.RunArgs, "--hostname=wordpress"))
-	assert.Assert(t, in(wp1.RunArgs, fmt.Sprintf("--net=%s_default", project.Name)))
-	assert.Assert(t, in(wp1.RunArgs, "--restart=always"))
-	assert.Assert(t, in(wp1.RunArgs, "-e=WORDPRESS_DB_HOST=db"))
-	assert.Assert(t, in(wp1.RunArgs, "-e=WORDPRESS_DB_USER=exampleuser"))
-	assert.Assert(t, in(wp1.RunArgs, "-p=8080:80/tcp"))
-	assert.Assert(t, in(wp1.RunArgs, fmt.Sprintf("-v=%s_wordpress:/var/www/html", project.Name)))
-	assert.Assert(t, in(wp1.RunArgs, "--pids-limit=100"))
-	assert.Assert(t, in(wp1.RunArgs, "--ulimit=nproc=500"))
-	assert.Assert(t, in(wp1.RunArgs, "--ulimit=nofile=20000:20000"))
-	assert.Assert(t, in(wp1.RunArgs, "--dns=8.8.8.8"))
-	assert.Assert(t, in(wp1.RunArgs, "--dns=8.8.4.4"))
-	assert.Assert(t, in(wp1.RunArgs, "--dns-search=example.com"))
-	assert.Assert(t, in(wp1.RunArgs, "--dns-option=no-tld-query"))
-	assert.Assert(t, in(wp1.RunArgs, "--log-driver=json-file"))
-	assert.Assert(t, in(wp1.RunArgs, "--log-opt=max-size=5K"))
-	assert.Assert(t, in(wp1.RunArgs, "--log-opt=max-file=2"))
-	assert.Assert(t, in(wp1.RunArgs, "--add-host=test.com:172.19.1.1"))
-	assert.Assert(t, in(wp1.RunArgs, "--add-host=test2.com:172.19.1.2"))
-	assert.Assert(t, in(wp1.RunArgs, "--shm-size=1073741824"))
-	assert.Assert(t, in(wp1.RunArgs, "--user=1001:1001"))
-	assert.Assert(t, in(wp1.RunArgs, "--group-add=1001"))
+	assert.Equal(t, wp1.CreateOptions.Name, wp1.Name)
+	assert.Equal(t, wp1.CreateOptions.Pull, "never")
+	assert.Equal(t, wp1.NetworkOptions.Hostname, "wordpress")
+	assert.DeepEqual(t, wp1.NetworkOptions.NetworkSlice, []string{fmt.Sprintf("%s_default", project.Name)})
+	assert.Equal(t, wp1.CreateOptions.Restart, "always")
+	assert.Assert(t, in(wp1.CreateOptions.Env, "WORDPRESS_DB_HOST=db"))
+	assert.Assert(t, in(wp1.CreateOptions.Env, "WORDPRESS_DB_USER=exampleuser"))
+	assert.DeepEqual(t, wp1.Publish, []string{"8080:80/tcp"})
+	assert.DeepEqual(t, wp1.NetworkOptions.Expose, []string{"9000/tcp"})
+	assert.Assert(t, in(wp1.CreateOptions.Volume, fmt.Sprintf("%s_wordpress:/var/www/html", project.Name)))
+	assert.Equal(t, wp1.CreateOptions.PidsLimit, int64(100))
+	assert.Assert(t, in(wp1.CreateOptions.Ulimit, "nproc=500"))
+	assert.Assert(t, in(wp1.CreateOptions.Ulimit, "nofile=20000:20000"))
+	assert.DeepEqual(t, wp1.NetworkOptions.DNSServers, []string{"8.8.8.8", "8.8.4.4"})
+	assert.DeepEqual(t, wp1.NetworkOptions.DNSSearchDomains, []string{"example.com"})
+	assert.DeepEqual(t, wp1.NetworkOptions.DNSResolvConfOptions, []string{"no-tld-query"})
+	assert.Equal(t, wp1.CreateOptions.LogDriver, "json-file")
+	assert.Assert(t, in(wp1.CreateOptions.LogOpt, "max-size=5K"))
+	assert.Assert(t, in(wp1.CreateOptions.LogOpt, "max-file=2"))
+	assert.Assert(t, in(wp1.NetworkOptions.AddHost, "test.com:172.19.1.1"))
+	assert.Assert(t, in(wp1.NetworkOptions.AddHost, "test2.com:172.19.1.2"))
+	assert.Equal(t, wp1.CreateOptions.ShmSize, "1073741824")
+	assert.Equal(t, wp1.CreateOptions.User, "1001:1001")
+	assert.DeepEqual(t, wp1.CreateOptions.GroupAdd, []string{"1001"})
+	assert.DeepEqual(t, wp1.Args, []string{"wordpress:5.7"})
 
 	dbSvc, err := project.GetService("db")
 	assert.NilError(t, err)
 
-	db, err := Parse(project, dbSvc)
+	db, err := Parse(project, dbSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("db: %+v", db)
 	assert.Assert(t, len(db.Containers) == 1)
 	db1 := db.Containers[0]
 	assert.Assert(t, db1.Name == DefaultContainerName(project.Name, "db", "1"))
-	assert.Assert(t, in(db1.RunArgs, "--hostname=db"))
-	assert.Assert(t, in(db1.RunArgs, fmt.Sprintf("-v=%s_db:/var/lib/mysql", project.Name)))
-	assert.Assert(t, in(db1.RunArgs, "--stop-signal=SIGUSR1"))
-	assert.Assert(t, in(db1.RunArgs, "--stop-timeout=90"))
+	assert.Equal(t, db1.NetworkOptions.Hostname, "db")
+	assert.Assert(t, in(db1.CreateOptions.Volume, fmt.Sprintf("%s_db:/var/lib/mysql", project.Name)))
+	assert.Equal(t, db1.CreateOptions.StopSignal, "SIGUSR1")
+	assert.Equal(t, db1.CreateOptions.StopTimeout, 90)
+}
+
+func TestParseDefaults(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: nginx:alpine
+    cap_add:
+    - NET_ADMIN
+    deploy:
+      replicas: 2
+  bar:
+    image: nginx:alpine
+    network_mode: host
+    logging:
+      driver: none
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	defaults := Defaults{
+		CreateOptions: apitypes.ContainerCreateOptions{
+			LogDriver: "json-file",
+			Runtime:   "io.containerd.runc.v2",
+			CapAdd:    make([]string, 0, 4),
+		},
+		NetworkOptions: apitypes.NetworkOptions{
+			NetworkSlice: []string{"bridge"},
+		},
+	}
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+	foo, err := Parse(project, fooSvc, defaults)
+	assert.NilError(t, err)
+	assert.Assert(t, len(foo.Containers) == 2)
+	for _, c := range foo.Containers {
+		// the fields that are not set by the service keep the defaults
+		assert.Equal(t, c.CreateOptions.LogDriver, "json-file")
+		assert.Equal(t, c.CreateOptions.Runtime, "io.containerd.runc.v2")
+		assert.DeepEqual(t, c.NetworkOptions.NetworkSlice, []string{fmt.Sprintf("%s_default", project.Name)})
+		assert.DeepEqual(t, c.CreateOptions.CapAdd, []string{"NET_ADMIN"})
+	}
+	// the containers must not share the slices of the defaults
+	foo.Containers[0].CreateOptions.CapAdd[0] = "SYS_ADMIN"
+	assert.DeepEqual(t, foo.Containers[1].CreateOptions.CapAdd, []string{"NET_ADMIN"})
+	assert.Equal(t, len(defaults.CreateOptions.CapAdd), 0)
+
+	barSvc, err := project.GetService("bar")
+	assert.NilError(t, err)
+	bar, err := Parse(project, barSvc, defaults)
+	assert.NilError(t, err)
+	assert.Equal(t, bar.Containers[0].CreateOptions.LogDriver, "none")
+	assert.DeepEqual(t, bar.Containers[0].NetworkOptions.NetworkSlice, []string{"host"})
 }
 
 func TestParseDeprecated(t *testing.T) {
@@ -224,16 +289,16 @@ services:
 	fooSvc, err := project.GetService("foo")
 	assert.NilError(t, err)
 
-	foo, err := Parse(project, fooSvc)
+	foo, err := Parse(project, fooSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("foo: %+v", foo)
 	assert.Assert(t, len(foo.Containers) == 1)
 	for i, c := range foo.Containers {
 		assert.Assert(t, c.Name == DefaultContainerName(project.Name, "foo", strconv.Itoa(i+1)))
-		assert.Assert(t, in(c.RunArgs, "--name="+c.Name))
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("--cpus=%f", 0.42)))
-		assert.Assert(t, in(c.RunArgs, "-m=44040192"))
+		assert.Equal(t, c.CreateOptions.Name, c.Name)
+		assert.Equal(t, c.CreateOptions.CPUs, 0.42)
+		assert.Equal(t, c.CreateOptions.Memory, "44040192")
 	}
 }
 
@@ -285,51 +350,53 @@ services:
 	fooSvc, err := project.GetService("foo")
 	assert.NilError(t, err)
 
-	foo, err := Parse(project, fooSvc)
+	foo, err := Parse(project, fooSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("foo: %+v", foo)
 	assert.Assert(t, len(foo.Containers) == 3)
 	for i, c := range foo.Containers {
 		assert.Assert(t, c.Name == DefaultContainerName(project.Name, "foo", strconv.Itoa(i+1)))
-		assert.Assert(t, in(c.RunArgs, "--name="+c.Name))
+		assert.Equal(t, c.CreateOptions.Name, c.Name)
 
-		assert.Assert(t, in(c.RunArgs, "--restart=no"))
-		assert.Assert(t, in(c.RunArgs, "--cpus=0.42"))
-		assert.Assert(t, in(c.RunArgs, "-m=44040192"))
+		assert.Equal(t, c.CreateOptions.Restart, "no")
+		assert.Equal(t, c.CreateOptions.CPUs, 0.42)
+		assert.Equal(t, c.CreateOptions.Memory, "44040192")
 	}
 
 	barSvc, err := project.GetService("bar")
 	assert.NilError(t, err)
 
-	bar, err := Parse(project, barSvc)
+	bar, err := Parse(project, barSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("bar: %+v", bar)
 	assert.Assert(t, len(bar.Containers) == 1)
 	for _, c := range bar.Containers {
-		assert.Assert(t, in(c.RunArgs, "--restart=always"))
-		assert.Assert(t, in(c.RunArgs, `--gpus="capabilities=gpu,utility,compute",driver=nvidia,count=2`))
-		assert.Assert(t, in(c.RunArgs, `--gpus=capabilities=nvidia,"device=dummy,dummy2"`))
+		assert.Equal(t, c.CreateOptions.Restart, "always")
+		assert.DeepEqual(t, c.CreateOptions.GPUs, []string{
+			`"capabilities=gpu,utility,compute",driver=nvidia,count=2`,
+			`capabilities=nvidia,"device=dummy,dummy2"`,
+		})
 	}
 
 	bazSvc, err := project.GetService("baz")
 	assert.NilError(t, err)
 
-	baz, err := Parse(project, bazSvc)
+	baz, err := Parse(project, bazSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("baz: %+v", baz)
 	assert.Assert(t, len(baz.Containers) == 1)
 	for _, c := range baz.Containers {
-		assert.Assert(t, in(c.RunArgs, "--restart=no"))
-		assert.Assert(t, in(c.RunArgs, `--gpus=capabilities=utility,count=-1`))
+		assert.Equal(t, c.CreateOptions.Restart, "no")
+		assert.DeepEqual(t, c.CreateOptions.GPUs, []string{`capabilities=utility,count=-1`})
 	}
 
 	quxSvc, err := project.GetService("qux")
 	assert.NilError(t, err)
 
-	qux, err := Parse(project, quxSvc)
+	qux, err := Parse(project, quxSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("qux: %+v", qux)
@@ -356,14 +423,12 @@ services:
 	fooSvc, err := project.GetService("foo")
 	assert.NilError(t, err)
 
-	foo, err := Parse(project, fooSvc)
+	foo, err := Parse(project, fooSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("foo: %+v", foo)
 	for _, c := range foo.Containers {
-		assert.Assert(t, in(c.RunArgs, "--device=/dev/a:/dev/a:rwm"))
-		assert.Assert(t, in(c.RunArgs, "--device=/dev/b:/dev/b:rwm"))
-		assert.Assert(t, in(c.RunArgs, "--device=/dev/c:/dev/c:rw"))
+		assert.DeepEqual(t, c.CreateOptions.Device, []string{"/dev/a:/dev/a:rwm", "/dev/b:/dev/b:rwm", "/dev/c:/dev/c:rw"})
 	}
 }
 
@@ -392,14 +457,14 @@ services:
 	fooSvc, err := project.GetService("foo")
 	assert.NilError(t, err)
 
-	foo, err := Parse(project, fooSvc)
+	foo, err := Parse(project, fooSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("foo: %+v", foo)
 	for _, c := range foo.Containers {
-		assert.Assert(t, in(c.RunArgs, "-v=/file1:/file1"))
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("-v=%s:/file2", filepath.Join(project.WorkingDir, "file2"))))
-		assert.Assert(t, in(c.RunArgs, "-v=/file3:/file3"))
+		assert.Assert(t, in(c.CreateOptions.Volume, "/file1:/file1"))
+		assert.Assert(t, in(c.CreateOptions.Volume, fmt.Sprintf("%s:/file2", filepath.Join(project.WorkingDir, "file2"))))
+		assert.Assert(t, in(c.CreateOptions.Volume, "/file3:/file3"))
 	}
 }
 
@@ -430,12 +495,12 @@ services:
 	fooSvc, err := project.GetService("foo")
 	assert.NilError(t, err)
 
-	foo, err := Parse(project, fooSvc)
+	foo, err := Parse(project, fooSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("foo: %+v", foo)
 	for _, c := range foo.Containers {
-		assert.Assert(t, in(c.RunArgs, "-v=/src/dir1:/tgt/dir1:rshared,ro"))
+		assert.Assert(t, in(c.CreateOptions.Volume, "/src/dir1:/tgt/dir1:rshared,ro"))
 	}
 }
 
@@ -460,24 +525,24 @@ services:
 	fooSvc, err := project.GetService("foo")
 	assert.NilError(t, err)
 
-	foo, err := Parse(project, fooSvc)
+	foo, err := Parse(project, fooSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("foo: %+v", foo)
 	for _, c := range foo.Containers {
-		assert.Assert(t, in(c.RunArgs, "--net=host"))
+		assert.DeepEqual(t, c.NetworkOptions.NetworkSlice, []string{"host"})
 	}
 
 	barSvc, err := project.GetService("bar")
 	assert.NilError(t, err)
 
-	bar, err := Parse(project, barSvc)
+	bar, err := Parse(project, barSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("bar: %+v", bar)
 	for _, c := range bar.Containers {
-		assert.Assert(t, in(c.RunArgs, "--net=container:nginx"))
-		assert.Assert(t, !in(c.RunArgs, "--hostname=bar"))
+		assert.DeepEqual(t, c.NetworkOptions.NetworkSlice, []string{"container:nginx"})
+		assert.Equal(t, c.NetworkOptions.Hostname, "")
 	}
 
 }
@@ -528,17 +593,82 @@ configs:
 	fooSvc, err := project.GetService("foo")
 	assert.NilError(t, err)
 
-	foo, err := Parse(project, fooSvc)
+	foo, err := Parse(project, fooSvc, Defaults{})
+	assert.NilError(t, err)
+
+	t.Logf("foo: %+v", foo)
+	for _, c := range foo.Containers {
+		assert.Assert(t, in(c.CreateOptions.Volume, fmt.Sprintf("%s:/run/secrets/secret1:ro", filepath.Join(project.WorkingDir, "secret1"))))
+		assert.Assert(t, in(c.CreateOptions.Volume, fmt.Sprintf("%s:/run/secrets/secret2-foo:ro", filepath.Join(project.WorkingDir, "secret2"))))
+		assert.Assert(t, in(c.CreateOptions.Volume, fmt.Sprintf("%s:/mnt/secret3-foo:ro", filepath.Join(project.WorkingDir, "secret3"))))
+		assert.Assert(t, in(c.CreateOptions.Volume, fmt.Sprintf("%s:/config1:ro", filepath.Join(project.WorkingDir, "config1"))))
+		assert.Assert(t, in(c.CreateOptions.Volume, fmt.Sprintf("%s:/mnt/config2-foo:ro", filepath.Join(project.WorkingDir, "config2"))))
+	}
+}
+
+func TestParseSecretsFiles(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("test is not compatible with windows")
+	}
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: nginx:alpine
+    secrets:
+    - secret1
+    - source: secret2
+      uid: "1000"
+      gid: "1001"
+      mode: 0400
+    - source: secret3
+      mode: 0440
+    configs:
+    - source: config1
+      target: /etc/config1
+secrets:
+  secret1:
+    environment: SECRET1
+  secret2:
+    environment: SECRET2
+  secret3:
+    file: ./secret3
+configs:
+  config1:
+    content: content-config1
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), map[string]string{"SECRET1": "content-secret1", "SECRET2": "content-secret2"})
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+
+	foo, err := Parse(project, fooSvc, Defaults{})
 	assert.NilError(t, err)
 
 	t.Logf("foo: %+v", foo)
 	for _, c := range foo.Containers {
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("-v=%s:/run/secrets/secret1:ro", filepath.Join(project.WorkingDir, "secret1"))))
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("-v=%s:/run/secrets/secret2-foo:ro", filepath.Join(project.WorkingDir, "secret2"))))
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("-v=%s:/mnt/secret3-foo:ro", filepath.Join(project.WorkingDir, "secret3"))))
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("-v=%s:/config1:ro", filepath.Join(project.WorkingDir, "config1"))))
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("-v=%s:/mnt/config2-foo:ro", filepath.Join(project.WorkingDir, "config2"))))
+		assert.DeepEqual(t, c.Files, []File{
+			{Name: "configs/config1", Target: "/etc/config1", Content: []byte("content-config1"), UID: -1, GID: -1, Mode: 0o444},
+			{Name: "secrets/secret1", Target: "/run/secrets/secret1", Content: []byte("content-secret1"), UID: -1, GID: -1, Mode: 0o444},
+			{Name: "secrets/secret2", Target: "/run/secrets/secret2", Content: []byte("content-secret2"), UID: 1000, GID: 1001, Mode: 0o400},
+			{Name: "secrets/secret3", Target: "/run/secrets/secret3", Source: filepath.Join(project.WorkingDir, "secret3"), UID: -1, GID: -1, Mode: 0o440},
+		})
+		for _, v := range c.CreateOptions.Volume {
+			assert.Assert(t, !strings.Contains(v, "secret"), v)
+		}
 	}
+
+	// the environment variable of a secret must be set
+	project, err = testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), map[string]string{"SECRET2": "content-secret2"})
+	assert.NilError(t, err)
+	fooSvc, err = project.GetService("foo")
+	assert.NilError(t, err)
+	_, err = Parse(project, fooSvc, Defaults{})
+	assert.ErrorContains(t, err, "SECRET1")
 }
 
 func TestParseRestartPolicy(t *testing.T) {
@@ -567,7 +697,7 @@ services:
 	getContainersFromService := func(svcName string) []Container {
 		svcConfig, err := project.GetService(svcName)
 		assert.NilError(t, err)
-		svc, err := Parse(project, svcConfig)
+		svc, err := Parse(project, svcConfig, Defaults{})
 		assert.NilError(t, err)
 
 		return svc.Containers
@@ -575,14 +705,102 @@ services:
 
 	var c Container
 	c = getContainersFromService("onfailure_no_count")[0]
-	assert.Assert(t, in(c.RunArgs, "--restart=on-failure"))
+	assert.Equal(t, c.CreateOptions.Restart, "on-failure")
 
 	c = getContainersFromService("onfailure_with_count")[0]
-	assert.Assert(t, in(c.RunArgs, "--restart=on-failure:10"))
+	assert.Equal(t, c.CreateOptions.Restart, "on-failure:10")
 
 	c = getContainersFromService("onfailure_ignore")[0]
-	assert.Assert(t, !in(c.RunArgs, "--restart=on-failure:3.14"))
+	assert.Equal(t, c.CreateOptions.Restart, "")
 
 	c = getContainersFromService("unless_stopped")[0]
-	assert.Assert(t, in(c.RunArgs, "--restart=unless-stopped"))
+	assert.Equal(t, c.CreateOptions.Restart, "unless-stopped")
+}
+
+func TestParseHealthOnFailure(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: alpine:3.14
+    x-nerdctl-health-on-failure: restart
+  bar:
+    image: alpine:3.14
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+	foo, err := Parse(project, fooSvc, Defaults{})
+	assert.NilError(t, err)
+	assert.Equal(t, foo.Containers[0].CreateOptions.HealthOnFailure, "restart")
+
+	barSvc, err := project.GetService("bar")
+	assert.NilError(t, err)
+	bar, err := Parse(project, barSvc, Defaults{})
+	assert.NilError(t, err)
+	assert.Equal(t, bar.Containers[0].CreateOptions.HealthOnFailure, "")
+}
+
+func TestParseHealthcheck(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+services:
+  shell:
+    image: alpine:3.14
+    healthcheck:
+      test: ["CMD-SHELL", "wget -q -O- http://localhost || exit 1"]
+      interval: 10s
+      timeout: 5s
+      retries: 5
+      start_period: 30s
+      start_interval: 1s
+  exec:
+    image: alpine:3.14
+    healthcheck:
+      test: ["CMD", "cat", "/tmp/it's ready"]
+  disabled:
+    image: alpine:3.14
+    healthcheck:
+      disable: true
+  none:
+    image: alpine:3.14
+    healthcheck:
+      test: ["NONE"]
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := testutil.LoadProject(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	shellSvc, err := project.GetService("shell")
+	assert.NilError(t, err)
+	shell, err := Parse(project, shellSvc, Defaults{})
+	assert.NilError(t, err)
+	shellOpt := shell.Containers[0].CreateOptions
+	assert.Equal(t, shellOpt.HealthCmd, "wget -q -O- http://localhost || exit 1")
+	assert.Equal(t, shellOpt.HealthInterval, 10*time.Second)
+	assert.Equal(t, shellOpt.HealthTimeout, 5*time.Second)
+	assert.Equal(t, shellOpt.HealthRetries, 5)
+	assert.Equal(t, shellOpt.HealthStartPeriod, 30*time.Second)
+	assert.Equal(t, shellOpt.HealthStartInterval, time.Second)
+
+	execSvc, err := project.GetService("exec")
+	assert.NilError(t, err)
+	execPs, err := Parse(project, execSvc, Defaults{})
+	assert.NilError(t, err)
+	assert.Equal(t, execPs.Containers[0].CreateOptions.HealthCmd, `["CMD","cat","/tmp/it's ready"]`)
+
+	for _, name := range []string{"disabled", "none"} {
+		svc, err := project.GetService(name)
+		assert.NilError(t, err)
+		ps, err := Parse(project, svc, Defaults{})
+		assert.NilError(t, err)
+		assert.Assert(t, ps.Containers[0].CreateOptions.NoHealthcheck)
+	}
 }
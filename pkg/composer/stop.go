@@ -18,8 +18,10 @@ package composer
 
 import (
 	"context"
-	"fmt"
+	"os"
+	"os/signal"
 	"sync"
+	"time"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
@@ -34,8 +36,7 @@ type StopOptions struct {
 	Timeout *uint
 }
 
-// Stop stops containers in `services` without removing them. It calls
-// `nerdctl stop CONTAINER_ID` to do the actual job.
+// Stop stops containers in `services` without removing them.
 func (c *Composer) Stop(ctx context.Context, opt StopOptions, services []string) error {
 	serviceNames, err := c.ServiceNames(services...)
 	if err != nil {
@@ -55,10 +56,10 @@ func (c *Composer) Stop(ctx context.Context, opt StopOptions, services []string)
 }
 
 func (c *Composer) stopContainers(ctx context.Context, containers []containerd.Container, opt StopOptions) error {
-	var timeoutArg string
+	var timeout *time.Duration
 	if opt.Timeout != nil {
-		// `nerdctl stop` uses `--time` instead of `--timeout`
-		timeoutArg = fmt.Sprintf("--time=%d", *opt.Timeout)
+		t := time.Duration(*opt.Timeout) * time.Second
+		timeout = &t
 	}
 
 	var rmWG sync.WaitGroup
@@ -69,12 +70,7 @@ func (c *Composer) stopContainers(ctx context.Context, containers []containerd.C
 			defer rmWG.Done()
 			info, _ := container.Info(ctx, containerd.WithoutRefreshedMetadata)
 			log.G(ctx).Infof("Stopping container %s", info.Labels[labels.Name])
-			args := []string{"stop"}
-			if opt.Timeout != nil {
-				args = append(args, timeoutArg)
-			}
-			args = append(args, container.ID())
-			if err := c.runNerdctlCmd(ctx, args...); err != nil {
+			if err := c.stopContainer(ctx, container.ID(), timeout); err != nil && ctx.Err() == nil {
 				log.G(ctx).Warn(err)
 			}
 		}()
@@ -93,10 +89,57 @@ func (c *Composer) stopContainersFromParsedServices(ctx context.Context, contain
 		go func() {
 			defer rmWG.Done()
 			log.G(ctx).Infof("Stopping container %s", container.Name)
-			if err := c.runNerdctlCmd(ctx, "stop", id); err != nil {
+			if err := c.stopContainer(ctx, id, nil); err != nil && ctx.Err() == nil {
 				log.G(ctx).Warn(err)
 			}
 		}()
 	}
 	rmWG.Wait()
 }
+
+// stopServicesGracefully stops the containers of the services with their stop signal and their stop timeout,
+// i.e., `stop_signal` and `stop_grace_period`, and kills them when interrupted again.
+//
+// The containers are looked up by their labels, as `up --watch` may have recreated them since they were started.
+func (c *Composer) stopServicesGracefully(ctx context.Context, services []string) {
+	containers, err := c.Containers(ctx, services...)
+	if err != nil {
+		log.G(ctx).Warn(err)
+		return
+	}
+
+	sigC := make(chan os.Signal, 1)
+	signal.Notify(sigC, os.Interrupt)
+	defer signal.Stop(sigC)
+
+	stopCtx, cancelStop := context.WithCancel(ctx)
+	defer cancelStop()
+	stopped := make(chan struct{})
+	go func() {
+		c.stopContainers(stopCtx, containers, StopOptions{})
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return
+	case <-sigC:
+	}
+	log.G(ctx).Info("Killing containers")
+	cancelStop()
+	<-stopped
+	var killWG sync.WaitGroup
+	for _, container := range containers {
+		container := container
+		killWG.Add(1)
+		go func() {
+			defer killWG.Done()
+			// a zero timeout sends SIGKILL immediately
+			var timeout time.Duration
+			if err := c.stopContainer(ctx, container.ID(), &timeout); err != nil {
+				log.G(ctx).Warn(err)
+			}
+		}()
+	}
+	killWG.Wait()
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
 
@@ -43,6 +44,9 @@ type UpOptions struct {
 	NoRecreate           bool
 	Scale                map[string]int // map of service name to replicas
 	Pull                 string
+	Watch                bool // watch the files of the services, see the `develop` section
+	Wait                 bool // wait for the services to be running and healthy, implies Detach
+	WaitTimeout          time.Duration
 }
 
 func (opts UpOptions) recreateStrategy() string {
@@ -92,7 +96,7 @@ func (c *Composer) Up(ctx context.Context, uo UpOptions, services []string) erro
 			}
 			svc.Deploy.Replicas = &replicas
 		}
-		ps, err := serviceparser.Parse(c.project, *svc)
+		ps, err := c.parseService(*svc)
 		if err != nil {
 			return err
 		}
@@ -122,12 +126,16 @@ func (c *Composer) Up(ctx context.Context, uo UpOptions, services []string) erro
 }
 
 func validateFileObjectConfig(obj types.FileObjectConfig, shortName, objType string, project *types.Project) error {
-	if unknown := reflectutil.UnknownNonEmptyFields(&obj, "Name", "External", "File"); len(unknown) > 0 {
+	if unknown := reflectutil.UnknownNonEmptyFields(&obj, "Name", "External", "File", "Environment", "Content"); len(unknown) > 0 {
 		log.L.Warnf("Ignoring: %s %s: %+v", objType, shortName, unknown)
 	}
 
+	if obj.Environment != "" || obj.Content != "" {
+		// written for the containers by writeFiles
+		return nil
+	}
 	if obj.File == "" {
-		return fmt.Errorf("%s %q: lacks file path", objType, shortName)
+		return fmt.Errorf("%s %q: lacks file path, environment or content", objType, shortName)
 	}
 	fullPath := project.RelativePath(obj.File)
 	if _, err := os.Stat(fullPath); err != nil {
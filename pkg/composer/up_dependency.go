@@ -0,0 +1,185 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+const (
+	// dependencyWaitTimeout is the maximum duration to wait for a dependency to satisfy its depends_on condition.
+	dependencyWaitTimeout = 5 * time.Minute
+	// dependencyPollInterval is the interval between checks of the dependency containers.
+	dependencyPollInterval = 500 * time.Millisecond
+)
+
+// waitDependencies blocks until the dependencies of svc satisfy their depends_on conditions.
+// The dependencies are expected to have been started already.
+func (c *Composer) waitDependencies(ctx context.Context, svc *types.ServiceConfig) error {
+	for depName, dep := range svc.DependsOn {
+		switch dep.Condition {
+		case types.ServiceConditionHealthy, types.ServiceConditionCompletedSuccessfully:
+		default:
+			continue
+		}
+		log.G(ctx).Infof("Waiting for service %s to satisfy condition %s", depName, dep.Condition)
+		if err := c.waitDependency(ctx, depName, dep.Condition); err != nil {
+			if !dep.Required {
+				log.G(ctx).WithError(err).Warnf("service %s: optional dependency %s did not satisfy condition %s", svc.Name, depName, dep.Condition)
+				continue
+			}
+			return fmt.Errorf("service %s: dependency %s failed to satisfy condition %s: %w", svc.Name, depName, dep.Condition, err)
+		}
+	}
+	return nil
+}
+
+func (c *Composer) waitDependency(ctx context.Context, depName, condition string) error {
+	ctx, cancel := context.WithTimeout(ctx, dependencyWaitTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(dependencyPollInterval)
+	defer ticker.Stop()
+	for {
+		satisfied, err := c.dependencySatisfied(ctx, depName, condition)
+		if err != nil {
+			return err
+		}
+		if satisfied {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out after %s", dependencyWaitTimeout)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// dependencySatisfied returns true once all the containers of the service satisfy condition.
+// It returns an error when a container can no longer satisfy it, e.g., when it has turned unhealthy,
+// or when it has exited with a non-zero code.
+func (c *Composer) dependencySatisfied(ctx context.Context, depName, condition string) (bool, error) {
+	containers, err := c.Containers(ctx, depName)
+	if err != nil {
+		return false, err
+	}
+	if len(containers) == 0 {
+		return false, fmt.Errorf("no container found for service %s", depName)
+	}
+	for _, container := range containers {
+		var satisfied bool
+		switch condition {
+		case types.ServiceConditionHealthy:
+			satisfied, err = containerHealthy(ctx, container)
+		case types.ServiceConditionCompletedSuccessfully:
+			satisfied, err = containerCompletedSuccessfully(ctx, container)
+		default:
+			return false, fmt.Errorf("unsupported depends_on condition %q", condition)
+		}
+		if err != nil || !satisfied {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func containerHealthy(ctx context.Context, container containerd.Container) (bool, error) {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return false, err
+	}
+	name := lab[labels.Name]
+	hcJSON, ok := lab[labels.HealthCheck]
+	if !ok {
+		return false, fmt.Errorf("container %s has no healthcheck configured", name)
+	}
+	hc, err := healthcheck.HealthCheckFromJSON(hcJSON)
+	if err != nil {
+		return false, err
+	}
+	if len(hc.Test) == 0 || hc.Test[0] == healthcheck.CmdNone {
+		return false, fmt.Errorf("container %s has its healthcheck disabled", name)
+	}
+
+	status, err := containerutil.ContainerStatus(ctx, container)
+	if err != nil {
+		// The task may not have been created yet
+		if errdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if status.Status == containerd.Stopped {
+		return false, fmt.Errorf("container %s exited (%d)", name, status.ExitStatus)
+	}
+
+	stateJSON, ok := lab[labels.HealthState]
+	if !ok {
+		return false, nil
+	}
+	state, err := healthcheck.HealthStateFromJSON(stateJSON)
+	if err != nil {
+		return false, err
+	}
+	switch state.Status {
+	case healthcheck.Healthy:
+		return true, nil
+	case healthcheck.Unhealthy:
+		return false, fmt.Errorf("container %s is unhealthy", name)
+	default:
+		return false, nil
+	}
+}
+
+func containerCompletedSuccessfully(ctx context.Context, container containerd.Container) (bool, error) {
+	status, err := containerutil.ContainerStatus(ctx, container)
+	if err != nil {
+		// The task may not have been created yet
+		if errdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if status.Status != containerd.Stopped {
+		return false, nil
+	}
+	if status.ExitStatus != 0 {
+		lab, err := container.Labels(ctx)
+		if err != nil {
+			return false, err
+		}
+		return false, fmt.Errorf("container %s exited (%d)", lab[labels.Name], status.ExitStatus)
+	}
+	return true, nil
+}
@@ -0,0 +1,203 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/jobs"
+)
+
+// imageConcurrency is the maximum number of images pulled or built at once.
+const imageConcurrency = 4
+
+// ensureServiceImages pulls and builds the images of the services concurrently.
+//
+// The images are pulled first, with their progress displayed in a single block, then built,
+// with the lines of their output tagged with the service name, so that the outputs are not interleaved.
+func (c *Composer) ensureServiceImages(ctx context.Context, parsedServices []*serviceparser.Service, allowBuild, forceBuild bool, bo BuildOptions, quiet bool, pullModeArg string) error {
+	var (
+		builds         []*serviceparser.Service
+		pulls          []*serviceparser.Service
+		pullCandidates []*serviceparser.Service
+		seenBuilds     = make(map[string]struct{})
+		seenPulls      = make(map[pullKey]struct{})
+	)
+	for _, ps := range parsedServices {
+		// the image of several services is built once
+		if _, ok := seenBuilds[ps.Image]; ok {
+			continue
+		}
+		build, err := c.needsBuild(ctx, ps, allowBuild, forceBuild)
+		if err != nil {
+			return err
+		}
+		if build {
+			seenBuilds[ps.Image] = struct{}{}
+			builds = append(builds, ps)
+		} else {
+			pullCandidates = append(pullCandidates, ps)
+		}
+	}
+	for _, ps := range pullCandidates {
+		// the image of several services is pulled once per pull and verification options,
+		// and not pulled at all when it is built for another service
+		if _, ok := seenBuilds[ps.Image]; ok {
+			continue
+		}
+		key := newPullKey(ps)
+		if _, ok := seenPulls[key]; !ok {
+			seenPulls[key] = struct{}{}
+			pulls = append(pulls, ps)
+		}
+	}
+
+	if err := c.pullServiceImages(ctx, pulls, quiet, pullModeArg); err != nil {
+		return err
+	}
+
+	var tagWidth int
+	for _, ps := range builds {
+		tagWidth = max(tagWidth, len(ps.Unparsed.Name))
+	}
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(imageConcurrency)
+	for _, ps := range builds {
+		ps := ps
+		bo := bo
+		if len(builds) > 1 {
+			bo.tag = ps.Unparsed.Name
+			bo.tagWidth = tagWidth
+		}
+		eg.Go(func() error {
+			return c.buildServiceImage(egCtx, ps.Image, ps.Build, ps.Unparsed.Platform, bo)
+		})
+	}
+	return eg.Wait()
+}
+
+// verifyExtensions are the extensions of a service that affect the verification of its image.
+var verifyExtensions = []string{
+	serviceparser.ComposeVerify,
+	serviceparser.ComposeCosignPublicKey,
+	serviceparser.ComposeCosignCertificateIdentity,
+	serviceparser.ComposeCosignCertificateIdentityRegexp,
+	serviceparser.ComposeCosignCertificateOidcIssuer,
+	serviceparser.ComposeCosignCertificateOidcIssuerRegexp,
+	serviceparser.ComposeCosignInsecureIgnoreTlog,
+}
+
+// pullKey identifies the pull of the image of a service.
+// The services sharing an image with different pull or verification options are pulled separately,
+// so that the image is verified for each of them.
+type pullKey struct {
+	image    string
+	platform string
+	pullMode string
+	verify   string
+}
+
+func newPullKey(ps *serviceparser.Service) pullKey {
+	var verify strings.Builder
+	for _, ext := range verifyExtensions {
+		if v, ok := ps.Unparsed.Extensions[ext]; ok {
+			fmt.Fprintf(&verify, "%s=%v\n", ext, v)
+		}
+	}
+	return pullKey{
+		image:    ps.Image,
+		platform: ps.Unparsed.Platform,
+		pullMode: ps.PullMode,
+		verify:   verify.String(),
+	}
+}
+
+// needsBuild returns true when the image of the service has to be built rather than pulled.
+func (c *Composer) needsBuild(ctx context.Context, ps *serviceparser.Service, allowBuild, forceBuild bool) (bool, error) {
+	if ps.Build == nil || !allowBuild {
+		return false, nil
+	}
+	if ps.Build.Force || forceBuild {
+		return true, nil
+	}
+	ok, err := c.ImageExists(ctx, ps.Image)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		// even when c.ImageExists returns true, we need to call c.EnsureImage
+		// because ps.PullMode can be "always".
+		log.G(ctx).Debugf("Image %s already exists, not building", ps.Image)
+	}
+	return !ok, nil
+}
+
+func (c *Composer) pullServiceImages(ctx context.Context, pulls []*serviceparser.Service, quiet bool, pullModeArg string) error {
+	var mp *jobs.MultiProgress
+	if len(pulls) > 1 && !quiet {
+		mp = jobs.NewMultiProgress(os.Stderr)
+		progressCtx, stopProgress := context.WithCancel(ctx)
+		progressDone := make(chan struct{})
+		go func() {
+			mp.Run(progressCtx)
+			close(progressDone)
+		}()
+		defer func() {
+			stopProgress()
+			<-progressDone
+		}()
+	}
+
+	images := make(map[string]int)
+	for _, ps := range pulls {
+		images[ps.Image]++
+	}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(imageConcurrency)
+	for _, ps := range pulls {
+		ps := ps
+		pullMode := ps.PullMode
+		if pullModeArg != "" {
+			pullMode = pullModeArg
+		}
+		// logged before displaying the progress, not to break it
+		log.G(ctx).Infof("Ensuring image %s", ps.Image)
+		var progressOutput io.Writer
+		if mp != nil {
+			name := ps.Image
+			if images[ps.Image] > 1 {
+				// the image is pulled for several services, with different options
+				name = fmt.Sprintf("%s (%s)", ps.Image, ps.Unparsed.Name)
+			}
+			progressOutput = mp.Writer(name)
+		}
+		eg.Go(func() error {
+			return c.EnsureImage(egCtx, ps.Image, pullMode, ps.Unparsed.Platform, ps, quiet, progressOutput)
+		})
+	}
+	return eg.Wait()
+}
@@ -21,17 +21,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"strings"
-	"sync"
+	"slices"
 
 	"golang.org/x/sync/errgroup"
 
 	"github.com/containerd/log"
 
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
-	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
 func (c *Composer) upServices(ctx context.Context, parsedServices []*serviceparser.Service, uo UpOptions) error {
@@ -39,52 +35,74 @@ func (c *Composer) upServices(ctx context.Context, parsedServices []*servicepars
 		return errors.New("no service was provided")
 	}
 
-	// TODO: parallelize loop for ensuring images (make sure not to mess up tty)
-	for _, ps := range parsedServices {
-		if err := c.ensureServiceImage(ctx, ps, !uo.NoBuild, uo.ForceBuild, BuildOptions{}, uo.QuietPull, uo.Pull); err != nil {
-			return err
-		}
+	if err := c.ensureServiceImages(ctx, parsedServices, !uo.NoBuild, uo.ForceBuild, BuildOptions{}, uo.QuietPull, uo.Pull); err != nil {
+		return err
 	}
 
 	recreate := uo.recreateStrategy()
 
 	var (
-		containers   = make(map[string]serviceparser.Container) // key: container ID
-		services     = []string{}
-		containersMu sync.Mutex
+		services = make([]string, 0, len(parsedServices))
+		// closed once all the containers of the service are started
+		started = make(map[string]chan struct{}, len(parsedServices))
 	)
 	for _, ps := range parsedServices {
-		ps := ps
-		var runEG errgroup.Group
 		services = append(services, ps.Unparsed.Name)
-		for _, container := range ps.Containers {
-			container := container
-			runEG.Go(func() error {
-				id, err := c.upServiceContainer(ctx, ps, container, recreate)
-				if err != nil {
-					return err
+		started[ps.Unparsed.Name] = make(chan struct{})
+	}
+
+	// the services are started concurrently, each one as soon as its dependencies are started
+	// and satisfy their depends_on conditions
+	upEG, upCtx := errgroup.WithContext(ctx)
+	for _, ps := range parsedServices {
+		ps := ps
+		upEG.Go(func() error {
+			for _, dep := range ps.Unparsed.GetDependencies() {
+				depStarted, ok := started[dep]
+				if !ok {
+					// not brought up by this command
+					continue
 				}
-				containersMu.Lock()
-				containers[id] = container
-				containersMu.Unlock()
-				return nil
-			})
-		}
-		if err := runEG.Wait(); err != nil {
-			return err
-		}
+				select {
+				case <-depStarted:
+				case <-upCtx.Done():
+					return upCtx.Err()
+				}
+			}
+			if err := c.waitDependencies(upCtx, ps.Unparsed); err != nil {
+				return err
+			}
+			var runEG errgroup.Group
+			for _, container := range ps.Containers {
+				container := container
+				runEG.Go(func() error {
+					_, err := c.upServiceContainer(upCtx, ps, container, recreate)
+					return err
+				})
+			}
+			if err := runEG.Wait(); err != nil {
+				return err
+			}
+			close(started[ps.Unparsed.Name])
+			return nil
+		})
+	}
+	if err := upEG.Wait(); err != nil {
+		return err
 	}
 
+	if uo.Wait {
+		return c.waitServices(ctx, parsedServices, uo.WaitTimeout)
+	}
 	if uo.Detach {
 		return nil
 	}
 
 	// this is used to stop containers in case --abort-on-container-exit flag is set.
-	// c.Logs returns an error, so we don't need Ctrl-c to reach the "Stopping containers (forcibly)"
+	// c.Logs returns an error, so we don't need Ctrl-c to reach the graceful stop of the containers
 	if uo.AbortOnContainerExit {
-		defer c.stopContainersFromParsedServices(ctx, containers)
+		defer c.stopServicesGracefully(ctx, services)
 	}
-	log.G(ctx).Info("Attaching to logs")
 	lo := LogsOptions{
 		AbortOnContainerExit: uo.AbortOnContainerExit,
 		Follow:               true,
@@ -92,38 +110,36 @@ func (c *Composer) upServices(ctx context.Context, parsedServices []*servicepars
 		NoLogPrefix:          uo.NoLogPrefix,
 		LatestRun:            recreate == RecreateNever,
 	}
-	if err := c.Logs(ctx, lo, services); err != nil {
-		return err
-	}
-
-	log.G(ctx).Infof("Stopping containers (forcibly)") // TODO: support gracefully stopping
-	c.stopContainersFromParsedServices(ctx, containers)
-	return nil
-}
-
-func (c *Composer) ensureServiceImage(ctx context.Context, ps *serviceparser.Service, allowBuild, forceBuild bool, bo BuildOptions, quiet bool, pullModeArg string) error {
-	if ps.Build != nil && allowBuild {
-		if ps.Build.Force || forceBuild {
-			return c.buildServiceImage(ctx, ps.Image, ps.Build, ps.Unparsed.Platform, bo)
-		}
-		if ok, err := c.ImageExists(ctx, ps.Image); err != nil {
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	defer cancelWatch()
+	if uo.Watch {
+		w, err := c.newWatcher(services)
+		if err != nil {
 			return err
-		} else if !ok {
-			return c.buildServiceImage(ctx, ps.Image, ps.Build, ps.Unparsed.Platform, bo)
 		}
-		// even when c.ImageExists returns true, we need to call c.EnsureImage
-		// because ps.PullMode can be "always". So no return here.
-		log.G(ctx).Debugf("Image %s already exists, not building", ps.Image)
+		defer w.fsw.Close()
+		recreated := make(chan recreateEvent)
+		w.recreated = recreated
+		lo.recreated = recreated
+		go func() {
+			if err := w.run(watchCtx); err != nil {
+				log.G(ctx).WithError(err).Error("failed to watch the files of the services")
+			}
+		}()
 	}
 
-	log.G(ctx).Infof("Ensuring image %s", ps.Image)
-	if pullModeArg != "" {
-		return c.EnsureImage(ctx, ps.Image, pullModeArg, ps.Unparsed.Platform, ps, quiet)
+	log.G(ctx).Info("Attaching to logs")
+	if err := c.Logs(ctx, lo, services); err != nil {
+		return err
 	}
-	return c.EnsureImage(ctx, ps.Image, ps.PullMode, ps.Unparsed.Platform, ps, quiet)
+	cancelWatch()
+
+	log.G(ctx).Info("Stopping containers gracefully (press Ctrl-C again to force)")
+	c.stopServicesGracefully(ctx, services)
+	return nil
 }
 
-// upServiceContainer must be called after ensureServiceImage
+// upServiceContainer must be called after ensureServiceImages
 // upServiceContainer returns container ID
 func (c *Composer) upServiceContainer(ctx context.Context, service *serviceparser.Service, container serviceparser.Container, recreate string) (string, error) {
 	// check if container already exists
@@ -137,16 +153,22 @@ func (c *Composer) upServiceContainer(ctx context.Context, service *serviceparse
 		return "", fmt.Errorf("currently StdinOpen(-i) and Tty(-t) should be same")
 	}
 
-	var runFlagD bool
 	if !service.Unparsed.StdinOpen && !service.Unparsed.Tty {
-		container.RunArgs = append([]string{"-d"}, container.RunArgs...)
-		runFlagD = true
+		container.CreateOptions.Detach = true
 	}
 
 	// start the existing container and exit early
 	if existingCid != "" && recreate == RecreateNever {
-		cmd := c.createNerdctlCmd(ctx, append([]string{"start"}, existingCid)...)
-		if err := c.executeUpCmd(ctx, cmd, container.Name, runFlagD, service.Unparsed.StdinOpen); err != nil {
+		// the files are written before starting the existing container too, as their tmpfs does not survive a reboot
+		if _, err := c.writeFiles(ctx, container); err != nil {
+			return "", fmt.Errorf("error while writing the secrets and configs of container %s: %w", container.Name, err)
+		}
+		if container.CreateOptions.Detach {
+			err = c.startContainer(ctx, existingCid)
+		} else {
+			err = c.attachContainer(ctx, existingCid, container.CreateOptions)
+		}
+		if err != nil {
 			return "", fmt.Errorf("error while starting existing container %s: %w", container.Name, err)
 		}
 		return existingCid, nil
@@ -155,8 +177,7 @@ func (c *Composer) upServiceContainer(ctx context.Context, service *serviceparse
 	// delete container if it already exists
 	if existingCid != "" {
 		log.G(ctx).Debugf("Container %q already exists, deleting", container.Name)
-		delCmd := c.createNerdctlCmd(ctx, "rm", "-f", container.Name)
-		if err = delCmd.Run(); err != nil {
+		if err = c.removeContainer(ctx, existingCid, container.Name, false); err != nil {
 			return "", fmt.Errorf("could not delete container %q: %w", container.Name, err)
 		}
 		log.G(ctx).Infof("Re-creating container %s", container.Name)
@@ -171,58 +192,21 @@ func (c *Composer) upServiceContainer(ctx context.Context, service *serviceparse
 		}
 	}
 
-	tempDir, err := os.MkdirTemp(os.TempDir(), "compose-")
+	fileVolumes, err := c.writeFiles(ctx, container)
 	if err != nil {
-		return "", fmt.Errorf("error while creating/re-creating container %s: %w", container.Name, err)
+		return "", fmt.Errorf("error while writing the secrets and configs of container %s: %w", container.Name, err)
 	}
-	defer os.RemoveAll(tempDir)
-	cidFilename := filepath.Join(tempDir, "cid")
+	container.CreateOptions.Volume = slices.Concat(fileVolumes, container.CreateOptions.Volume)
 
 	if c.EnvFile != "" {
-		container.RunArgs = append([]string{"--env-file=" + c.EnvFile}, container.RunArgs...)
-	}
-
-	//add metadata labels to container https://github.com/compose-spec/compose-spec/blob/master/spec.md#labels
-	container.RunArgs = append([]string{
-		"--cidfile=" + cidFilename,
-		fmt.Sprintf("-l=%s=%s", labels.ComposeProject, c.project.Name),
-		fmt.Sprintf("-l=%s=%s", labels.ComposeService, service.Unparsed.Name),
-	}, container.RunArgs...)
-
-	cmd := c.createNerdctlCmd(ctx, append([]string{"run"}, container.RunArgs...)...)
-	if c.DebugPrintFull {
-		log.G(ctx).Debugf("Running %v", cmd.Args)
+		container.CreateOptions.EnvFile = slices.Concat([]string{c.EnvFile}, container.CreateOptions.EnvFile)
 	}
 
-	if err := c.executeUpCmd(ctx, cmd, container.Name, runFlagD, service.Unparsed.StdinOpen); err != nil {
-		return "", fmt.Errorf("error while creating container %s: %w", container.Name, err)
-	}
+	container.CreateOptions.Label = slices.Concat(c.composeLabels(service), container.CreateOptions.Label)
 
-	cid, err := os.ReadFile(cidFilename)
+	cid, err := c.createContainer(ctx, container, true)
 	if err != nil {
 		return "", fmt.Errorf("error while creating container %s: %w", container.Name, err)
 	}
-	return strings.TrimSpace(string(cid)), nil
-}
-
-func (c *Composer) executeUpCmd(ctx context.Context, cmd *exec.Cmd, containerName string, runFlagD, stdinOpen bool) error {
-	log.G(ctx).Infof("Running %v", cmd.Args)
-	if c.DebugPrintFull {
-		log.G(ctx).Debugf("Running %v", cmd.Args)
-	}
-
-	if stdinOpen {
-		cmd.Stdin = os.Stdin
-	}
-	if !runFlagD {
-		cmd.Stdout = os.Stdout
-	}
-	// Always propagate stderr to print detailed error messages (https://github.com/containerd/nerdctl/issues/1942)
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("error while creating container %s: %w", containerName, err)
-	}
-
-	return nil
+	return cid, nil
 }
@@ -0,0 +1,206 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/pipetagger"
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// waitLogTail is the number of the last log lines printed for a service that failed to get ready.
+const waitLogTail = 20
+
+// waitServices blocks until all the containers of the services are running, and healthy when they have a healthcheck.
+// The containers of the services that other services depend on with the condition service_completed_successfully
+// may have exited with code 0 instead.
+//
+// waitServices returns an error, after printing the last log lines of the failing service, when a container
+// has exited or is unhealthy, or when timeout is reached. A zero timeout waits indefinitely.
+func (c *Composer) waitServices(ctx context.Context, parsedServices []*serviceparser.Service, timeout time.Duration) error {
+	// waiting does not need the lock
+	if err := Unlock(); err != nil {
+		return err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	completable := make(map[string]bool)
+	for _, svc := range c.project.Services {
+		for depName, dep := range svc.DependsOn {
+			if dep.Condition == types.ServiceConditionCompletedSuccessfully {
+				completable[depName] = true
+			}
+		}
+	}
+
+	pending := make(map[string]struct{}, len(parsedServices))
+	for _, ps := range parsedServices {
+		pending[ps.Unparsed.Name] = struct{}{}
+	}
+	log.G(ctx).Info("Waiting for the services to be ready")
+
+	ticker := time.NewTicker(dependencyPollInterval)
+	defer ticker.Stop()
+	for {
+		for _, ps := range parsedServices {
+			name := ps.Unparsed.Name
+			if _, ok := pending[name]; !ok {
+				continue
+			}
+			ready, failed, err := c.serviceReady(ctx, name, completable[name])
+			if err != nil {
+				if failed != nil {
+					c.printLastLogs(ctx, failed)
+				}
+				return fmt.Errorf("service %s failed to get ready: %w", name, err)
+			}
+			if ready {
+				log.G(ctx).Infof("Service %s is ready", name)
+				delete(pending, name)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ctx.Err()
+			}
+			var names []string
+			for _, ps := range parsedServices {
+				if _, ok := pending[ps.Unparsed.Name]; ok {
+					names = append(names, ps.Unparsed.Name)
+					// the context is done, the logs are read with a new one
+					c.printServiceLastLogs(context.WithoutCancel(ctx), ps.Unparsed.Name)
+				}
+			}
+			return fmt.Errorf("timed out after %s waiting for services %s to get ready", timeout, strings.Join(names, ", "))
+		case <-ticker.C:
+		}
+	}
+}
+
+// serviceReady returns true once all the containers of the service are ready.
+// On error, it returns the container that failed to get ready, if any.
+func (c *Composer) serviceReady(ctx context.Context, service string, completable bool) (bool, containerd.Container, error) {
+	containers, err := c.Containers(ctx, service)
+	if err != nil {
+		return false, nil, err
+	}
+	for _, container := range containers {
+		ready, err := containerReady(ctx, container, completable)
+		if err != nil {
+			return false, container, err
+		}
+		if !ready {
+			return false, nil, nil
+		}
+	}
+	return true, nil, nil
+}
+
+func containerReady(ctx context.Context, container containerd.Container, completable bool) (bool, error) {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return false, err
+	}
+	name := lab[labels.Name]
+	status, err := containerutil.ContainerStatus(ctx, container)
+	if err != nil {
+		// The task may not have been created yet
+		if errdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	switch status.Status {
+	case containerd.Running:
+	case containerd.Stopped:
+		if completable && status.ExitStatus == 0 {
+			return true, nil
+		}
+		return false, fmt.Errorf("container %s exited (%d)", name, status.ExitStatus)
+	default:
+		return false, nil
+	}
+
+	hcJSON, ok := lab[labels.HealthCheck]
+	if !ok {
+		return true, nil
+	}
+	hc, err := healthcheck.HealthCheckFromJSON(hcJSON)
+	if err != nil {
+		return false, err
+	}
+	if len(hc.Test) == 0 || hc.Test[0] == healthcheck.CmdNone {
+		return true, nil
+	}
+	return containerHealthy(ctx, container)
+}
+
+func (c *Composer) printServiceLastLogs(ctx context.Context, service string) {
+	containers, err := c.Containers(ctx, service)
+	if err != nil {
+		log.G(ctx).Warn(err)
+		return
+	}
+	for _, container := range containers {
+		c.printLastLogs(ctx, container)
+	}
+}
+
+// printLastLogs prints the last log lines of the container, tagged like `compose logs`.
+func (c *Composer) printLastLogs(ctx context.Context, container containerd.Container) {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		log.G(ctx).Warn(err)
+		return
+	}
+	tag := strings.TrimPrefix(lab[labels.Name], c.project.Name+serviceparser.Separator)
+	cmd := c.createNerdctlCmd(ctx, "logs", "-n", fmt.Sprint(waitLogTail), container.ID())
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to get the logs of container %s", lab[labels.Name])
+		return
+	}
+	log.G(ctx).Infof("Last logs of container %s:", lab[labels.Name])
+	if err := pipetagger.New(os.Stderr, bytes.NewReader(out), tag, len(tag)+1, false).Run(); err != nil {
+		log.G(ctx).Warn(err)
+	}
+}
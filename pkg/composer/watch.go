@@ -0,0 +1,366 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/containerd/log"
+)
+
+// watchDebounce is the quiet period after a file event before the actions are applied,
+// so that a burst of changes (e.g., a git checkout) triggers a single sync, restart or rebuild.
+const watchDebounce = 500 * time.Millisecond
+
+// defaultWatchIgnores are always ignored, as in Docker Compose: the VCS metadata and the temporary files of editors.
+var defaultWatchIgnores = []string{".git", "*~", "*.swp", "*.swx"}
+
+type watchTrigger struct {
+	service string
+	types.Trigger
+}
+
+type watcher struct {
+	c        *Composer
+	triggers []watchTrigger
+	services []string // in dependency order
+	fsw      *fsnotify.Watcher
+	// recreated notifies the logs of `up --watch` of the containers recreated by a rebuild, nil for `compose watch`
+	recreated chan<- recreateEvent
+}
+
+// Watch synchronizes the files of the services with their containers, following the
+// `develop.watch` section of the services, until ctx is done.
+func (c *Composer) Watch(ctx context.Context, services []string) error {
+	w, err := c.newWatcher(services)
+	if err != nil {
+		return err
+	}
+	defer w.fsw.Close()
+	// like `compose logs`, this is a long-running operation that must not prevent other compose commands
+	if err := Unlock(); err != nil {
+		return err
+	}
+	return w.run(ctx)
+}
+
+func (c *Composer) newWatcher(services []string) (*watcher, error) {
+	w := &watcher{c: c}
+	if err := c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
+		if svc.Develop == nil {
+			return nil
+		}
+		for _, trigger := range svc.Develop.Watch {
+			switch trigger.Action {
+			case types.WatchActionSync, types.WatchActionSyncRestart:
+				if trigger.Target == "" {
+					return fmt.Errorf("service %q: watch: %s of %q requires a target", svc.Name, trigger.Action, trigger.Path)
+				}
+			case types.WatchActionRebuild:
+				if svc.Build == nil {
+					return fmt.Errorf("service %q: watch: rebuild of %q requires a build section", svc.Name, trigger.Path)
+				}
+			case types.WatchActionRestart:
+			default:
+				log.L.Warnf("Ignoring: service %s: watch: unsupported action %q", svc.Name, trigger.Action)
+				continue
+			}
+			if len(trigger.Include) > 0 {
+				log.L.Warnf("Ignoring: service %s: watch: include", svc.Name)
+			}
+			w.triggers = append(w.triggers, watchTrigger{service: svc.Name, Trigger: trigger})
+		}
+		w.services = append(w.services, svc.Name)
+		return nil
+	}, types.IgnoreDependencies); err != nil {
+		return nil, err
+	}
+	if len(w.triggers) == 0 {
+		return nil, errors.New("none of the selected services is configured for watch, consider setting a 'develop' section")
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w.fsw = fsw
+	for _, trigger := range w.triggers {
+		if err := w.add(trigger, trigger.Path); err != nil {
+			fsw.Close()
+			return nil, fmt.Errorf("service %q: failed to watch %q: %w", trigger.service, trigger.Path, err)
+		}
+	}
+	return w, nil
+}
+
+// add watches p, recursively when it is a directory. fsnotify does not watch directories
+// recursively, and files are watched through their parent directory to survive atomic saves.
+func (w *watcher) add(trigger watchTrigger, p string) error {
+	st, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	if !st.IsDir() {
+		return w.fsw.Add(filepath.Dir(p))
+	}
+	return filepath.WalkDir(p, func(dir string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if _, ok := trigger.match(dir); !ok {
+			return filepath.SkipDir
+		}
+		return w.fsw.Add(dir)
+	})
+}
+
+// match returns the path of p relative to the path of the trigger, if p is watched by the trigger.
+func (t watchTrigger) match(p string) (string, bool) {
+	rel, err := filepath.Rel(t.Path, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	if watchIgnored(slices.Concat(defaultWatchIgnores, t.Ignore), rel) {
+		return "", false
+	}
+	return rel, true
+}
+
+// watchIgnored reports whether the relative path, or one of its parent directories, matches one of the patterns.
+// Patterns without a slash match a file or directory name at any depth.
+func watchIgnored(patterns []string, rel string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		anyDepth := !strings.Contains(pattern, "/")
+		for p := rel; p != "." && p != "/"; p = path.Dir(p) {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+			if ok, _ := path.Match(pattern, path.Base(p)); ok && anyDepth {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (w *watcher) run(ctx context.Context) error {
+	for _, trigger := range w.triggers {
+		log.G(ctx).Infof("Watching %s for service %s (%s)", trigger.Path, trigger.service, trigger.Action)
+	}
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	pending := make(map[string]struct{})
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				for _, trigger := range w.triggers {
+					if _, ok := trigger.match(event.Name); ok {
+						if st, err := os.Stat(event.Name); err == nil && st.IsDir() {
+							if err := w.add(trigger, event.Name); err != nil {
+								log.G(ctx).WithError(err).Warnf("failed to watch %q", event.Name)
+							}
+						}
+					}
+				}
+			}
+			pending[event.Name] = struct{}{}
+			timer.Reset(watchDebounce)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return nil
+			}
+			log.G(ctx).WithError(err).Warn("error while watching files")
+		case <-timer.C:
+			changed := pending
+			pending = make(map[string]struct{})
+			w.apply(ctx, changed)
+		}
+	}
+}
+
+type watchSync struct {
+	src string
+	dst string
+}
+
+// apply applies the actions of the triggers matching the changed paths, service by service.
+// The errors are logged, so that the watch goes on.
+func (w *watcher) apply(ctx context.Context, changed map[string]struct{}) {
+	for _, service := range w.services {
+		var (
+			rebuild, restart bool
+			syncs            []watchSync
+		)
+		for _, trigger := range w.triggers {
+			if trigger.service != service {
+				continue
+			}
+			for p := range changed {
+				rel, ok := trigger.match(p)
+				if !ok {
+					continue
+				}
+				switch trigger.Action {
+				case types.WatchActionRebuild:
+					rebuild = true
+				case types.WatchActionRestart:
+					restart = true
+				case types.WatchActionSyncRestart:
+					restart = true
+					fallthrough
+				case types.WatchActionSync:
+					syncs = append(syncs, watchSync{src: p, dst: path.Join(trigger.Target, filepath.ToSlash(rel))})
+				}
+			}
+		}
+		if rebuild {
+			// the image contains the synchronized files too, and the new containers are started anyway
+			if err := w.rebuild(ctx, service); err != nil {
+				log.G(ctx).WithError(err).Errorf("failed to rebuild service %s", service)
+			}
+			continue
+		}
+		for _, s := range syncs {
+			if err := w.c.watchSync(ctx, service, s); err != nil {
+				log.G(ctx).WithError(err).Errorf("failed to sync %s to service %s", s.src, service)
+			}
+		}
+		if restart {
+			containers, err := w.c.Containers(ctx, service)
+			if err != nil {
+				log.G(ctx).WithError(err).Errorf("failed to restart service %s", service)
+				continue
+			}
+			if err := w.c.restartContainers(ctx, containers, RestartOptions{}); err != nil {
+				log.G(ctx).WithError(err).Errorf("failed to restart service %s", service)
+			}
+		}
+	}
+}
+
+// watchSync copies a changed path into the containers of the service, or deletes it when it has been removed.
+func (c *Composer) watchSync(ctx context.Context, service string, s watchSync) error {
+	st, err := os.Stat(s.src)
+	if errors.Is(err, os.ErrNotExist) {
+		log.G(ctx).Infof("Removing %s from service %s", s.dst, service)
+		return c.watchExec(ctx, service, "rm", "-rf", s.dst)
+	} else if err != nil {
+		return err
+	}
+	co := CopyOptions{Source: s.src, Destination: service + ":" + s.dst}
+	if st.IsDir() {
+		// copy the content of the directory, not the directory itself into an existing one
+		co.Source = s.src + string(filepath.Separator) + "."
+	}
+	if err := c.Copy(ctx, co); err != nil {
+		// `nerdctl cp` requires the parent of the destination to exist
+		if mkdirErr := c.watchExec(ctx, service, "mkdir", "-p", path.Dir(s.dst)); mkdirErr != nil {
+			return errors.Join(err, mkdirErr)
+		}
+		return c.Copy(ctx, co)
+	}
+	return nil
+}
+
+func (c *Composer) watchExec(ctx context.Context, service string, args ...string) error {
+	containers, err := c.Containers(ctx, service)
+	if err != nil {
+		return err
+	}
+	for _, container := range containers {
+		if err := c.runNerdctlCmd(ctx, append([]string{"exec", container.ID()}, args...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuild builds the image of the service and recreates its containers, holding the global lock
+// that has been released while watching, so that it does not run concurrently with other compose commands.
+func (w *watcher) rebuild(ctx context.Context, service string) error {
+	return withLock(w.c.GlobalOptions.DataRoot, w.c.GlobalOptions.Address, func() error {
+		return w.rebuildLocked(ctx, service)
+	})
+}
+
+func (w *watcher) rebuildLocked(ctx context.Context, service string) error {
+	svc, err := w.c.project.GetService(service)
+	if err != nil {
+		return err
+	}
+	ps, err := w.c.parseService(svc)
+	if err != nil {
+		return err
+	}
+	log.G(ctx).Infof("Rebuilding service %s", service)
+	if err := w.c.buildServiceImage(ctx, ps.Image, ps.Build, ps.Unparsed.Platform, BuildOptions{}); err != nil {
+		return err
+	}
+
+	w.notifyRecreated(ctx, recreateEvent{service: service})
+	defer func() {
+		// the new containers are followed even when only some of them were recreated
+		containers, err := w.c.Containers(ctx, service)
+		if err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to list the containers of service %s", service)
+		}
+		w.notifyRecreated(ctx, recreateEvent{service: service, done: true, containers: containers})
+	}()
+	for _, container := range ps.Containers {
+		if _, err := w.c.upServiceContainer(ctx, ps, container, RecreateForce); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyRecreated sends ev to the logs of `up --watch`, if any.
+func (w *watcher) notifyRecreated(ctx context.Context, ev recreateEvent) {
+	if w.recreated == nil {
+		return
+	}
+	select {
+	case w.recreated <- ev:
+	case <-ctx.Done():
+	}
+}
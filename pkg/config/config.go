@@ -44,6 +44,8 @@ type Config struct {
 	// CDISpecDirs is a list of directories in which CDI specifications can be found.
 	CDISpecDirs []string `toml:"cdi_spec_dirs,omitempty"`
 	UsernsRemap string   `toml:"userns_remap, omitempty"`
+	// TrustPolicy is the path of the image trust policy (policy.json) enforced on the images of pull, run, create and compose.
+	TrustPolicy string `toml:"trust_policy,omitempty"`
 }
 
 // New creates a default Config object statically,
@@ -66,5 +68,6 @@ func New() *Config {
 		KubeHideDupe:     false,
 		CDISpecDirs:      ncdefaults.CDISpecDirs(),
 		UsernsRemap:      "",
+		TrustPolicy:      "",
 	}
 }
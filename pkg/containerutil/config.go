@@ -28,9 +28,16 @@ import (
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/config"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
 	"github.com/containerd/nerdctl/v2/pkg/ipcutil"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumedriver"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
 	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
 )
 
@@ -130,3 +137,157 @@ func ReconfigIPCContainer(ctx context.Context, c containerd.Container, client *c
 	}
 	return nil
 }
+
+// ReconfigVolumes mounts the volumes created with options (e.g. `--opt type=nfs`) that are used by the container,
+// in case they have been unmounted in the meantime, e.g. by a reboot of the host.
+func ReconfigVolumes(ctx context.Context, lab map[string]string, cfg *config.Config) error {
+	mountsJSON, ok := lab[labels.Mounts]
+	if !ok {
+		return nil
+	}
+	var mounts []dockercompat.MountPoint
+	if err := json.Unmarshal([]byte(mountsJSON), &mounts); err != nil {
+		return err
+	}
+	dataStore, err := clientutil.DataStore(cfg.DataRoot, cfg.Address)
+	if err != nil {
+		return err
+	}
+	volStore, err := volumestore.New(dataStore, lab[labels.Namespace])
+	if err != nil {
+		return err
+	}
+	if err := volStore.Lock(); err != nil {
+		return err
+	}
+	defer volStore.Release()
+	for _, m := range mounts {
+		if m.Type != mountutil.Volume || m.Name == "" {
+			continue
+		}
+		if err := volStore.MountWithoutLock(m.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MountDriverVolumes asks the drivers of the volumes used by the container (other than "local") to mount them,
+// and points the mounts of the container to the paths returned by the drivers, like Docker does on start.
+// The volumes stay mounted until UnmountDriverVolumes is called when the container is stopped,
+// so the volumes of a container that has exited by itself are not mounted again.
+func MountDriverVolumes(ctx context.Context, container containerd.Container) error {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	mountsJSON, ok := lab[labels.Mounts]
+	if !ok || lab[labels.DriverVolumesMounted] == "true" {
+		return nil
+	}
+	var mounts []dockercompat.MountPoint
+	if err := json.Unmarshal([]byte(mountsJSON), &mounts); err != nil {
+		return err
+	}
+	var mounted []dockercompat.MountPoint
+	success := false
+	defer func() {
+		if !success {
+			unmountDriverVolumes(ctx, container.ID(), mounted)
+		}
+	}()
+	sources := make(map[string]string) // key: destination
+	for i := range mounts {
+		m := &mounts[i]
+		if !isDriverVolume(*m) {
+			continue
+		}
+		driver, err := volumedriver.Get(m.Driver)
+		if err == nil {
+			m.Source, err = driver.Mount(m.Name, container.ID())
+		}
+		if err != nil {
+			return fmt.Errorf("failed to mount volume %q: %w", m.Name, err)
+		}
+		mounted = append(mounted, *m)
+		sources[m.Destination] = m.Source
+	}
+	if len(mounted) == 0 {
+		return nil
+	}
+
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range spec.Mounts {
+		if src, ok := sources[spec.Mounts[i].Destination]; ok {
+			spec.Mounts[i].Source = src
+		}
+	}
+	mountsB, err := json.Marshal(mounts)
+	if err != nil {
+		return err
+	}
+	opt := containerd.WithAdditionalContainerLabels(map[string]string{
+		labels.Mounts:               string(mountsB),
+		labels.DriverVolumesMounted: "true",
+	})
+	if err := container.Update(ctx, containerd.UpdateContainerOpts(containerd.WithSpec(spec)), containerd.UpdateContainerOpts(opt)); err != nil {
+		return err
+	}
+	success = true
+	return nil
+}
+
+// UnmountDriverVolumes releases the volumes mounted by MountDriverVolumes for the container - soft failure.
+func UnmountDriverVolumes(ctx context.Context, container containerd.Container) {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to unmount the volumes of container %q", container.ID())
+		return
+	}
+	if lab[labels.DriverVolumesMounted] != "true" {
+		return
+	}
+	ReleaseDriverVolumes(ctx, container.ID(), lab)
+	opt := containerd.WithAdditionalContainerLabels(map[string]string{
+		labels.DriverVolumesMounted: "false",
+	})
+	if err := container.Update(ctx, containerd.UpdateContainerOpts(opt)); err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to update the labels of container %q", container.ID())
+	}
+}
+
+// ReleaseDriverVolumes releases the volumes mounted by MountDriverVolumes for the container id, given its labels,
+// e.g., once the container has been removed - soft failure.
+func ReleaseDriverVolumes(ctx context.Context, id string, lab map[string]string) {
+	if lab[labels.DriverVolumesMounted] != "true" {
+		return
+	}
+	var mounts []dockercompat.MountPoint
+	if err := json.Unmarshal([]byte(lab[labels.Mounts]), &mounts); err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to unmarshal the mounts of container %q", id)
+		return
+	}
+	unmountDriverVolumes(ctx, id, mounts)
+}
+
+func isDriverVolume(m dockercompat.MountPoint) bool {
+	return m.Type == mountutil.Volume && m.Driver != "" && m.Driver != volumestore.LocalDriver
+}
+
+func unmountDriverVolumes(ctx context.Context, id string, mounts []dockercompat.MountPoint) {
+	for _, m := range mounts {
+		if !isDriverVolume(m) {
+			continue
+		}
+		driver, err := volumedriver.Get(m.Driver)
+		if err == nil {
+			err = driver.Unmount(m.Name, id)
+		}
+		if err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to unmount volume %q of container %q", m.Name, id)
+		}
+	}
+}
@@ -44,9 +44,11 @@ import (
 	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/config"
 	"github.com/containerd/nerdctl/v2/pkg/consoleutil"
 	"github.com/containerd/nerdctl/v2/pkg/errutil"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/ipcutil"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/labels/k8slabels"
@@ -213,7 +215,8 @@ func GenerateSharingPIDOpts(ctx context.Context, targetCon containerd.Container)
 }
 
 // Start starts `container` with `attach` flag. If `attach` is true, it will attach to the container's stdio.
-func Start(ctx context.Context, container containerd.Container, flagA bool, flagI bool, client *containerd.Client, detachKeys string) (err error) {
+// taskOpts are passed to the creation of the task, e.g., to restore it from a checkpoint.
+func Start(ctx context.Context, container containerd.Container, flagA bool, flagI bool, client *containerd.Client, detachKeys string, cfg *config.Config, taskOpts ...containerd.NewTaskOpts) (err error) {
 	// defer the storage of start error in the dedicated label
 	defer func() {
 		if err != nil {
@@ -237,6 +240,10 @@ func Start(ctx context.Context, container containerd.Container, flagA bool, flag
 		return err
 	}
 
+	if err := ReconfigVolumes(ctx, lab, cfg); err != nil {
+		return err
+	}
+
 	process, err := container.Spec(ctx)
 	if err != nil {
 		return err
@@ -277,6 +284,9 @@ func Start(ctx context.Context, container containerd.Container, flagA bool, flag
 			log.G(ctx).WithError(err).Debug("failed to delete old task")
 		}
 	}
+	if err := MountDriverVolumes(ctx, container); err != nil {
+		return err
+	}
 	detachC := make(chan struct{})
 	attachStreamOpt := []string{}
 	if flagA {
@@ -284,7 +294,7 @@ func Start(ctx context.Context, container containerd.Container, flagA bool, flag
 		// source: https://github.com/containerd/nerdctl/blob/main/docs/command-reference.md#whale-nerdctl-start
 		attachStreamOpt = []string{"STDOUT", "STDERR"}
 	}
-	task, err := taskutil.NewTask(ctx, client, container, attachStreamOpt, flagI, flagT, true, con, logURI, detachKeys, namespace, detachC)
+	task, err := taskutil.NewTask(ctx, client, container, attachStreamOpt, flagI, flagT, true, con, logURI, detachKeys, namespace, detachC, taskOpts...)
 	if err != nil {
 		return err
 	}
@@ -292,6 +302,9 @@ func Start(ctx context.Context, container containerd.Container, flagA bool, flag
 	if err := task.Start(ctx); err != nil {
 		return err
 	}
+	if err := healthcheck.CreateTimer(ctx, container, cfg); err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to schedule health checks for container %s", container.ID())
+	}
 	if !flagA {
 		return nil
 	}
@@ -344,6 +357,16 @@ func Stop(ctx context.Context, container containerd.Container, timeout *time.Dur
 	if err := UpdateExplicitlyStoppedLabel(ctx, container, true); err != nil {
 		return err
 	}
+	// Release the volumes of volume drivers once the container is stopped
+	defer func() {
+		if err == nil {
+			UnmountDriverVolumes(ctx, container)
+		}
+	}()
+	// Stop periodic health checks - soft failure
+	if err := healthcheck.RemoveTimer(ctx, container.ID()); err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to remove healthcheck timer for container %q", container.ID())
+	}
 
 	l, err := container.Labels(ctx)
 	if err != nil {
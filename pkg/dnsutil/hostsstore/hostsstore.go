@@ -84,12 +84,15 @@ type Meta struct {
 	ExtraHosts map[string]string // host:ip
 	Name       string
 	Domainname string
+	Aliases    map[string][]string `json:",omitempty"` // network:aliases
 }
 
 type Store interface {
 	Acquire(Meta) error
 	Release(id string) error
 	Update(id, newName string) error
+	AttachNetwork(id, network string, result *types100.Result, aliases []string) error
+	DetachNetwork(id, network string) error
 	HostsPath(id string) (location string, err error)
 	Delete(id string) (err error)
 	AllocHostsFile(id string, content []byte) (location string, err error)
@@ -259,6 +262,70 @@ func (x *hostsStore) Update(id, newName string) (err error) {
 	})
 }
 
+// AttachNetwork records that the running container has been connected to network, and updates the hosts files.
+func (x *hostsStore) AttachNetwork(id, network string, result *types100.Result, aliases []string) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Join(ErrHostsStore, err)
+		}
+	}()
+
+	return x.transformMeta(id, func(meta *Meta) {
+		if meta.Networks == nil {
+			meta.Networks = make(map[string]*types100.Result)
+		}
+		meta.Networks[network] = result
+		if len(aliases) > 0 {
+			if meta.Aliases == nil {
+				meta.Aliases = make(map[string][]string)
+			}
+			meta.Aliases[network] = aliases
+		} else {
+			delete(meta.Aliases, network)
+		}
+	})
+}
+
+// DetachNetwork records that the running container has been disconnected from network, and updates the hosts files.
+func (x *hostsStore) DetachNetwork(id, network string) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Join(ErrHostsStore, err)
+		}
+	}()
+
+	return x.transformMeta(id, func(meta *Meta) {
+		delete(meta.Networks, network)
+		delete(meta.Aliases, network)
+	})
+}
+
+func (x *hostsStore) transformMeta(id string, fun func(meta *Meta)) error {
+	return x.safeStore.WithLock(func() error {
+		content, err := x.safeStore.Get(id, metaJSON)
+		if err != nil {
+			return err
+		}
+
+		meta := &Meta{}
+		if err = json.Unmarshal(content, meta); err != nil {
+			return err
+		}
+
+		fun(meta)
+		content, err = json.Marshal(meta)
+		if err != nil {
+			return err
+		}
+
+		if err = x.safeStore.Set(content, id, metaJSON); err != nil {
+			return err
+		}
+
+		return x.updateAllHosts()
+	})
+}
+
 func (x *hostsStore) updateAllHosts() (err error) {
 	entries, err := x.safeStore.List()
 	if err != nil {
@@ -27,6 +27,8 @@ import (
 // line is line "bar.example.com bar bar.nw0 foo foo.nw0\n"
 // for  `nerdctl --name=foo --hostname=bar --domainname=example.com --network=n0`.
 //
+// The aliases of the container on thatNetwork (`nerdctl network connect --alias`) are appended to the line.
+//
 // May return an empty string slice
 func createLine(thatNetwork string, meta *Meta, myNetworks map[string]struct{}) []string {
 	line := []string{}
@@ -52,5 +54,8 @@ func createLine(thatNetwork string, meta *Meta, myNetworks map[string]struct{})
 			line = append(line, baseHostname+"."+thatNetwork)
 		}
 	}
+
+	// Aliases are only resolvable on the network they were defined for
+	line = append(line, meta.Aliases[thatNetwork]...)
 	return line
 }
@@ -31,9 +31,10 @@ func TestCreateLine(t *testing.T) {
 	type testCase struct {
 		thatIP         string
 		thatNetwork    string
-		thatHostname   string // nerdctl run --hostname
-		thatDomainname string // nerdctl run --domainname
-		thatName       string // nerdctl run --name
+		thatHostname   string   // nerdctl run --hostname
+		thatDomainname string   // nerdctl run --domainname
+		thatName       string   // nerdctl run --name
+		thatAliases    []string // nerdctl network connect --alias
 		myNetwork      string
 		expected       string
 	}
@@ -114,6 +115,15 @@ func TestCreateLine(t *testing.T) {
 			myNetwork:      netutil.DefaultNetworkName,
 			expected:       "bar.example.com.example.com bar.example.com",
 		},
+		{
+			thatIP:       "10.4.2.10",
+			thatNetwork:  "n1",
+			thatHostname: "bar",
+			thatName:     "foo",
+			thatAliases:  []string{"db", "primary"},
+			myNetwork:    "n1",
+			expected:     "bar bar.n1 foo foo.n1 db primary",
+		},
 	}
 	for _, tc := range testCases {
 		thatMeta := &Meta{
@@ -136,6 +146,9 @@ func TestCreateLine(t *testing.T) {
 			Domainname: tc.thatDomainname,
 			Name:       tc.thatName,
 		}
+		if len(tc.thatAliases) > 0 {
+			thatMeta.Aliases = map[string][]string{tc.thatNetwork: tc.thatAliases}
+		}
 
 		myNetworks := map[string]struct{}{
 			tc.myNetwork: {},
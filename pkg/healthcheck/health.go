@@ -18,6 +18,8 @@ package healthcheck
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -39,6 +41,14 @@ const (
 	TestNone = ""
 )
 
+// Actions taken when a container becomes unhealthy
+const (
+	OnFailureNone    = "none"
+	OnFailureKill    = "kill"
+	OnFailureRestart = "restart"
+	OnFailureStop    = "stop"
+)
+
 const (
 	DefaultProbeInterval   = 30 * time.Second // Default interval between probe runs. Also applies before the first probe.
 	DefaultProbeTimeout    = 30 * time.Second // Max duration a single probe run may take before it's considered failed.
@@ -84,6 +94,32 @@ type HealthState struct {
 	FailingStreak int          // FailingStreak is the number of consecutive failures
 }
 
+// ValidateOnFailure checks that action is a supported --health-on-failure action.
+func ValidateOnFailure(action string) error {
+	switch action {
+	case "", OnFailureNone, OnFailureKill, OnFailureRestart, OnFailureStop:
+		return nil
+	default:
+		return fmt.Errorf("invalid health-on-failure action %q: must be one of %q, %q, %q or %q",
+			action, OnFailureNone, OnFailureKill, OnFailureRestart, OnFailureStop)
+	}
+}
+
+// ParseHealthCmd returns the test of --health-cmd, which is either a shell command, e.g., `curl -f http://localhost`,
+// or a JSON array in the exec form, e.g., `["CMD", "curl", "-f", "http://localhost"]`, which is not run by the shell.
+func ParseHealthCmd(healthCmd string) ([]string, error) {
+	var test []string
+	if strings.HasPrefix(healthCmd, "[") && json.Unmarshal([]byte(healthCmd), &test) == nil {
+		if len(test) < 2 || (test[0] != Cmd && test[0] != CmdShell) {
+			return nil, fmt.Errorf("invalid health-cmd %q: the exec form must start with %q or %q, followed by the command",
+				healthCmd, Cmd, CmdShell)
+		}
+		return test, nil
+	}
+	// not a JSON array, e.g., `[ -f /tmp/ready ]`
+	return []string{CmdShell, healthCmd}, nil
+}
+
 // ToJSONString serializes HealthState to a JSON string for label storage
 func (hs *HealthState) ToJSONString() (string, error) {
 	b, err := json.Marshal(hs)
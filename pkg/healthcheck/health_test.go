@@ -0,0 +1,67 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseHealthCmd(t *testing.T) {
+	testCases := []struct {
+		healthCmd string
+		expected  []string
+		err       string
+	}{
+		{
+			healthCmd: "curl -f http://localhost || exit 1",
+			expected:  []string{CmdShell, "curl -f http://localhost || exit 1"},
+		},
+		{
+			healthCmd: "[ -f /tmp/ready ]",
+			expected:  []string{CmdShell, "[ -f /tmp/ready ]"},
+		},
+		{
+			healthCmd: `["CMD", "cat", "/tmp/it's ready"]`,
+			expected:  []string{Cmd, "cat", "/tmp/it's ready"},
+		},
+		{
+			healthCmd: `["CMD-SHELL", "exit 0"]`,
+			expected:  []string{CmdShell, "exit 0"},
+		},
+		{
+			healthCmd: `["CMD"]`,
+			err:       "the exec form must start with",
+		},
+		{
+			healthCmd: `["curl", "-f", "http://localhost"]`,
+			err:       "the exec form must start with",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.healthCmd, func(t *testing.T) {
+			test, err := ParseHealthCmd(tc.healthCmd)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, test, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/config"
+	"github.com/containerd/nerdctl/v2/pkg/defaults"
+	"github.com/containerd/nerdctl/v2/pkg/idgen"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+)
+
+// maxStartPeriodProbes caps the number of timer triggers generated for the start period,
+// so that a tiny start interval cannot produce an unbounded systemd-run command line.
+const maxStartPeriodProbes = 256
+
+// CreateTimer sets up a transient systemd timer that runs `nerdctl container healthcheck` for the container
+// on the configured cadence. The timer is owned by systemd, so probes keep running after nerdctl exits.
+// It is a no-op when the container has no healthcheck, or when systemd is not available on the host.
+func CreateTimer(ctx context.Context, container containerd.Container, cfg *config.Config) error {
+	hc, err := readHealthcheckFromLabels(ctx, container)
+	if err != nil {
+		return err
+	}
+	if hc == nil || len(hc.Test) == 0 || hc.Test[0] == CmdNone {
+		return nil
+	}
+	if !defaults.IsSystemdAvailable() {
+		log.G(ctx).Debugf("systemd is not available, skipping healthcheck timer for container %s", container.ID())
+		return nil
+	}
+
+	// A (re)started container has to prove again that it is healthy.
+	if err := ResetHealthState(ctx, container); err != nil {
+		return err
+	}
+
+	// Clean up any timer left over from a previous run of the container.
+	if err := RemoveTimer(ctx, container.ID()); err != nil {
+		return err
+	}
+
+	selfExe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	interval := withDefault(hc.Interval, DefaultProbeInterval)
+	args := systemctlScope()
+	// The unit name is made unique, as the container may be restarted by a probe that runs in the unit of the previous timer.
+	unit := timerUnitPrefix(container.ID()) + idgen.TruncateID(idgen.GenerateID())
+	args = append(args, "--unit="+unit, "--description=nerdctl healthcheck for container "+container.ID())
+	for _, d := range activeSchedule(hc) {
+		args = append(args, "--timer-property=OnActiveSec="+systemdDuration(d))
+	}
+	args = append(args, "--on-unit-inactive="+systemdDuration(interval), "--timer-property=AccuracySec=1s")
+	if path := os.Getenv("PATH"); path != "" {
+		args = append(args, "--setenv=PATH="+path)
+	}
+	args = append(args, "--", selfExe,
+		"--namespace="+cfg.Namespace,
+		"--address="+cfg.Address,
+		"--data-root="+cfg.DataRoot,
+		"container", "healthcheck", container.ID())
+
+	log.G(ctx).Debugf("creating healthcheck timer with: systemd-run %s", strings.Join(args, " "))
+	if out, err := exec.CommandContext(ctx, "systemd-run", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create healthcheck timer for container %s: %w (output: %q)", container.ID(), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RemoveTimer stops the healthcheck timers of the container, if any.
+// A probe that is currently running is allowed to finish.
+func RemoveTimer(ctx context.Context, containerID string) error {
+	if !defaults.IsSystemdAvailable() {
+		return nil
+	}
+	pattern := timerUnitPrefix(containerID) + "*"
+	args := append(systemctlScope(), "stop", pattern+".timer")
+	if out, err := exec.CommandContext(ctx, "systemctl", args...).CombinedOutput(); err != nil {
+		// Exit code 5 means that the unit is not loaded, i.e., there is nothing to stop.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 5 {
+			return nil
+		}
+		return fmt.Errorf("failed to remove healthcheck timer for container %s: %w (output: %q)", containerID, err, strings.TrimSpace(string(out)))
+	}
+	// Transient services that failed are kept loaded by systemd until they are reset.
+	args = append(systemctlScope(), "reset-failed", pattern+".service")
+	_ = exec.CommandContext(ctx, "systemctl", args...).Run()
+	return nil
+}
+
+// activeSchedule returns the delays, relative to the timer creation, of the probes that are not driven by
+// the regular interval: one probe every StartInterval during the start period, or the first probe otherwise.
+// Subsequent probes are triggered Interval after the previous one has completed.
+func activeSchedule(hc *Healthcheck) []time.Duration {
+	interval := withDefault(hc.Interval, DefaultProbeInterval)
+	if hc.StartPeriod <= 0 {
+		return []time.Duration{interval}
+	}
+	startInterval := withDefault(hc.StartInterval, DefaultStartInterval)
+	var schedule []time.Duration
+	for d := startInterval; d <= hc.StartPeriod && len(schedule) < maxStartPeriodProbes; d += startInterval {
+		schedule = append(schedule, d)
+	}
+	if len(schedule) == 0 {
+		return []time.Duration{interval}
+	}
+	return schedule
+}
+
+// readHealthcheckFromLabels reads the healthcheck configuration from container labels
+func readHealthcheckFromLabels(ctx context.Context, container containerd.Container) (*Healthcheck, error) {
+	lbs, err := container.Labels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container labels: %w", err)
+	}
+	hcJSON, ok := lbs[labels.HealthCheck]
+	if !ok {
+		return nil, nil
+	}
+	hc, err := HealthCheckFromJSON(hcJSON)
+	if err != nil {
+		return nil, fmt.Errorf("invalid health check configuration: %w", err)
+	}
+	return hc, nil
+}
+
+func timerUnitPrefix(containerID string) string {
+	return "nerdctl-healthcheck-" + containerID + "-"
+}
+
+func systemctlScope() []string {
+	if rootlessutil.IsRootless() {
+		return []string{"--user"}
+	}
+	return nil
+}
+
+// systemdDuration formats d as a systemd time span.
+func systemdDuration(d time.Duration) string {
+	return fmt.Sprintf("%dus", d.Microseconds())
+}
+
+func withDefault(configuredValue, defaultValue time.Duration) time.Duration {
+	if configuredValue <= 0 {
+		return defaultValue
+	}
+	return configuredValue
+}
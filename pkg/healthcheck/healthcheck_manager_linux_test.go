@@ -0,0 +1,71 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestActiveSchedule(t *testing.T) {
+	tests := []struct {
+		name string
+		hc   *Healthcheck
+		want []time.Duration
+	}{
+		{
+			name: "defaults",
+			hc:   &Healthcheck{},
+			want: []time.Duration{DefaultProbeInterval},
+		},
+		{
+			name: "no start period",
+			hc:   &Healthcheck{Interval: 10 * time.Second, StartInterval: time.Second},
+			want: []time.Duration{10 * time.Second},
+		},
+		{
+			name: "start period with default start interval",
+			hc:   &Healthcheck{Interval: time.Minute, StartPeriod: 12 * time.Second},
+			want: []time.Duration{5 * time.Second, 10 * time.Second},
+		},
+		{
+			name: "start period with start interval",
+			hc:   &Healthcheck{Interval: time.Minute, StartPeriod: 3 * time.Second, StartInterval: time.Second},
+			want: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second},
+		},
+		{
+			name: "start interval longer than start period",
+			hc:   &Healthcheck{Interval: time.Minute, StartPeriod: time.Second, StartInterval: 2 * time.Second},
+			want: []time.Duration{time.Minute},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.DeepEqual(t, activeSchedule(tc.hc), tc.want)
+		})
+	}
+
+	capped := activeSchedule(&Healthcheck{StartPeriod: time.Hour, StartInterval: time.Millisecond})
+	assert.Equal(t, len(capped), maxStartPeriodProbes)
+}
+
+func TestSystemdDuration(t *testing.T) {
+	assert.Equal(t, systemdDuration(90*time.Second), "90000000us")
+	assert.Equal(t, systemdDuration(1500*time.Microsecond), "1500us")
+}
@@ -0,0 +1,38 @@
+//go:build !linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"context"
+
+	containerd "github.com/containerd/containerd/v2/client"
+
+	"github.com/containerd/nerdctl/v2/pkg/config"
+)
+
+// CreateTimer is not supported on this platform: health checks only run when
+// `nerdctl container healthcheck` is invoked.
+func CreateTimer(ctx context.Context, container containerd.Container, cfg *config.Config) error {
+	return nil
+}
+
+// RemoveTimer is a no-op on this platform.
+func RemoveTimer(ctx context.Context, containerID string) error {
+	return nil
+}
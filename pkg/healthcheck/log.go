@@ -160,6 +160,11 @@ func writeHealthStateToLabels(ctx context.Context, container containerd.Containe
 	return nil
 }
 
+// ResetHealthState sets the health state of the container back to Starting, with no failing streak.
+func ResetHealthState(ctx context.Context, container containerd.Container) error {
+	return writeHealthStateToLabels(ctx, container, &HealthState{Status: Starting})
+}
+
 // readHealthStateFromLabels reads the health state from container labels
 func readHealthStateFromLabels(ctx context.Context, container containerd.Container) (*HealthState, error) {
 	lbs, err := container.Labels(ctx)
@@ -0,0 +1,188 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ApplyChanges applies Dockerfile instructions (e.g. `ENV FOO=bar`, `CMD ["/bin/sh"]`) to an image config.
+// Supported instructions: CMD, ENTRYPOINT, ENV, EXPOSE, LABEL, STOPSIGNAL, USER, VOLUME, WORKDIR.
+func ApplyChanges(config *ocispec.ImageConfig, changes []string) error {
+	for _, change := range changes {
+		change = strings.TrimSpace(change)
+		if change == "" {
+			return fmt.Errorf("received an empty value in change flag")
+		}
+		directive, args, _ := strings.Cut(change, " ")
+		args = strings.TrimSpace(args)
+		if args == "" {
+			return fmt.Errorf("%s requires at least one argument", strings.ToUpper(directive))
+		}
+		switch strings.ToUpper(directive) {
+		case "CMD":
+			cmd, err := parseCommand(args)
+			if err != nil {
+				return err
+			}
+			config.Cmd = cmd
+		case "ENTRYPOINT":
+			entrypoint, err := parseCommand(args)
+			if err != nil {
+				return err
+			}
+			config.Entrypoint = entrypoint
+		case "ENV":
+			kvs, err := parseKeyValues(args)
+			if err != nil {
+				return fmt.Errorf("malformed ENV %q: %w", args, err)
+			}
+			for _, kv := range kvs {
+				config.Env = setEnv(config.Env, kv[0], kv[1])
+			}
+		case "LABEL":
+			kvs, err := parseKeyValues(args)
+			if err != nil {
+				return fmt.Errorf("malformed LABEL %q: %w", args, err)
+			}
+			if config.Labels == nil {
+				config.Labels = make(map[string]string)
+			}
+			for _, kv := range kvs {
+				config.Labels[kv[0]] = kv[1]
+			}
+		case "EXPOSE":
+			if config.ExposedPorts == nil {
+				config.ExposedPorts = make(map[string]struct{})
+			}
+			for _, port := range strings.Fields(args) {
+				if !strings.Contains(port, "/") {
+					port += "/tcp"
+				}
+				config.ExposedPorts[port] = struct{}{}
+			}
+		case "VOLUME":
+			volumes := strings.Fields(args)
+			if strings.HasPrefix(args, "[") {
+				if err := json.Unmarshal([]byte(args), &volumes); err != nil {
+					return fmt.Errorf("malformed json in VOLUME %q", args)
+				}
+			}
+			if config.Volumes == nil {
+				config.Volumes = make(map[string]struct{})
+			}
+			for _, v := range volumes {
+				config.Volumes[v] = struct{}{}
+			}
+		case "STOPSIGNAL":
+			config.StopSignal = args
+		case "USER":
+			config.User = args
+		case "WORKDIR":
+			config.WorkingDir = args
+		default:
+			return fmt.Errorf("unsupported change directive %q", directive)
+		}
+	}
+	return nil
+}
+
+// parseCommand parses the exec form (JSON array) or the shell form of CMD and ENTRYPOINT.
+func parseCommand(args string) ([]string, error) {
+	if strings.HasPrefix(args, "[") {
+		var cmd []string
+		if err := json.Unmarshal([]byte(args), &cmd); err != nil {
+			return nil, fmt.Errorf("malformed json in change flag value %q", args)
+		}
+		return cmd, nil
+	}
+	return []string{"/bin/sh", "-c", args}, nil
+}
+
+// parseKeyValues parses `key=value key2="value 2"`, or the legacy `key value` form.
+func parseKeyValues(args string) ([][2]string, error) {
+	fields, err := splitQuoted(args)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(fields[0], "=") {
+		key, value, _ := strings.Cut(args, " ")
+		return [][2]string{{key, strings.TrimSpace(value)}}, nil
+	}
+	kvs := make([][2]string, 0, len(fields))
+	for _, f := range fields {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", f)
+		}
+		kvs = append(kvs, [2]string{key, value})
+	}
+	return kvs, nil
+}
+
+// splitQuoted splits a string on spaces, keeping quoted strings together, and removing the quotes.
+func splitQuoted(s string) ([]string, error) {
+	var (
+		fields  []string
+		current strings.Builder
+		quote   rune
+		inField bool
+	)
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inField = true
+		case r == ' ' || r == '\t':
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			current.WriteRune(r)
+			inField = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+	return fields, nil
+}
+
+func setEnv(env []string, key, value string) []string {
+	for i, e := range env {
+		if k, _, _ := strings.Cut(e, "="); k == key {
+			env[i] = key + "=" + value
+			return env
+		}
+	}
+	return append(env, key+"="+value)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright The containerd Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestApplyChanges(t *testing.T) {
+	config := ocispec.ImageConfig{
+		Env: []string{"PATH=/bin", "FOO=old"},
+	}
+	err := ApplyChanges(&config, []string{
+		`CMD ["cat", "/file"]`,
+		"ENTRYPOINT echo hello",
+		`ENV FOO=bar BAZ="a b"`,
+		"ENV LEGACY some value",
+		"LABEL org.example=1",
+		"EXPOSE 80 53/udp",
+		`VOLUME ["/data"]`,
+		"STOPSIGNAL SIGINT",
+		"USER nobody",
+		"WORKDIR /tmp",
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, config.Cmd, []string{"cat", "/file"})
+	assert.DeepEqual(t, config.Entrypoint, []string{"/bin/sh", "-c", "echo hello"})
+	assert.DeepEqual(t, config.Env, []string{"PATH=/bin", "FOO=bar", "BAZ=a b", "LEGACY=some value"})
+	assert.DeepEqual(t, config.Labels, map[string]string{"org.example": "1"})
+	assert.DeepEqual(t, config.ExposedPorts, map[string]struct{}{"80/tcp": {}, "53/udp": {}})
+	assert.DeepEqual(t, config.Volumes, map[string]struct{}{"/data": {}})
+	assert.Equal(t, config.StopSignal, "SIGINT")
+	assert.Equal(t, config.User, "nobody")
+	assert.Equal(t, config.WorkingDir, "/tmp")
+}
+
+func TestApplyChangesErrors(t *testing.T) {
+	for _, change := range []string{
+		"",
+		"CMD",
+		"RUN echo hello",
+		`CMD ["unterminated"`,
+		`ENV FOO="bar`,
+		"LABEL =value",
+	} {
+		config := ocispec.ImageConfig{}
+		assert.Assert(t, ApplyChanges(&config, []string{change}) != nil, "expected an error for %q", change)
+	}
+}
@@ -0,0 +1,221 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package importer creates images from tarballs of root filesystems (`nerdctl import`).
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/identity"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/leases"
+	"github.com/containerd/containerd/v2/pkg/archive/compression"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/platforms"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// Import creates a single-layer image from the tarball of a root filesystem (optionally compressed),
+// stores it in the content store and unpacks it.
+// It returns the digest of the image config, i.e. the image ID.
+func Import(ctx context.Context, client *containerd.Client, r io.Reader, options types.ImageImportOptions) (digest.Digest, error) {
+	platform := platforms.DefaultSpec()
+	if options.Platform != "" {
+		var err error
+		if platform, err = platforms.Parse(options.Platform); err != nil {
+			return "", err
+		}
+	}
+	var name string
+	if options.Reference != "" {
+		parsedReference, err := referenceutil.Parse(options.Reference)
+		if err != nil {
+			return "", err
+		}
+		name = parsedReference.String()
+	}
+
+	config := ocispec.ImageConfig{}
+	if err := ApplyChanges(&config, options.Change); err != nil {
+		return "", err
+	}
+
+	// Don't gc the content before the image is created
+	ctx, done, err := client.WithLease(ctx, leases.WithRandomID(), leases.WithExpiration(1*time.Hour))
+	if err != nil {
+		return "", fmt.Errorf("failed to create lease for import: %w", err)
+	}
+	defer done(ctx)
+
+	cs := client.ContentStore()
+	layerDesc, diffID, err := writeLayer(ctx, cs, r)
+	if err != nil {
+		return "", err
+	}
+
+	created := time.Now()
+	comment := options.Message
+	if comment == "" {
+		comment = "Imported from " + options.Source
+	}
+	imgConfig := ocispec.Image{
+		Created:  &created,
+		Platform: platform,
+		Config:   config,
+		RootFS: ocispec.RootFS{
+			Type:    "layers",
+			DiffIDs: []digest.Digest{diffID},
+		},
+		History: []ocispec.History{
+			{
+				Created: &created,
+				Comment: comment,
+			},
+		},
+	}
+	configDesc, err := writeJSON(ctx, cs, ocispec.MediaTypeImageConfig, imgConfig, map[string]string{
+		fmt.Sprintf("containerd.io/gc.ref.snapshot.%s", options.GOptions.Snapshotter): identity.ChainID(imgConfig.RootFS.DiffIDs).String(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	manifest := ocispec.Manifest{
+		Versioned: specs.Versioned{
+			SchemaVersion: 2,
+		},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    configDesc,
+		Layers:    []ocispec.Descriptor{layerDesc},
+	}
+	// The manifest references the config and the layer
+	manifestDesc, err := writeJSON(ctx, cs, ocispec.MediaTypeImageManifest, manifest, map[string]string{
+		"containerd.io/gc.ref.content.config": configDesc.Digest.String(),
+		"containerd.io/gc.ref.content.l.0":    layerDesc.Digest.String(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		// Like images created by the cri plugin, images without a name are named after their digest
+		name = manifestDesc.Digest.String()
+	}
+	img := images.Image{
+		Name:      name,
+		Target:    manifestDesc,
+		CreatedAt: created,
+	}
+	if _, err := client.ImageService().Update(ctx, img); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return "", err
+		}
+		if _, err := client.ImageService().Create(ctx, img); err != nil {
+			return "", fmt.Errorf("failed to create new image %s: %w", name, err)
+		}
+	}
+
+	cimg := containerd.NewImageWithPlatform(client, img, platforms.Only(platform))
+	if err := cimg.Unpack(ctx, options.GOptions.Snapshotter); err != nil {
+		return "", err
+	}
+	return configDesc.Digest, nil
+}
+
+// writeLayer writes the tarball r as a gzip-compressed layer into the content store,
+// and returns the descriptor of the layer and its diffID (the digest of the uncompressed tarball).
+func writeLayer(ctx context.Context, cs content.Store, r io.Reader) (ocispec.Descriptor, digest.Digest, error) {
+	decompressed, err := compression.DecompressStream(r)
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	defer decompressed.Close()
+
+	ref := fmt.Sprintf("import-%d", time.Now().UnixNano())
+	w, err := content.OpenWriter(ctx, cs, content.WithRef(ref))
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	defer w.Close()
+	// In case of a previous failed import with the same ref
+	if err := w.Truncate(0); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+
+	diffIDDigester := digest.Canonical.Digester()
+	counter := &countingWriter{w: w}
+	gz := gzip.NewWriter(counter)
+	if _, err := io.Copy(io.MultiWriter(gz, diffIDDigester.Hash()), decompressed); err != nil {
+		return ocispec.Descriptor{}, "", fmt.Errorf("failed to read the tarball: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	diffID := diffIDDigester.Digest()
+	labels := map[string]string{
+		"containerd.io/uncompressed": diffID.String(),
+	}
+	if err := w.Commit(ctx, counter.n, "", content.WithLabels(labels)); err != nil && !errdefs.IsAlreadyExists(err) {
+		return ocispec.Descriptor{}, "", err
+	}
+	return ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayerGzip,
+		Digest:    w.Digest(),
+		Size:      counter.n,
+	}, diffID, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+func writeJSON(ctx context.Context, cs content.Store, mediaType string, x any, labels map[string]string) (ocispec.Descriptor, error) {
+	b, err := json.Marshal(x)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(b),
+		Size:      int64(len(b)),
+	}
+	if err := content.WriteBlob(ctx, cs, desc.Digest.String(), bytes.NewReader(b), desc, content.WithLabels(labels)); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return desc, nil
+}
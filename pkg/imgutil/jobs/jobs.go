@@ -38,15 +38,22 @@ import (
 // by checking status in the content store.
 //
 // From https://github.com/containerd/containerd/blob/v1.7.0-rc.2/cmd/ctr/commands/content/fetch.go#L219-L336
+//
+// When out is a Writer of MultiProgress, the progress is displayed along with the progress of the other jobs of MultiProgress.
 func ShowProgress(ctx context.Context, ongoing *Jobs, cs content.Store, out io.Writer) {
 	var (
 		ticker   = time.NewTicker(100 * time.Millisecond)
-		fw       = progress.NewWriter(out)
+		fw       flushWriter
 		start    = time.Now()
 		statuses = map[string]StatusInfo{}
 		done     bool
 	)
 	defer ticker.Stop()
+	if w, ok := out.(*multiProgressWriter); ok {
+		fw = w
+	} else {
+		fw = progress.NewWriter(out)
+	}
 
 outer:
 	for {
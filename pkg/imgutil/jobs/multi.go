@@ -0,0 +1,113 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/v2/pkg/progress"
+)
+
+// flushWriter is implemented by *progress.Writer and by the writers of MultiProgress.
+type flushWriter interface {
+	io.Writer
+	Flush() error
+}
+
+// MultiProgress displays the progress of concurrent jobs, e.g., concurrent pulls, in a single block
+// of lines, so that their progress is not interleaved.
+// Each job writes its progress with ShowProgress to its own Writer.
+type MultiProgress struct {
+	mu     sync.Mutex
+	fw     *progress.Writer
+	names  []string
+	frames map[string][]byte
+}
+
+// NewMultiProgress returns a MultiProgress that displays the progress of its jobs to out.
+func NewMultiProgress(out io.Writer) *MultiProgress {
+	return &MultiProgress{
+		fw:     progress.NewWriter(out),
+		frames: map[string][]byte{},
+	}
+}
+
+// Writer returns the writer for the progress of the job name, to be passed to ShowProgress.
+func (m *MultiProgress) Writer(name string) io.Writer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.frames[name]; !ok {
+		m.names = append(m.names, name)
+		m.frames[name] = nil
+	}
+	return &multiProgressWriter{m: m, name: name}
+}
+
+// Run displays the progress of the jobs until ctx is done.
+func (m *MultiProgress) Run(ctx context.Context) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.Flush()
+		case <-ctx.Done():
+			// display the final progress of the jobs once more
+			m.Flush()
+			return
+		}
+	}
+}
+
+// Flush displays the last progress of each job, in the order of their Writer calls.
+func (m *MultiProgress) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, name := range m.names {
+		if _, err := m.fw.Write(m.frames[name]); err != nil {
+			return err
+		}
+	}
+	return m.fw.Flush()
+}
+
+// multiProgressWriter buffers the progress of a job until Flush.
+type multiProgressWriter struct {
+	m    *MultiProgress
+	name string
+	buf  bytes.Buffer
+}
+
+func (w *multiProgressWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+// Flush replaces the last progress of the job.
+func (w *multiProgressWriter) Flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	w.m.mu.Lock()
+	defer w.m.mu.Unlock()
+	w.m.frames[w.name] = bytes.Clone(w.buf.Bytes())
+	w.buf.Reset()
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package jobs
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestMultiProgress(t *testing.T) {
+	var out bytes.Buffer
+	m := NewMultiProgress(&out)
+	foo := m.Writer("foo").(flushWriter)
+	bar := m.Writer("bar").(flushWriter)
+
+	fmt.Fprintln(bar, "bar: resolving")
+	assert.NilError(t, bar.Flush())
+	fmt.Fprintln(foo, "foo: resolving")
+	// not flushed yet
+	assert.NilError(t, m.Flush())
+	assert.Equal(t, out.String(), "bar: resolving\n")
+
+	out.Reset()
+	assert.NilError(t, foo.Flush())
+	fmt.Fprintln(bar, "bar: done")
+	assert.NilError(t, bar.Flush())
+	assert.NilError(t, m.Flush())
+	// the jobs are displayed in the order of their writers, with their last progress only
+	assert.Equal(t, out.String(), "foo: resolving\nbar: done\n")
+}
@@ -49,6 +49,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
 	"github.com/containerd/nerdctl/v2/pkg/ipcutil"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
 	"github.com/containerd/nerdctl/v2/pkg/ocihook/state"
 )
 
@@ -455,19 +456,23 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 		cs.Paused = n.Process.Status.Status == containerd.Paused
 		cs.Pid = n.Process.Pid
 		cs.ExitCode = int(n.Process.Status.ExitStatus)
+		var stateInterfaces map[string]string
 		if containerAnnotations[labels.StateDir] != "" {
 			if lf, err := state.New(containerAnnotations[labels.StateDir]); err != nil {
 				log.L.WithError(err).Errorf("failed retrieving state")
 			} else if err = lf.Load(); err != nil {
 				log.L.WithError(err).Errorf("failed retrieving StartedAt from state")
-			} else if !time.Time.IsZero(lf.StartedAt) {
-				cs.StartedAt = lf.StartedAt.UTC().Format(time.RFC3339Nano)
+			} else {
+				if !time.Time.IsZero(lf.StartedAt) {
+					cs.StartedAt = lf.StartedAt.UTC().Format(time.RFC3339Nano)
+				}
+				stateInterfaces = lf.Interfaces
 			}
 		}
 		if !n.Process.Status.ExitTime.IsZero() {
 			cs.FinishedAt = n.Process.Status.ExitTime.Format(time.RFC3339Nano)
 		}
-		nSettings, err := networkSettingsFromNative(n.Process.NetNS, n.Spec.(*specs.Spec))
+		nSettings, err := networkSettingsFromNative(n.Process.NetNS, n.Spec.(*specs.Spec), interfaceNetworks(containerAnnotations, stateInterfaces))
 		if err != nil {
 			return nil, err
 		}
@@ -476,7 +481,7 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 	} else {
 		// n.process is not set if the container is not started, making the networkSetting null
 		// we should send an empty object even in this case inorder for it to be compatible with docker inspect response
-		nSettings, err := networkSettingsFromNative(nil, n.Spec.(*specs.Spec))
+		nSettings, err := networkSettingsFromNative(nil, n.Spec.(*specs.Spec), nil)
 		if err != nil {
 			return nil, err
 		}
@@ -581,6 +586,17 @@ func ContainerFromNative(n *native.Container) (*Container, error) {
 		c.Config.User = n.Labels[labels.User]
 	}
 
+	if exposedPortsJSON := n.Labels[labels.ExposedPorts]; exposedPortsJSON != "" {
+		var exposedPorts []string
+		if err := json.Unmarshal([]byte(exposedPortsJSON), &exposedPorts); err != nil {
+			return nil, fmt.Errorf("failed to parse label %q: %w", labels.ExposedPorts, err)
+		}
+		c.Config.ExposedPorts = make(nat.PortSet)
+		for _, p := range exposedPorts {
+			c.Config.ExposedPorts[nat.Port(p)] = struct{}{}
+		}
+	}
+
 	// Add health check config if present in labels
 	if hConfig, ok := n.Labels[labels.HealthCheck]; ok && hConfig != "" {
 		healthCheckConfig, err := healthcheck.HealthCheckFromJSON(hConfig)
@@ -694,7 +710,33 @@ func statusFromNative(x containerd.Status, labels map[string]string) string {
 	}
 }
 
-func networkSettingsFromNative(n *native.NetNS, sp *specs.Spec) (*NetworkSettings, error) {
+// interfaceNetworks returns the names of the CNI networks of a running container, keyed by interface name.
+// stateInterfaces is the network:interface map recorded by `nerdctl network connect|disconnect`, if any.
+// Otherwise, the interfaces are named after the index of the networks ("eth<i>"), as created by the OCI hook.
+func interfaceNetworks(annotations map[string]string, stateInterfaces map[string]string) map[string]string {
+	res := make(map[string]string)
+	if stateInterfaces != nil {
+		for netName, ifName := range stateInterfaces {
+			res[ifName] = netName
+		}
+		return res
+	}
+	var networks []string
+	if err := json.Unmarshal([]byte(annotations[labels.Networks]), &networks); err != nil {
+		return res
+	}
+	if netType, err := nettype.Detect(networks); err != nil || netType != nettype.CNI {
+		return res
+	}
+	for i, netName := range networks {
+		res[fmt.Sprintf("eth%d", i)] = netName
+	}
+	return res
+}
+
+// networkSettingsFromNative converts the interfaces of the network namespace to Docker-compatible network settings.
+// ifNetworks maps interface names to network names; interfaces that are not found there are reported as "unknown-<interface>".
+func networkSettingsFromNative(n *native.NetNS, sp *specs.Spec, ifNetworks map[string]string) (*NetworkSettings, error) {
 	res := &NetworkSettings{
 		Networks: make(map[string]*NetworkEndpointSettings),
 	}
@@ -733,9 +775,12 @@ func networkSettingsFromNative(n *native.NetNS, sp *specs.Spec) (*NetworkSetting
 				nes.GlobalIPv6PrefixLen = ones
 			}
 		}
-		// TODO: set CNI name when possible
-		fakeDockerNetworkName := fmt.Sprintf("unknown-%s", x.Name)
-		res.Networks[fakeDockerNetworkName] = nes
+		if netName, ok := ifNetworks[x.Name]; ok {
+			res.Networks[netName] = nes
+		} else {
+			fakeDockerNetworkName := fmt.Sprintf("unknown-%s", x.Name)
+			res.Networks[fakeDockerNetworkName] = nes
+		}
 
 		if portsLabel, ok := sp.Annotations[labels.Ports]; ok {
 			var ports []cni.PortMapping
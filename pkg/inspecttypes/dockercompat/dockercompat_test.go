@@ -69,10 +69,11 @@ func TestContainerFromNative(t *testing.T) {
 			n: &native.Container{
 				Container: containers.Container{
 					Labels: map[string]string{
-						"nerdctl/mounts":    "[{\"Type\":\"bind\",\"Source\":\"/mnt/foo\",\"Destination\":\"/mnt/foo\",\"Mode\":\"rshared,rw\",\"RW\":true,\"Propagation\":\"rshared\"}]",
-						"nerdctl/state-dir": tempStateDir,
-						"nerdctl/hostname":  "host1",
-						"nerdctl/user":      "test-user",
+						"nerdctl/mounts":        "[{\"Type\":\"bind\",\"Source\":\"/mnt/foo\",\"Destination\":\"/mnt/foo\",\"Mode\":\"rshared,rw\",\"RW\":true,\"Propagation\":\"rshared\"}]",
+						"nerdctl/state-dir":     tempStateDir,
+						"nerdctl/hostname":      "host1",
+						"nerdctl/user":          "test-user",
+						"nerdctl/exposed-ports": "[\"80/tcp\"]",
 					},
 				},
 				Spec: &specs.Spec{
@@ -120,14 +121,16 @@ func TestContainerFromNative(t *testing.T) {
 				},
 				Config: &Config{
 					Labels: map[string]string{
-						"nerdctl/mounts":    "[{\"Type\":\"bind\",\"Source\":\"/mnt/foo\",\"Destination\":\"/mnt/foo\",\"Mode\":\"rshared,rw\",\"RW\":true,\"Propagation\":\"rshared\"}]",
-						"nerdctl/state-dir": tempStateDir,
-						"nerdctl/hostname":  "host1",
-						"nerdctl/user":      "test-user",
+						"nerdctl/mounts":        "[{\"Type\":\"bind\",\"Source\":\"/mnt/foo\",\"Destination\":\"/mnt/foo\",\"Mode\":\"rshared,rw\",\"RW\":true,\"Propagation\":\"rshared\"}]",
+						"nerdctl/state-dir":     tempStateDir,
+						"nerdctl/hostname":      "host1",
+						"nerdctl/user":          "test-user",
+						"nerdctl/exposed-ports": "[\"80/tcp\"]",
 					},
-					Hostname: "host1",
-					Env:      []string{"/some/path"},
-					User:     "test-user",
+					Hostname:     "host1",
+					Env:          []string{"/some/path"},
+					User:         "test-user",
+					ExposedPorts: nat.PortSet{"80/tcp": {}},
 				},
 				NetworkSettings: &NetworkSettings{
 					Ports:    &nat.PortMap{},
@@ -379,10 +382,11 @@ func TestNetworkSettingsFromNative(t *testing.T) {
 	defer os.RemoveAll(tempStateDir)
 
 	testcase := []struct {
-		name     string
-		n        *native.NetNS
-		s        *specs.Spec
-		expected *NetworkSettings
+		name       string
+		n          *native.NetNS
+		s          *specs.Spec
+		ifNetworks map[string]string
+		expected   *NetworkSettings
 	}{
 		// Given null native.NetNS, Return initialized NetworkSettings
 		//    UseCase: Inspect a Stopped Container
@@ -470,11 +474,61 @@ func TestNetworkSettingsFromNative(t *testing.T) {
 				},
 			},
 		},
+		// Given native.NetNS with interfaces of known networks, Return NetworkSettings keyed by network name
+		//   UseCase: Inspect a Running Container connected to several networks
+		{
+			name: "Given NetNS with interfaces of known networks, Return NetworkSettings keyed by network name",
+			n: &native.NetNS{
+				Interfaces: []native.NetInterface{
+					{
+						Interface: net.Interface{
+							Index: 1,
+							MTU:   1500,
+							Name:  "eth0",
+							Flags: net.FlagUp,
+						},
+						HardwareAddr: "xx:xx:xx:xx:xx:xx",
+						Flags:        []string{},
+						Addrs:        []string{"10.4.0.30/24"},
+					},
+					{
+						Interface: net.Interface{
+							Index: 2,
+							MTU:   1500,
+							Name:  "eth1",
+							Flags: net.FlagUp,
+						},
+						HardwareAddr: "yy:yy:yy:yy:yy:yy",
+						Flags:        []string{},
+						Addrs:        []string{"10.5.0.30/24"},
+					},
+				},
+			},
+			s: &specs.Spec{
+				Annotations: map[string]string{},
+			},
+			ifNetworks: map[string]string{"eth0": "bridge", "eth1": "blue"},
+			expected: &NetworkSettings{
+				Ports: &nat.PortMap{},
+				Networks: map[string]*NetworkEndpointSettings{
+					"bridge": {
+						IPAddress:   "10.4.0.30",
+						IPPrefixLen: 24,
+						MacAddress:  "xx:xx:xx:xx:xx:xx",
+					},
+					"blue": {
+						IPAddress:   "10.5.0.30",
+						IPPrefixLen: 24,
+						MacAddress:  "yy:yy:yy:yy:yy:yy",
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testcase {
 		t.Run(tc.name, func(tt *testing.T) {
-			d, _ := networkSettingsFromNative(tc.n, tc.s)
+			d, _ := networkSettingsFromNative(tc.n, tc.s, tc.ifNetworks)
 			assert.DeepEqual(tt, d, tc.expected)
 		})
 	}
@@ -19,7 +19,9 @@ package native
 // Volume is also compatible with Docker
 type Volume struct {
 	Name       string             `json:"Name"`
+	Driver     string             `json:"Driver,omitempty"`
 	Mountpoint string             `json:"Mountpoint"`
 	Labels     *map[string]string `json:"Labels,omitempty"`
+	Options    map[string]string  `json:"Options,omitempty"`
 	Size       int64              `json:"Size,omitempty"`
 }
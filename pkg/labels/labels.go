@@ -35,6 +35,12 @@ const (
 	//Compose Service Name
 	ComposeService = "com.docker.compose.service"
 
+	// ComposeConfigFiles is the comma-separated list of the compose files of the project
+	ComposeConfigFiles = "com.docker.compose.project.config_files"
+
+	// ComposeWorkingDir is the working directory of the project
+	ComposeWorkingDir = "com.docker.compose.project.working_dir"
+
 	//Compose Network Name
 	ComposeNetwork = "com.docker.compose.network"
 
@@ -57,9 +63,15 @@ const (
 	// Currently, the length of the slice must be 1.
 	Networks = Prefix + "networks"
 
+	// NetworkAliases is a JSON-marshalled string of map[string][]string, the aliases of the container on each network.
+	NetworkAliases = Prefix + "network-aliases"
+
 	// Ports is a JSON-marshalled string of []cni.PortMapping .
 	Ports = Prefix + "ports"
 
+	// ExposedPorts is a JSON-marshalled string of []string, the exposed ports of the container (e.g., "80/tcp").
+	ExposedPorts = Prefix + "exposed-ports"
+
 	// IPAddress is the static IP address of the container assigned by the user
 	IPAddress = Prefix + "ip"
 
@@ -82,6 +94,10 @@ const (
 	// Mounts is the mount points for the container.
 	Mounts = Prefix + "mounts"
 
+	// DriverVolumesMounted is "true" while the volumes of volume drivers (other than "local") used by the container
+	// are mounted by their drivers, i.e., from the start of the container until it is stopped or removed.
+	DriverVolumesMounted = Prefix + "driver-volumes-mounted"
+
 	// StopTimeout is seconds to wait for stop a container.
 	StopTimeout = Prefix + "stop-timeout"
 
@@ -124,4 +140,16 @@ const (
 
 	// HealthState stores the current health state (status and failing streak).
 	HealthState = Prefix + "healthstate"
+
+	// HealthOnFailure stores the action to take when the container becomes unhealthy (kill, restart, or stop).
+	HealthOnFailure = Prefix + "health-on-failure"
+
+	// CheckpointContainer is set on checkpoint images, the ID of the checkpointed container.
+	CheckpointContainer = Prefix + "checkpoint.container"
+
+	// CheckpointName is set on checkpoint images, the name given to the checkpoint by the user.
+	CheckpointName = Prefix + "checkpoint.name"
+
+	// ManifestList is set on the image indexes created by `nerdctl manifest create`.
+	ManifestList = Prefix + "manifest-list"
 )
@@ -0,0 +1,335 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package gelf implements the Graylog Extended Log Format (GELF) 1.1 over UDP and TCP,
+// as used by the "gelf" log driver.
+//
+// UDP messages are compressed and split into chunks when they do not fit into a single datagram.
+// TCP messages are never compressed, and each of them is terminated by a null byte.
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+)
+
+// Compression is the compression of the UDP messages.
+type Compression string
+
+const (
+	CompressGzip Compression = "gzip"
+	CompressZlib Compression = "zlib"
+	CompressNone Compression = "none"
+)
+
+const (
+	// Version is the version of GELF.
+	Version = "1.1"
+
+	// Syslog severity levels used for the messages of stdout and stderr.
+	LevelError = 3
+	LevelInfo  = 6
+
+	// ChunkSize is the maximum size of a UDP datagram, including the chunk header, as in Docker.
+	ChunkSize = 1420
+	// MaxChunks is the maximum number of chunks of a message, defined by the GELF specification.
+	MaxChunks = 128
+
+	chunkHeaderLen = 12
+)
+
+// ChunkMagic starts every chunk of a chunked UDP message.
+var ChunkMagic = []byte{0x1e, 0x0f}
+
+// Message is a GELF message.
+type Message struct {
+	Version      string
+	Host         string
+	ShortMessage string
+	// Timestamp is the number of seconds since the UNIX epoch, with an optional decimal part.
+	Timestamp float64
+	Level     int
+	// Extra contains the additional fields. The names are written with the leading underscore
+	// required by GELF.
+	Extra map[string]string
+}
+
+func (m *Message) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]any, len(m.Extra)+5)
+	for k, v := range m.Extra {
+		fields["_"+k] = v
+	}
+	fields["version"] = m.Version
+	fields["host"] = m.Host
+	fields["short_message"] = m.ShortMessage
+	fields["timestamp"] = m.Timestamp
+	fields["level"] = m.Level
+	return json.Marshal(fields)
+}
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*m = Message{Extra: make(map[string]string)}
+	for k, v := range fields {
+		switch k {
+		case "version":
+			m.Version, _ = v.(string)
+		case "host":
+			m.Host, _ = v.(string)
+		case "short_message":
+			m.ShortMessage, _ = v.(string)
+		case "timestamp":
+			m.Timestamp, _ = v.(float64)
+		case "level":
+			level, _ := v.(float64)
+			m.Level = int(level)
+		default:
+			if len(k) > 1 && k[0] == '_' {
+				m.Extra[k[1:]] = fmt.Sprint(v)
+			}
+		}
+	}
+	return nil
+}
+
+// Writer sends GELF messages to a server.
+type Writer interface {
+	WriteMessage(m *Message) error
+	Close() error
+}
+
+// ParseAddress parses an address of the form "udp://host:port" or "tcp://host:port".
+func ParseAddress(address string) (network, hostport string, err error) {
+	if address == "" {
+		return "", "", errors.New("gelf: the address is required")
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Scheme != "udp" && u.Scheme != "tcp" {
+		return "", "", fmt.Errorf("gelf: unsupported protocol %q, must be udp or tcp", u.Scheme)
+	}
+	if _, _, err := net.SplitHostPort(u.Host); err != nil {
+		return "", "", fmt.Errorf("gelf: please provide the address as proto://host:port: %w", err)
+	}
+	return u.Scheme, u.Host, nil
+}
+
+// ParseCompression parses the compression of the UDP messages. Defaults to gzip, as in Docker.
+func ParseCompression(s string) (Compression, error) {
+	switch c := Compression(s); c {
+	case "":
+		return CompressGzip, nil
+	case CompressGzip, CompressZlib, CompressNone:
+		return c, nil
+	default:
+		return "", fmt.Errorf("gelf: unknown compression type %q, must be gzip, zlib or none", s)
+	}
+}
+
+// NewWriter returns a writer for the address, that must have been validated by ParseAddress.
+// The compression only applies to UDP.
+func NewWriter(address string, compression Compression) (Writer, error) {
+	network, hostport, err := ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if network == "tcp" {
+		return NewTCPWriter(hostport)
+	}
+	return NewUDPWriter(hostport, compression)
+}
+
+// UDPWriter sends messages in UDP datagrams.
+type UDPWriter struct {
+	conn        net.Conn
+	compression Compression
+}
+
+func NewUDPWriter(hostport string, compression Compression) (*UDPWriter, error) {
+	conn, err := net.Dial("udp", hostport)
+	if err != nil {
+		return nil, err
+	}
+	return &UDPWriter{conn: conn, compression: compression}, nil
+}
+
+func (w *UDPWriter) WriteMessage(m *Message) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if b, err = compress(b, w.compression); err != nil {
+		return err
+	}
+	if len(b) <= ChunkSize {
+		_, err = w.conn.Write(b)
+		return err
+	}
+	chunks, err := Chunk(b)
+	if err != nil {
+		return err
+	}
+	for _, c := range chunks {
+		if _, err := w.conn.Write(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *UDPWriter) Close() error {
+	return w.conn.Close()
+}
+
+// Chunk splits a UDP message into chunks of at most ChunkSize bytes.
+func Chunk(b []byte) ([][]byte, error) {
+	dataLen := ChunkSize - chunkHeaderLen
+	count := (len(b) + dataLen - 1) / dataLen
+	if count > MaxChunks {
+		return nil, fmt.Errorf("gelf: message too large (%d bytes, %d chunks)", len(b), count)
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	chunks := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		end := min((i+1)*dataLen, len(b))
+		c := make([]byte, 0, chunkHeaderLen+end-i*dataLen)
+		c = append(c, ChunkMagic...)
+		c = append(c, id...)
+		c = append(c, byte(i), byte(count))
+		c = append(c, b[i*dataLen:end]...)
+		chunks = append(chunks, c)
+	}
+	return chunks, nil
+}
+
+// Decode decodes a UDP message that is not chunked, or a TCP message without its null byte.
+// The compression is detected from the magic bytes.
+func Decode(b []byte) (*Message, error) {
+	var (
+		zr  io.ReadCloser
+		err error
+	)
+	switch {
+	case len(b) >= 2 && b[0] == 0x1f && b[1] == 0x8b:
+		zr, err = gzip.NewReader(bytes.NewReader(b))
+	case len(b) >= 2 && b[0] == 0x78:
+		zr, err = zlib.NewReader(bytes.NewReader(b))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if zr != nil {
+		defer zr.Close()
+		if b, err = io.ReadAll(zr); err != nil {
+			return nil, err
+		}
+	}
+	var m Message
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func compress(b []byte, compression Compression) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		zw  io.WriteCloser
+	)
+	switch compression {
+	case CompressGzip, "":
+		zw = gzip.NewWriter(&buf)
+	case CompressZlib:
+		zw = zlib.NewWriter(&buf)
+	default:
+		return b, nil
+	}
+	if _, err := zw.Write(b); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// TCPWriter sends null-delimited messages over a TCP connection, that is
+// re-established once when a write fails.
+type TCPWriter struct {
+	mu       sync.Mutex
+	hostport string
+	conn     net.Conn
+}
+
+func NewTCPWriter(hostport string) (*TCPWriter, error) {
+	conn, err := net.Dial("tcp", hostport)
+	if err != nil {
+		return nil, err
+	}
+	return &TCPWriter{hostport: hostport, conn: conn}, nil
+}
+
+func (w *TCPWriter) WriteMessage(m *Message) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	b = append(b, 0)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		if _, err = w.conn.Write(b); err == nil {
+			return nil
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+	conn, dialErr := net.Dial("tcp", w.hostport)
+	if dialErr != nil {
+		return errors.Join(err, dialErr)
+	}
+	w.conn = conn
+	_, err = w.conn.Write(b)
+	return err
+}
+
+func (w *TCPWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,159 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package gelf
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func testMessage(shortMessage string) *Message {
+	return &Message{
+		Version:      Version,
+		Host:         "host",
+		ShortMessage: shortMessage,
+		Timestamp:    1700000000.123,
+		Level:        LevelInfo,
+		Extra:        map[string]string{"container_id": "abc", "foo": "bar"},
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	network, hostport, err := ParseAddress("udp://127.0.0.1:12201")
+	assert.NilError(t, err)
+	assert.Equal(t, network, "udp")
+	assert.Equal(t, hostport, "127.0.0.1:12201")
+
+	network, hostport, err = ParseAddress("tcp://graylog:12201")
+	assert.NilError(t, err)
+	assert.Equal(t, network, "tcp")
+	assert.Equal(t, hostport, "graylog:12201")
+
+	_, _, err = ParseAddress("")
+	assert.ErrorContains(t, err, "required")
+	_, _, err = ParseAddress("http://127.0.0.1:12201")
+	assert.ErrorContains(t, err, "unsupported protocol")
+	_, _, err = ParseAddress("udp://127.0.0.1")
+	assert.ErrorContains(t, err, "proto://host:port")
+}
+
+func TestMessageJSON(t *testing.T) {
+	m := testMessage("hello")
+	b, err := m.MarshalJSON()
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(b), `"_container_id":"abc"`), string(b))
+	assert.Assert(t, strings.Contains(string(b), `"short_message":"hello"`), string(b))
+
+	var decoded Message
+	assert.NilError(t, decoded.UnmarshalJSON(b))
+	assert.DeepEqual(t, &decoded, m)
+}
+
+func TestUDPWriter(t *testing.T) {
+	for _, compression := range []Compression{CompressGzip, CompressZlib, CompressNone} {
+		t.Run(string(compression), func(t *testing.T) {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			assert.NilError(t, err)
+			defer conn.Close()
+
+			w, err := NewUDPWriter(conn.LocalAddr().String(), compression)
+			assert.NilError(t, err)
+			defer w.Close()
+			m := testMessage("hello")
+			assert.NilError(t, w.WriteMessage(m))
+
+			buf := make([]byte, ChunkSize)
+			n, _, err := conn.ReadFrom(buf)
+			assert.NilError(t, err)
+			if compression == CompressNone {
+				assert.Equal(t, buf[0], byte('{'))
+			}
+			decoded, err := Decode(buf[:n])
+			assert.NilError(t, err)
+			assert.DeepEqual(t, decoded, m)
+		})
+	}
+}
+
+func TestUDPWriterChunked(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	w, err := NewUDPWriter(conn.LocalAddr().String(), CompressNone)
+	assert.NilError(t, err)
+	defer w.Close()
+	m := testMessage(strings.Repeat("a", 3*ChunkSize))
+	assert.NilError(t, w.WriteMessage(m))
+
+	var (
+		data  []byte
+		id    []byte
+		count = -1
+	)
+	for seq := 0; seq != count; seq++ {
+		buf := make([]byte, ChunkSize)
+		n, _, err := conn.ReadFrom(buf)
+		assert.NilError(t, err)
+		assert.Assert(t, bytes.HasPrefix(buf, ChunkMagic))
+		if id == nil {
+			id = buf[2:10]
+			count = int(buf[11])
+		}
+		assert.DeepEqual(t, buf[2:10], id)
+		assert.Equal(t, int(buf[10]), seq)
+		data = append(data, buf[chunkHeaderLen:n]...)
+	}
+	assert.Equal(t, count, 4)
+	decoded, err := Decode(data)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, decoded, m)
+
+	_, err = Chunk(make([]byte, MaxChunks*ChunkSize))
+	assert.ErrorContains(t, err, "too large")
+}
+
+func TestTCPWriter(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer l.Close()
+
+	w, err := NewTCPWriter(l.Addr().String())
+	assert.NilError(t, err)
+	defer w.Close()
+	conn, err := l.Accept()
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	messages := []*Message{testMessage("foo"), testMessage("bar")}
+	for _, m := range messages {
+		assert.NilError(t, w.WriteMessage(m))
+	}
+	r := bufio.NewReader(conn)
+	for _, m := range messages {
+		b, err := r.ReadBytes(0)
+		assert.NilError(t, err)
+		decoded, err := Decode(b[:len(b)-1])
+		assert.NilError(t, err)
+		assert.DeepEqual(t, decoded, m)
+	}
+}
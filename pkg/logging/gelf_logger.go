@@ -0,0 +1,215 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/logging/gelf"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+const (
+	gelfAddress         = "gelf-address"
+	gelfCompressionType = "gelf-compression-type"
+)
+
+var gelfOpts = []string{
+	gelfAddress,
+	gelfCompressionType,
+	Tag,
+	Labels,
+	Env,
+	EnvRegex,
+}
+
+func GelfOptsValidate(logOptMap map[string]string) error {
+	for key := range logOptMap {
+		if !strutil.InStringSlice(gelfOpts, key) {
+			log.L.Warnf("log-opt %s is ignored for gelf log driver", key)
+		}
+	}
+	network, _, err := gelf.ParseAddress(logOptMap[gelfAddress])
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", gelfAddress, err)
+	}
+	if compression, ok := logOptMap[gelfCompressionType]; ok {
+		if network != "udp" {
+			return fmt.Errorf("%s is only supported with udp", gelfCompressionType)
+		}
+		if _, err := gelf.ParseCompression(compression); err != nil {
+			return err
+		}
+	}
+	if envRegex, ok := logOptMap[EnvRegex]; ok {
+		if _, err := regexp.Compile(envRegex); err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvRegex, err)
+		}
+	}
+	return nil
+}
+
+type GelfLogger struct {
+	Opts    map[string]string
+	Address string
+	writer  gelf.Writer
+	host    string
+	extra   map[string]string
+}
+
+func (g *GelfLogger) Init(dataStore, ns, id string) error {
+	return nil
+}
+
+func (g *GelfLogger) PreProcess(ctx context.Context, dataStore string, config *logging.Config) error {
+	tag, err := parseLogTag(g.Opts, config)
+	if err != nil {
+		return err
+	}
+	compression, err := gelf.ParseCompression(g.Opts[gelfCompressionType])
+	if err != nil {
+		return err
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(ctx, config.Namespace, g.Address)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		cancel()
+		client.Close()
+	}()
+	container, err := client.LoadContainer(ctx, config.ID)
+	if err != nil {
+		return err
+	}
+	info, err := container.Info(ctx)
+	if err != nil {
+		return err
+	}
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return err
+	}
+	var env, args []string
+	if spec.Process != nil {
+		env = spec.Process.Env
+		args = spec.Process.Args
+	}
+	extra, err := extraAttributes(g.Opts, info.Labels, env)
+	if err != nil {
+		return err
+	}
+	extra["container_id"] = config.ID
+	extra["container_name"] = containerutil.GetContainerName(info.Labels)
+	extra["image_name"] = info.Image
+	extra["command"] = strings.Join(args, " ")
+	extra["tag"] = tag
+	extra["created"] = info.CreatedAt.Format(time.RFC3339Nano)
+	extra["namespace"] = config.Namespace
+
+	writer, err := gelf.NewWriter(g.Opts[gelfAddress], compression)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the gelf server: %w", err)
+	}
+	g.writer = writer
+	g.host = host
+	g.extra = extra
+	return nil
+}
+
+func (g *GelfLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	fn := func(dataChan <-chan string, level int) {
+		defer wg.Done()
+		for line := range dataChan {
+			m := &gelf.Message{
+				Version:      gelf.Version,
+				Host:         g.host,
+				ShortMessage: strings.TrimSuffix(line, "\n"),
+				Timestamp:    float64(time.Now().UnixMilli()) / 1000,
+				Level:        level,
+				Extra:        g.extra,
+			}
+			if err := g.writer.WriteMessage(m); err != nil {
+				log.L.WithError(err).Error("failed to send the log message to the gelf server")
+			}
+		}
+	}
+	go fn(stdout, gelf.LevelInfo)
+	go fn(stderr, gelf.LevelError)
+	wg.Wait()
+	return nil
+}
+
+func (g *GelfLogger) PostProcess() error {
+	return g.writer.Close()
+}
+
+// extraAttributes returns the labels and the environment variables of the container
+// selected by the "labels", "env" and "env-regex" options.
+func extraAttributes(opts map[string]string, containerLabels map[string]string, containerEnv []string) (map[string]string, error) {
+	extra := make(map[string]string)
+	if labels := opts[Labels]; labels != "" {
+		for _, l := range strings.Split(labels, ",") {
+			if v, ok := containerLabels[l]; ok {
+				extra[l] = v
+			}
+		}
+	}
+	envMapping := make(map[string]string, len(containerEnv))
+	for _, e := range containerEnv {
+		if k, v, ok := strings.Cut(e, "="); ok {
+			envMapping[k] = v
+		}
+	}
+	if env := opts[Env]; env != "" {
+		for _, e := range strings.Split(env, ",") {
+			if v, ok := envMapping[e]; ok {
+				extra[e] = v
+			}
+		}
+	}
+	if envRegex := opts[EnvRegex]; envRegex != "" {
+		re, err := regexp.Compile(envRegex)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range envMapping {
+			if re.MatchString(k) {
+				extra[k] = v
+			}
+		}
+	}
+	return extra, nil
+}
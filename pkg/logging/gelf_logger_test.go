@@ -0,0 +1,50 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGelfOptsValidate(t *testing.T) {
+	assert.NilError(t, GelfOptsValidate(map[string]string{gelfAddress: "udp://127.0.0.1:12201", gelfCompressionType: "zlib"}))
+	assert.NilError(t, GelfOptsValidate(map[string]string{gelfAddress: "tcp://127.0.0.1:12201", EnvRegex: "^FOO_"}))
+	assert.ErrorContains(t, GelfOptsValidate(map[string]string{}), gelfAddress)
+	assert.ErrorContains(t, GelfOptsValidate(map[string]string{gelfAddress: "udp://127.0.0.1:12201", gelfCompressionType: "lz4"}), "unknown compression type")
+	assert.ErrorContains(t, GelfOptsValidate(map[string]string{gelfAddress: "tcp://127.0.0.1:12201", gelfCompressionType: "gzip"}), "only supported with udp")
+	assert.ErrorContains(t, GelfOptsValidate(map[string]string{gelfAddress: "udp://127.0.0.1:12201", EnvRegex: "("}), EnvRegex)
+}
+
+func TestExtraAttributes(t *testing.T) {
+	opts := map[string]string{
+		Labels:   "com.example.team,missing",
+		Env:      "FOO",
+		EnvRegex: "^APP_",
+	}
+	labels := map[string]string{"com.example.team": "infra", "com.example.other": "x"}
+	env := []string{"FOO=foo", "BAR=bar", "APP_NAME=web", "APP_ENV=prod=1"}
+	extra, err := extraAttributes(opts, labels, env)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, extra, map[string]string{
+		"com.example.team": "infra",
+		"FOO":              "foo",
+		"APP_NAME":         "web",
+		"APP_ENV":          "prod=1",
+	})
+}
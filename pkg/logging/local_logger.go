@@ -0,0 +1,488 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/plugins/logdriver"
+	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/docker/go-units"
+	"github.com/fahedouch/go-logrotate"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/logging/localfile"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+const (
+	// The defaults of the local log driver are the ones of Docker
+	localDefaultMaxSize  = "20m"
+	localDefaultMaxFile  = 5
+	localDefaultCompress = true
+)
+
+var LocalDriverLogOpts = []string{
+	MaxSize,
+	MaxFile,
+	Compress,
+}
+
+type LocalLogger struct {
+	Opts map[string]string
+	// cache is set when the logger is the local cache of another driver
+	cache  bool
+	logger io.Writer
+}
+
+func LocalLogOptsValidate(logOptMap map[string]string) error {
+	for key := range logOptMap {
+		if !strutil.InStringSlice(LocalDriverLogOpts, key) {
+			log.L.Warnf("log-opt %s is ignored for local log driver", key)
+		}
+	}
+	_, _, _, err := parseLocalLogOpts(logOptMap)
+	return err
+}
+
+func parseLocalLogOpts(opts map[string]string) (maxBytes int64, maxFile int, compress bool, err error) {
+	maxSize := localDefaultMaxSize
+	if v, ok := opts[MaxSize]; ok {
+		maxSize = v
+	}
+	maxBytes, err = units.FromHumanSize(maxSize)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if maxBytes <= 0 {
+		return 0, 0, false, fmt.Errorf("max-size must be a positive number")
+	}
+	maxFile = localDefaultMaxFile
+	if v, ok := opts[MaxFile]; ok {
+		maxFile, err = strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if maxFile < 1 {
+			return 0, 0, false, fmt.Errorf("max-file cannot be less than 1")
+		}
+	}
+	compress = localDefaultCompress
+	if v, ok := opts[Compress]; ok {
+		compress, err = strconv.ParseBool(v)
+		if err != nil {
+			return 0, 0, false, fmt.Errorf("invalid value for compress: %w", err)
+		}
+	}
+	return maxBytes, maxFile, compress, nil
+}
+
+func (localLogger *LocalLogger) logFilePath(dataStore, ns, id string) string {
+	if localLogger.cache {
+		return localfile.CachePath(dataStore, ns, id)
+	}
+	return localfile.Path(dataStore, ns, id)
+}
+
+func (localLogger *LocalLogger) Init(dataStore, ns, id string) error {
+	logFilePath := localLogger.logFilePath(dataStore, ns, id)
+	if err := os.MkdirAll(filepath.Dir(logFilePath), 0700); err != nil {
+		return err
+	}
+	if !localLogger.cache {
+		return nil
+	}
+	// The cache file tells `nerdctl logs` that the logs are cached, even before anything is logged
+	f, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (localLogger *LocalLogger) PreProcess(ctx context.Context, dataStore string, config *logging.Config) error {
+	maxBytes, maxFile, compress, err := parseLocalLogOpts(localLogger.Opts)
+	if err != nil {
+		return err
+	}
+	logFilePath := localLogger.logFilePath(dataStore, config.Namespace, config.ID)
+	rotated, err := rotatedLocalLogFiles(logFilePath)
+	if err != nil {
+		return err
+	}
+	l := &logrotate.Logger{
+		Filename: logFilePath,
+		MaxBytes: maxBytes,
+		// MaxBackups does not include file to write logs to
+		MaxBackups: maxFile - 1,
+		Compress:   compress,
+	}
+	// Keep numbering the rotated files after the existing ones, e.g., when the container is restarted
+	if len(rotated) > 0 {
+		l.FileOrder = rotated[len(rotated)-1].order
+	}
+	if maxFile == 1 {
+		// logrotate keeps all the rotated files when MaxBackups is 0
+		localLogger.logger = &singleLogFileWriter{Logger: l, maxBytes: maxBytes}
+	} else {
+		localLogger.logger = l
+	}
+	return nil
+}
+
+func (localLogger *LocalLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	return localfile.Encode(stdout, stderr, localLogger.logger)
+}
+
+func (localLogger *LocalLogger) PostProcess() error {
+	if c, ok := localLogger.logger.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// singleLogFileWriter removes the rotated files of a logrotate.Logger as soon as they are created.
+type singleLogFileWriter struct {
+	*logrotate.Logger
+	maxBytes int64
+	size     int64
+	statted  bool
+}
+
+func (w *singleLogFileWriter) Write(p []byte) (int, error) {
+	if !w.statted {
+		if st, err := os.Stat(w.Filename); err == nil {
+			w.size = st.Size()
+		}
+		w.statted = true
+	}
+	// Same condition as logrotate.Logger.Write
+	rotating := w.size+int64(len(p)) > w.maxBytes
+	n, err := w.Logger.Write(p)
+	if rotating {
+		w.size = 0
+		rotated, listErr := rotatedLocalLogFiles(w.Filename)
+		if listErr != nil {
+			log.L.WithError(listErr).Warn("failed to list the rotated log files")
+		}
+		for _, f := range rotated {
+			if rmErr := os.Remove(f.path); rmErr != nil {
+				log.L.WithError(rmErr).Warnf("failed to remove the rotated log file %q", f.path)
+			}
+		}
+	}
+	w.size += int64(n)
+	return n, err
+}
+
+type rotatedLogFile struct {
+	path  string
+	order int
+}
+
+// rotatedLocalLogFiles returns the files rotated from logFilePath, i.e., "<logFilePath>.<N>" or "<logFilePath>.<N>.gz",
+// from the oldest to the newest.
+func rotatedLocalLogFiles(logFilePath string) ([]rotatedLogFile, error) {
+	dir, base := filepath.Split(logFilePath)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var files []rotatedLogFile
+	for _, e := range entries {
+		suffix, ok := strings.CutPrefix(e.Name(), base+".")
+		if !ok {
+			continue
+		}
+		order, err := strconv.Atoi(strings.TrimSuffix(suffix, ".gz"))
+		if err != nil {
+			continue
+		}
+		files = append(files, rotatedLogFile{path: filepath.Join(dir, e.Name()), order: order})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].order < files[j].order
+	})
+	return files, nil
+}
+
+// Loads log entries from logfiles produced by the local driver and forwards
+// them to the provided io.Writers after applying the provided logging options.
+func viewLogsLocal(lvopts LogViewOptions, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
+	logFilePath := localfile.Path(lvopts.DatastoreRootPath, lvopts.Namespace, lvopts.ContainerID)
+	return viewLogsLocalFile(lvopts, logFilePath, stdout, stderr, stopChannel)
+}
+
+func viewLogsLocalFile(lvopts LogViewOptions, logFilePath string, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
+	if _, err := os.Stat(logFilePath); err != nil {
+		// The file is created by the logger process, which may not have started yet
+		if errors.Is(err, os.ErrNotExist) {
+			time.Sleep(200 * time.Millisecond)
+			_, err = os.Stat(logFilePath)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to stat local log file %w", err)
+		}
+	}
+	return viewLogsLocalDirect(lvopts, logFilePath, stdout, stderr, stopChannel)
+}
+
+// localLogWriter writes the entries to stdout or stderr, after applying the time filters.
+type localLogWriter struct {
+	stdout, stderr io.Writer
+	timestamps     bool
+	since, until   time.Time
+}
+
+func newLocalLogWriter(lvopts LogViewOptions, stdout, stderr io.Writer) (*localLogWriter, error) {
+	w := &localLogWriter{stdout: stdout, stderr: stderr, timestamps: lvopts.Timestamps}
+	now := time.Now()
+	parse := func(name, value string) (time.Time, error) {
+		if value == "" {
+			return time.Time{}, nil
+		}
+		ts, err := timetypes.GetTimestamp(value, now)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid value for %q: %w", name, err)
+		}
+		sec, nsec, err := timetypes.ParseTimestamps(ts, 0)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid value for %q: %w", name, err)
+		}
+		return time.Unix(sec, nsec), nil
+	}
+	var err error
+	if w.since, err = parse("since", lvopts.Since); err != nil {
+		return nil, err
+	}
+	if w.until, err = parse("until", lvopts.Until); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *localLogWriter) write(e *logdriver.LogEntry) error {
+	t := time.Unix(0, e.TimeNano)
+	if (!w.since.IsZero() && t.Before(w.since)) || (!w.until.IsZero() && t.After(w.until)) {
+		return nil
+	}
+	var output []byte
+	if w.timestamps {
+		output = append(output, t.UTC().Format(time.RFC3339Nano)...)
+		output = append(output, ' ')
+	}
+	output = append(output, e.Line...)
+	if !e.Partial {
+		output = append(output, '\n')
+	}
+	switch e.Source {
+	case "stdout":
+		_, err := w.stdout.Write(output)
+		return err
+	case "stderr":
+		_, err := w.stderr.Write(output)
+		return err
+	default:
+		log.L.Errorf("unknown stream name %q", e.Source)
+		return nil
+	}
+}
+
+// readRotatedLocalLogFile returns the entries of a rotated (and possibly compressed) log file.
+// If tail is positive, only the last tail entries are returned.
+func readRotatedLocalLogFile(path string, tail int) ([]*logdriver.LogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress %q: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	dec := localfile.NewDecoder(r)
+	var entries []*logdriver.LogEntry
+	for {
+		e := &logdriver.LogEntry{}
+		if _, err := dec.Decode(e); err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+			return nil, fmt.Errorf("failed to read %q: %w", path, err)
+		}
+		entries = append(entries, e)
+		if tail > 0 && len(entries) > tail {
+			entries = entries[1:]
+		}
+	}
+}
+
+// Loads the log entries directly from the provided log file, and from the files rotated from it.
+// If `LogViewOptions.Follow` is provided, it will keep reading the log file until
+// it receives something through the stopChannel.
+func viewLogsLocalDirect(lvopts LogViewOptions, logFilePath string, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
+	w, err := newLocalLogWriter(lvopts, stdout, stderr)
+	if err != nil {
+		return err
+	}
+
+	fin, err := openFileShareDelete(logFilePath)
+	if err != nil {
+		return err
+	}
+	defer func() { fin.Close() }()
+	st, err := fin.Stat()
+	if err != nil {
+		return err
+	}
+
+	rotated, err := rotatedLocalLogFiles(logFilePath)
+	if err != nil {
+		return err
+	}
+
+	// Find the first entry to print, in the current file or in the rotated ones
+	var start int64
+	var previous []*logdriver.LogEntry
+	if lvopts.Tail > 0 {
+		var found int
+		start, found, err = localfile.TailOffset(fin, st.Size(), int(lvopts.Tail))
+		if err != nil {
+			return fmt.Errorf("failed to tail %d lines of local log file %q: %w", lvopts.Tail, logFilePath, err)
+		}
+		for i := len(rotated) - 1; i >= 0 && found < int(lvopts.Tail); i-- {
+			entries, err := readRotatedLocalLogFile(rotated[i].path, int(lvopts.Tail)-found)
+			if err != nil {
+				return err
+			}
+			previous = append(entries, previous...)
+			found += len(entries)
+		}
+	} else {
+		for _, f := range rotated {
+			entries, err := readRotatedLocalLogFile(f.path, 0)
+			if err != nil {
+				return err
+			}
+			previous = append(previous, entries...)
+		}
+	}
+	for _, e := range previous {
+		if err := w.write(e); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fin.Seek(start, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek in log file %q from %d position: %w", logFilePath, start, err)
+	}
+
+	var watcher *fsnotify.Watcher
+	baseName := filepath.Base(logFilePath)
+	dir := filepath.Dir(logFilePath)
+	e := &logdriver.LogEntry{}
+	for {
+		select {
+		case <-stopChannel:
+			log.L.Debug("received stop signal while re-reading local logfile, returning")
+			return nil
+		default:
+		}
+
+		// Read all the complete entries
+		pos, err := fin.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		dec := localfile.NewDecoder(fin)
+		for {
+			n, err := dec.Decode(e)
+			if err != nil {
+				if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+					break
+				}
+				return fmt.Errorf("error occurred while reading local logfile %q: %w", logFilePath, err)
+			}
+			pos += int64(n)
+			if err := w.write(e); err != nil {
+				return err
+			}
+		}
+		if !lvopts.Follow {
+			return nil
+		}
+		// Rewind to the beginning of the incomplete entry, if any
+		if _, err := fin.Seek(pos, io.SeekStart); err != nil {
+			return err
+		}
+
+		if watcher == nil {
+			// Initialize the watcher if it has not been initialized yet.
+			if watcher, err = NewLogFileWatcher(dir); err != nil {
+				return err
+			}
+			defer watcher.Close()
+			// If we just created the watcher, try again to read as we might have missed
+			// the event.
+			continue
+		}
+
+		// Wait until the next log change.
+		recreated, err := startTail(context.Background(), baseName, watcher)
+		if err != nil {
+			return err
+		}
+		if recreated {
+			// Read the rest of the rotated file before switching to the new one
+			for {
+				if _, err := dec.Decode(e); err != nil {
+					break
+				}
+				if err := w.write(e); err != nil {
+					return err
+				}
+			}
+			newF, err := openFileShareDelete(logFilePath)
+			if err != nil {
+				return fmt.Errorf("failed to open local logfile %q: %w", logFilePath, err)
+			}
+			fin.Close()
+			fin = newF
+		}
+	}
+}
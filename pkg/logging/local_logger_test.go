@@ -0,0 +1,199 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+
+	"github.com/containerd/nerdctl/v2/pkg/logging/localfile"
+)
+
+// writeLocalLogs writes the lines to the log file of the container "test" with a LocalLogger with the options opts.
+func writeLocalLogs(t *testing.T, dataStore string, opts map[string]string, stdoutLines, stderrLines []string) string {
+	t.Helper()
+	assert.NilError(t, LocalLogOptsValidate(opts))
+	logger := &LocalLogger{Opts: opts}
+	assert.NilError(t, logger.Init(dataStore, "default", "test"))
+	assert.NilError(t, logger.PreProcess(context.Background(), dataStore, &logging.Config{Namespace: "default", ID: "test"}))
+
+	stdout := make(chan string)
+	stderr := make(chan string)
+	go func() {
+		for _, l := range stdoutLines {
+			stdout <- l
+		}
+		close(stdout)
+	}()
+	go func() {
+		for _, l := range stderrLines {
+			stderr <- l
+		}
+		close(stderr)
+	}()
+	assert.NilError(t, logger.Process(stdout, stderr))
+	assert.NilError(t, logger.PostProcess())
+	return localfile.Path(dataStore, "default", "test")
+}
+
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line%03d\n", i)
+	}
+	return lines
+}
+
+func TestLocalLogger(t *testing.T) {
+	dataStore := t.TempDir()
+	logFilePath := writeLocalLogs(t, dataStore, nil, []string{"foo\n", "partial"}, []string{"bar\n"})
+
+	stdoutBuf := &bytes.Buffer{}
+	stderrBuf := &bytes.Buffer{}
+	assert.NilError(t, viewLogsLocalDirect(LogViewOptions{}, logFilePath, stdoutBuf, stderrBuf, nil))
+	assert.Equal(t, stdoutBuf.String(), "foo\npartial")
+	assert.Equal(t, stderrBuf.String(), "bar\n")
+
+	stdoutBuf.Reset()
+	stderrBuf.Reset()
+	assert.NilError(t, viewLogsLocalDirect(LogViewOptions{Since: "1h"}, logFilePath, stdoutBuf, stderrBuf, nil))
+	assert.Equal(t, stdoutBuf.String(), "foo\npartial")
+
+	stdoutBuf.Reset()
+	stderrBuf.Reset()
+	assert.NilError(t, viewLogsLocalDirect(LogViewOptions{Until: "1h"}, logFilePath, stdoutBuf, stderrBuf, nil))
+	assert.Equal(t, stdoutBuf.String(), "")
+	assert.Equal(t, stderrBuf.String(), "")
+}
+
+func TestLocalLoggerRotation(t *testing.T) {
+	dataStore := t.TempDir()
+	lines := numberedLines(200)
+	logFilePath := writeLocalLogs(t, dataStore, map[string]string{MaxSize: "1k", MaxFile: "3"}, lines, nil)
+
+	rotated, err := rotatedLocalLogFiles(logFilePath)
+	assert.NilError(t, err)
+	// max-file includes the current file
+	assert.Equal(t, len(rotated), 2)
+	for _, f := range rotated {
+		assert.Assert(t, strings.HasSuffix(f.path, ".gz"), f.path)
+	}
+	st, err := os.Stat(logFilePath)
+	assert.NilError(t, err)
+	assert.Assert(t, st.Size() <= 1024)
+
+	// The oldest entries have been removed with the oldest files
+	stdoutBuf := &bytes.Buffer{}
+	assert.NilError(t, viewLogsLocalDirect(LogViewOptions{}, logFilePath, stdoutBuf, &bytes.Buffer{}, nil))
+	out := stdoutBuf.String()
+	assert.Assert(t, strings.HasSuffix(out, strings.Join(lines[190:], "")), out)
+	assert.Assert(t, !strings.Contains(out, lines[0]), out)
+
+	// The tail spans the current file and the rotated ones
+	for _, tail := range []int{1, 10, 50} {
+		stdoutBuf.Reset()
+		assert.NilError(t, viewLogsLocalDirect(LogViewOptions{Tail: uint(tail)}, logFilePath, stdoutBuf, &bytes.Buffer{}, nil))
+		assert.Equal(t, stdoutBuf.String(), strings.Join(lines[len(lines)-tail:], ""))
+	}
+}
+
+func TestLocalLoggerSingleFile(t *testing.T) {
+	dataStore := t.TempDir()
+	lines := numberedLines(200)
+	logFilePath := writeLocalLogs(t, dataStore, map[string]string{MaxSize: "1k", MaxFile: "1"}, lines, nil)
+
+	rotated, err := rotatedLocalLogFiles(logFilePath)
+	assert.NilError(t, err)
+	assert.Equal(t, len(rotated), 0)
+
+	stdoutBuf := &bytes.Buffer{}
+	assert.NilError(t, viewLogsLocalDirect(LogViewOptions{Tail: 3}, logFilePath, stdoutBuf, &bytes.Buffer{}, nil))
+	assert.Equal(t, stdoutBuf.String(), strings.Join(lines[197:], ""))
+}
+
+func TestLocalLoggerFollow(t *testing.T) {
+	dataStore := t.TempDir()
+	logFilePath := writeLocalLogs(t, dataStore, nil, []string{"first\n"}, nil)
+
+	stdoutBuf := &bytes.Buffer{}
+	stopChannel := make(chan os.Signal)
+	done := make(chan error)
+	go func() {
+		done <- viewLogsLocalDirect(LogViewOptions{Follow: true}, logFilePath, stdoutBuf, &bytes.Buffer{}, stopChannel)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// Append an entry, like a restarted container
+	f, err := os.OpenFile(logFilePath, os.O_APPEND|os.O_WRONLY, 0600)
+	assert.NilError(t, err)
+	stdout := make(chan string, 1)
+	stdout <- "second\n"
+	close(stdout)
+	stderr := make(chan string)
+	close(stderr)
+	assert.NilError(t, localfile.Encode(stdout, stderr, f))
+	assert.NilError(t, f.Close())
+
+	time.Sleep(2 * time.Second)
+	close(stopChannel)
+	assert.NilError(t, <-done)
+	assert.Equal(t, stdoutBuf.String(), "first\nsecond\n")
+}
+
+func TestLocalLogOptsValidate(t *testing.T) {
+	assert.NilError(t, LocalLogOptsValidate(map[string]string{MaxSize: "10m", MaxFile: "2", Compress: "false"}))
+	assert.ErrorContains(t, LocalLogOptsValidate(map[string]string{MaxSize: "foo"}), "invalid size")
+	assert.ErrorContains(t, LocalLogOptsValidate(map[string]string{MaxFile: "0"}), "max-file")
+	assert.ErrorContains(t, LocalLogOptsValidate(map[string]string{Compress: "maybe"}), "compress")
+}
+
+func TestLocalLogFileTailOffset(t *testing.T) {
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "container.log"))
+	assert.NilError(t, err)
+	defer f.Close()
+	stdout := make(chan string, 3)
+	for _, l := range []string{"a\n", "bb\n", "ccc\n"} {
+		stdout <- l
+	}
+	close(stdout)
+	stderr := make(chan string)
+	close(stderr)
+	assert.NilError(t, localfile.Encode(stdout, stderr, f))
+	st, err := f.Stat()
+	assert.NilError(t, err)
+
+	offset, found, err := localfile.TailOffset(f, st.Size(), 2)
+	assert.NilError(t, err)
+	assert.Equal(t, found, 2)
+	assert.Assert(t, offset > 0 && offset < st.Size())
+
+	offset, found, err = localfile.TailOffset(f, st.Size(), 5)
+	assert.NilError(t, err)
+	assert.Equal(t, found, 3)
+	assert.Equal(t, offset, int64(0))
+}
@@ -0,0 +1,183 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package localfile implements the file format of the "local" log driver, compatible with Docker:
+// each entry is a protobuf-encoded logdriver.LogEntry, preceded and followed by its size (big-endian uint32),
+// so that the file can be read backwards.
+package localfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/plugins/logdriver"
+
+	"github.com/containerd/log"
+)
+
+const (
+	encodeBinaryLen = 4
+	// maxMsgLen is the maximum size of an encoded entry, as in Docker
+	maxMsgLen = 1e6
+)
+
+// Path returns the path of the log file of the container.
+func Path(dataStore, ns, id string) string {
+	// the file name corresponds to Docker
+	return filepath.Join(dataStore, "containers", ns, id, "local-logs", "container.log")
+}
+
+// CachePath returns the path of the local cache of the logs of the container,
+// for the drivers whose logs cannot be read back.
+func CachePath(dataStore, ns, id string) string {
+	return filepath.Join(dataStore, "containers", ns, id, "local-logs", "container-cached.log")
+}
+
+// Encoder writes log entries to a stream.
+type Encoder struct {
+	w   io.Writer
+	buf []byte
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, buf: make([]byte, 1024)}
+}
+
+// Encode writes the entry with a single call to Write, so that the entry is never split by a log rotation.
+func (e *Encoder) Encode(entry *logdriver.LogEntry) error {
+	n := entry.Size()
+	total := n + 2*encodeBinaryLen
+	if total > len(e.buf) {
+		e.buf = make([]byte, total)
+	}
+	binary.BigEndian.PutUint32(e.buf, uint32(n))
+	if _, err := entry.MarshalTo(e.buf[encodeBinaryLen:]); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint32(e.buf[encodeBinaryLen+n:], uint32(n))
+	_, err := e.w.Write(e.buf[:total])
+	return err
+}
+
+// Encode writes the lines received from stdout and stderr to writer, until both channels are closed.
+func Encode(stdout <-chan string, stderr <-chan string, writer io.Writer) error {
+	enc := NewEncoder(writer)
+	var encMu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(2)
+	f := func(dataChan <-chan string, name string) {
+		defer wg.Done()
+		e := &logdriver.LogEntry{
+			Source: name,
+		}
+		for logEntry := range dataChan {
+			// Like Docker, the line is stored without its trailing newline, and is partial if it has none
+			line, complete := strings.CutSuffix(logEntry, "\n")
+			e.Line = []byte(line)
+			e.Partial = !complete
+			e.TimeNano = time.Now().UnixNano()
+			encMu.Lock()
+			encErr := enc.Encode(e)
+			encMu.Unlock()
+			if encErr != nil {
+				log.L.WithError(encErr).Errorf("failed to encode log entry")
+				return
+			}
+		}
+	}
+	go f(stdout, "stdout")
+	go f(stderr, "stderr")
+	wg.Wait()
+	return nil
+}
+
+// Decoder reads log entries from a stream.
+type Decoder struct {
+	r      io.Reader
+	lenBuf []byte
+	buf    []byte
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r:      r,
+		lenBuf: make([]byte, encodeBinaryLen),
+		buf:    make([]byte, 1024),
+	}
+}
+
+// Decode reads the next entry and returns the number of bytes it occupies in the stream.
+// It returns io.EOF at the end of the stream, and io.ErrUnexpectedEOF if the last entry is incomplete,
+// e.g., when it is still being written.
+func (d *Decoder) Decode(entry *logdriver.LogEntry) (int, error) {
+	n, err := io.ReadFull(d.r, d.lenBuf)
+	if err != nil {
+		if n > 0 && errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+	size := int(binary.BigEndian.Uint32(d.lenBuf))
+	if size > maxMsgLen {
+		return 0, fmt.Errorf("log entry is too large (%d bytes), the log file may be corrupted", size)
+	}
+	if len(d.buf) < size+encodeBinaryLen {
+		d.buf = make([]byte, size+encodeBinaryLen)
+	}
+	if _, err := io.ReadFull(d.r, d.buf[:size+encodeBinaryLen]); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+	if trailing := int(binary.BigEndian.Uint32(d.buf[size:])); trailing != size {
+		return 0, fmt.Errorf("mismatched sizes of log entry (%d != %d), the log file may be corrupted", size, trailing)
+	}
+	entry.Reset()
+	if err := entry.Unmarshal(d.buf[:size]); err != nil {
+		return 0, err
+	}
+	return size + 2*encodeBinaryLen, nil
+}
+
+// TailOffset returns the offset of the n-th last entry of r, whose size is size, by reading it backwards.
+// If r has fewer than n entries, it returns 0 and their number.
+func TailOffset(r io.ReaderAt, size int64, n int) (int64, int, error) {
+	lenBuf := make([]byte, encodeBinaryLen)
+	offset := size
+	found := 0
+	for found < n && offset > 0 {
+		if offset < 2*encodeBinaryLen {
+			return 0, 0, errors.New("truncated log entry, the log file may be corrupted")
+		}
+		if _, err := r.ReadAt(lenBuf, offset-encodeBinaryLen); err != nil {
+			return 0, 0, err
+		}
+		entrySize := int64(binary.BigEndian.Uint32(lenBuf)) + 2*encodeBinaryLen
+		if entrySize > offset {
+			return 0, 0, errors.New("truncated log entry, the log file may be corrupted")
+		}
+		offset -= entrySize
+		found++
+	}
+	return offset, found, nil
+}
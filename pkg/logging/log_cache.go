@@ -0,0 +1,183 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/logging/localfile"
+)
+
+// Like Docker, the logs of the drivers that cannot be read back (e.g., fluentd or syslog)
+// are also written to a local cache, in the format of the local driver, so that `nerdctl logs` can read them.
+// See https://docs.docker.com/engine/logging/dual-logging/
+const (
+	CacheDisabled = "cache-disabled"
+	CacheMaxSize  = "cache-max-size"
+	CacheMaxFile  = "cache-max-file"
+	CacheCompress = "cache-compress"
+)
+
+var logCacheOpts = map[string]string{
+	CacheMaxSize:  MaxSize,
+	CacheMaxFile:  MaxFile,
+	CacheCompress: Compress,
+}
+
+// supportsLogCache returns whether the logs of the driver are cached, unless disabled with cache-disabled=true.
+func supportsLogCache(driverName string) bool {
+	if driverName == "none" {
+		return false
+	}
+	_, hasViewer := logViewers[driverName]
+	return !hasViewer
+}
+
+// splitLogCacheOpts splits the cache-* options from the options of the driver.
+// The returned cache options are the options of the local driver, e.g., "max-size" for "cache-max-size".
+func splitLogCacheOpts(opts map[string]string) (cacheOpts, driverOpts map[string]string, disabled bool, err error) {
+	cacheOpts = make(map[string]string)
+	driverOpts = make(map[string]string)
+	for k, v := range opts {
+		if k == CacheDisabled {
+			if disabled, err = strconv.ParseBool(v); err != nil {
+				return nil, nil, false, fmt.Errorf("invalid value for %s: %w", CacheDisabled, err)
+			}
+			continue
+		}
+		if localKey, ok := logCacheOpts[k]; ok {
+			cacheOpts[localKey] = v
+			continue
+		}
+		driverOpts[k] = v
+	}
+	return cacheOpts, driverOpts, disabled, nil
+}
+
+func validateLogCacheOpts(opts map[string]string) error {
+	_, _, _, err := parseLocalLogOpts(opts)
+	if err != nil {
+		return fmt.Errorf("invalid log cache options: %w", err)
+	}
+	return nil
+}
+
+// logCacheEnabled returns whether the logs of a container created with the driver and the options are cached.
+// The containers created before the cache was introduced have no cache file, and are not considered as cached.
+func logCacheEnabled(driverName string, opts map[string]string, lvopts LogViewOptions) bool {
+	if !supportsLogCache(driverName) {
+		return false
+	}
+	if _, _, disabled, err := splitLogCacheOpts(opts); err != nil || disabled {
+		return false
+	}
+	_, err := os.Stat(localfile.CachePath(lvopts.DatastoreRootPath, lvopts.Namespace, lvopts.ContainerID))
+	return err == nil
+}
+
+// cachedDriver writes the logs to both a driver and the local cache.
+type cachedDriver struct {
+	driver Driver
+	cache  *LocalLogger
+}
+
+func (c *cachedDriver) Init(dataStore, ns, id string) error {
+	if err := c.cache.Init(dataStore, ns, id); err != nil {
+		return err
+	}
+	return c.driver.Init(dataStore, ns, id)
+}
+
+func (c *cachedDriver) PreProcess(ctx context.Context, dataStore string, config *logging.Config) error {
+	if err := c.cache.PreProcess(ctx, dataStore, config); err != nil {
+		return err
+	}
+	return c.driver.PreProcess(ctx, dataStore, config)
+}
+
+func (c *cachedDriver) Process(stdout <-chan string, stderr <-chan string) error {
+	driverStdout, cacheStdout := teeLogChannel(stdout)
+	driverStderr, cacheStderr := teeLogChannel(stderr)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := c.cache.Process(cacheStdout, cacheStderr); err != nil {
+			log.L.WithError(err).Error("failed to write the logs to the local cache")
+		}
+	}()
+	err := c.driver.Process(driverStdout, driverStderr)
+	// Drain the channels if the driver returned early, so that the cache gets all the logs
+	go drainLogChannel(driverStdout)
+	go drainLogChannel(driverStderr)
+	wg.Wait()
+	return err
+}
+
+func (c *cachedDriver) PostProcess() error {
+	if err := c.cache.PostProcess(); err != nil {
+		log.L.WithError(err).Error("failed to close the local log cache")
+	}
+	return c.driver.PostProcess()
+}
+
+// teeLogChannel duplicates the lines of in to the driver and the cache, until it is closed.
+// The sends to the driver do not block, so that a stalled driver (e.g., an unreachable fluentd server)
+// does not stall the cache: the lines are dropped for the driver while its buffer is full.
+func teeLogChannel(in <-chan string) (driverOut <-chan string, cacheOut <-chan string) {
+	driverCh := make(chan string, 10000)
+	cacheCh := make(chan string, 10000)
+	go func() {
+		defer close(driverCh)
+		defer close(cacheCh)
+		dropped := 0
+		for s := range in {
+			cacheCh <- s
+			select {
+			case driverCh <- s:
+			default:
+				if dropped == 0 {
+					log.L.Warn("the logging driver is not keeping up, dropping the logs for the driver (the local cache still has them)")
+				}
+				dropped++
+			}
+		}
+		if dropped > 0 {
+			log.L.Warnf("dropped %d lines of the logs for the logging driver", dropped)
+		}
+	}()
+	return driverCh, cacheCh
+}
+
+func drainLogChannel(ch <-chan string) {
+	for range ch {
+	}
+}
+
+// viewLogsCache reads the local cache of the logs.
+func viewLogsCache(lvopts LogViewOptions, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
+	logFilePath := localfile.CachePath(lvopts.DatastoreRootPath, lvopts.Namespace, lvopts.ContainerID)
+	return viewLogsLocalFile(lvopts, logFilePath, stdout, stderr, stopChannel)
+}
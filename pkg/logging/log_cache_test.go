@@ -0,0 +1,119 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+)
+
+func TestGetDriverWithLogCache(t *testing.T) {
+	driver, err := GetDriver("syslog", map[string]string{CacheMaxSize: "1m", "tag": "foo"}, "")
+	assert.NilError(t, err)
+	cached, ok := driver.(*cachedDriver)
+	assert.Assert(t, ok, "expected the logs of syslog to be cached, got %T", driver)
+	assert.DeepEqual(t, cached.cache.Opts, map[string]string{MaxSize: "1m"})
+	assert.DeepEqual(t, cached.driver.(*SyslogLogger).Opts, map[string]string{"tag": "foo"})
+
+	driver, err = GetDriver("syslog", map[string]string{CacheDisabled: "true"}, "")
+	assert.NilError(t, err)
+	_, ok = driver.(*SyslogLogger)
+	assert.Assert(t, ok, "expected the log cache to be disabled, got %T", driver)
+
+	// The drivers that can be read back are not cached
+	driver, err = GetDriver("json-file", nil, "")
+	assert.NilError(t, err)
+	_, ok = driver.(*JSONLogger)
+	assert.Assert(t, ok, "expected the logs of json-file not to be cached, got %T", driver)
+
+	_, err = GetDriver("syslog", map[string]string{CacheDisabled: "maybe"}, "")
+	assert.ErrorContains(t, err, CacheDisabled)
+}
+
+func TestValidateLogCacheOpts(t *testing.T) {
+	assert.NilError(t, ValidateLogOpts("fluentd", map[string]string{CacheMaxSize: "10m", CacheMaxFile: "2", CacheCompress: "false"}))
+	assert.ErrorContains(t, ValidateLogOpts("fluentd", map[string]string{CacheMaxSize: "foo"}), "invalid log cache options")
+	assert.ErrorContains(t, ValidateLogOpts("fluentd", map[string]string{CacheMaxFile: "0"}), "max-file")
+}
+
+func TestCachedDriver(t *testing.T) {
+	dataStore := t.TempDir()
+	mock := &MockDriver{}
+	driver := &cachedDriver{
+		driver: mock,
+		cache:  &LocalLogger{cache: true},
+	}
+	config := &logging.Config{Namespace: "default", ID: "test"}
+	assert.NilError(t, driver.Init(dataStore, config.Namespace, config.ID))
+	assert.NilError(t, driver.PreProcess(context.Background(), dataStore, config))
+
+	stdout := make(chan string, 2)
+	stdout <- "foo\n"
+	stdout <- "bar\n"
+	close(stdout)
+	stderr := make(chan string, 1)
+	stderr <- "baz\n"
+	close(stderr)
+	assert.NilError(t, driver.Process(stdout, stderr))
+	assert.NilError(t, driver.PostProcess())
+
+	assert.DeepEqual(t, mock.receivedStdout, []string{"foo\n", "bar\n"})
+	assert.DeepEqual(t, mock.receivedStderr, []string{"baz\n"})
+
+	stdoutBuf := &bytes.Buffer{}
+	stderrBuf := &bytes.Buffer{}
+	lvopts := LogViewOptions{DatastoreRootPath: dataStore, Namespace: config.Namespace, ContainerID: config.ID}
+	assert.NilError(t, viewLogsCache(lvopts, stdoutBuf, stderrBuf, nil))
+	assert.Equal(t, stdoutBuf.String(), "foo\nbar\n")
+	assert.Equal(t, stderrBuf.String(), "baz\n")
+}
+
+func TestTeeLogChannelStalledDriver(t *testing.T) {
+	in := make(chan string)
+	_, cacheOut := teeLogChannel(in)
+	const n = 20000
+	go func() {
+		defer close(in)
+		for i := 0; i < n; i++ {
+			in <- "foo\n"
+		}
+	}()
+	// The driver channel is never read, but the cache must still get all the lines
+	received := 0
+	for range cacheOut {
+		received++
+	}
+	assert.Equal(t, received, n)
+}
+
+func TestLogCacheEnabled(t *testing.T) {
+	dataStore := t.TempDir()
+	lvopts := LogViewOptions{DatastoreRootPath: dataStore, Namespace: "default", ContainerID: "test"}
+	// The containers created before the cache was introduced have no cache file
+	assert.Assert(t, !logCacheEnabled("syslog", nil, lvopts))
+
+	cache := &LocalLogger{cache: true}
+	assert.NilError(t, cache.Init(dataStore, lvopts.Namespace, lvopts.ContainerID))
+	assert.Assert(t, logCacheEnabled("syslog", nil, lvopts))
+	assert.Assert(t, !logCacheEnabled("syslog", map[string]string{CacheDisabled: "true"}, lvopts))
+	assert.Assert(t, !logCacheEnabled("json-file", nil, lvopts))
+}
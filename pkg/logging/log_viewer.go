@@ -45,6 +45,7 @@ func RegisterLogViewer(driverName string, lvfn LogViewerFunc) {
 
 func init() {
 	RegisterLogViewer("json-file", viewLogsJSONFile)
+	RegisterLogViewer("local", viewLogsLocal)
 	RegisterLogViewer("journald", viewLogsJournald)
 	RegisterLogViewer("cri", viewLogsCRI)
 }
@@ -166,7 +167,13 @@ func (lv *ContainerLogViewer) PrintLogsTo(stdout, stderr io.Writer) error {
 	}
 	viewerFunc, err := getLogViewer(lv.loggingConfig.Driver)
 	if err != nil {
-		return err
+		if !logCacheEnabled(lv.loggingConfig.Driver, lv.loggingConfig.Opts, lv.logViewingOptions) {
+			if supportsLogCache(lv.loggingConfig.Driver) {
+				return fmt.Errorf("%w: the local cache of the logs is disabled (%s=true)", err, CacheDisabled)
+			}
+			return err
+		}
+		viewerFunc = viewLogsCache
 	}
 
 	return viewerFunc(lv.logViewingOptions, stdout, stderr, lv.stopChannel)
@@ -18,6 +18,7 @@ package logging
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -30,6 +31,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/docker/cli/templates"
 	"github.com/fsnotify/fsnotify"
 	"github.com/muesli/cancelreader"
 
@@ -47,9 +49,11 @@ const (
 	LogPath    = "log-path"
 	MaxSize    = "max-size"
 	MaxFile    = "max-file"
+	Compress   = "compress"
 	Tag        = "tag"
 	Env        = "env"
 	Labels     = "labels"
+	EnvRegex   = "env-regex"
 )
 
 type Driver interface {
@@ -66,6 +70,16 @@ var drivers = make(map[string]DriverFactory)
 var driversLogOptsValidateFunctions = make(map[string]LogOptsValidateFunc)
 
 func ValidateLogOpts(logDriver string, logOpts map[string]string) error {
+	if supportsLogCache(logDriver) {
+		cacheOpts, driverOpts, _, err := splitLogCacheOpts(logOpts)
+		if err != nil {
+			return err
+		}
+		if err := validateLogCacheOpts(cacheOpts); err != nil {
+			return err
+		}
+		logOpts = driverOpts
+	}
 	if value, ok := driversLogOptsValidateFunctions[logDriver]; ok && value != nil {
 		return value(logOpts)
 	}
@@ -91,7 +105,21 @@ func GetDriver(name string, opts map[string]string, address string) (Driver, err
 	if !ok {
 		return nil, fmt.Errorf("unknown logging driver %q: %w", name, errdefs.ErrNotFound)
 	}
-	return driverFactory(opts, address)
+	if !supportsLogCache(name) {
+		return driverFactory(opts, address)
+	}
+	cacheOpts, driverOpts, disabled, err := splitLogCacheOpts(opts)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := driverFactory(driverOpts, address)
+	if err != nil || disabled {
+		return driver, err
+	}
+	return &cachedDriver{
+		driver: driver,
+		cache:  &LocalLogger{Opts: cacheOpts, cache: true},
+	}, nil
 }
 
 func init() {
@@ -101,6 +129,9 @@ func init() {
 	RegisterDriver("json-file", func(opts map[string]string, address string) (Driver, error) {
 		return &JSONLogger{Opts: opts}, nil
 	}, JSONFileLogOptsValidate)
+	RegisterDriver("local", func(opts map[string]string, address string) (Driver, error) {
+		return &LocalLogger{Opts: opts}, nil
+	}, LocalLogOptsValidate)
 	RegisterDriver("journald", func(opts map[string]string, address string) (Driver, error) {
 		return &JournaldLogger{Opts: opts, Address: address}, nil
 	}, JournalLogOptsValidate)
@@ -110,6 +141,9 @@ func init() {
 	RegisterDriver("syslog", func(opts map[string]string, address string) (Driver, error) {
 		return &SyslogLogger{Opts: opts}, nil
 	}, SyslogOptsValidate)
+	RegisterDriver("gelf", func(opts map[string]string, address string) (Driver, error) {
+		return &GelfLogger{Opts: opts, Address: address}, nil
+	}, GelfOptsValidate)
 }
 
 // Main is the entrypoint for the containerd runtime v2 logging plugin mode.
@@ -154,6 +188,35 @@ func LoadLogConfig(dataStore, ns, id string) (LogConfig, error) {
 	return logConfig, nil
 }
 
+type identifier struct {
+	ID        string
+	FullID    string
+	Namespace string
+}
+
+// parseLogTag executes the template of the "tag" option, defaulting to the short ID of the container.
+func parseLogTag(opts map[string]string, config *logging.Config) (string, error) {
+	shortID := config.ID[:12]
+	tag, ok := opts[Tag]
+	if !ok {
+		return shortID, nil
+	}
+	tmpl, err := templates.Parse(tag)
+	if err != nil {
+		return "", err
+	}
+	idn := identifier{
+		ID:        shortID,
+		FullID:    config.ID,
+		Namespace: config.Namespace,
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, idn); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
 func getLockPath(dataStore, ns, id string) string {
 	return filepath.Join(dataStore, "containers", ns, id, "logger-lock")
 }
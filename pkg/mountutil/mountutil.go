@@ -48,6 +48,7 @@ type Processed struct {
 	Mount           specs.Mount
 	Name            string // name
 	AnonymousVolume string // anonymous volume name
+	Driver          string // volume driver, for named volumes
 	Mode            string
 	Opts            []oci.SpecOpts
 }
@@ -57,9 +58,12 @@ type volumeSpec struct {
 	Name            string
 	Source          string
 	AnonymousVolume string
+	Driver          string
 }
 
-func ProcessFlagV(s string, volStore volumestore.VolumeStore, createDir bool) (*Processed, error) {
+// ProcessFlagV parses a -v specification, creating the volume if needed.
+// opts are used to create named volumes that do not exist yet (e.g. `--volume-driver`).
+func ProcessFlagV(s string, volStore volumestore.VolumeStore, createDir bool, opts ...volumestore.CreateOpt) (*Processed, error) {
 	var (
 		res      *Processed
 		volSpec  volumeSpec
@@ -101,7 +105,7 @@ func ProcessFlagV(s string, volStore volumestore.VolumeStore, createDir bool) (*
 
 		// Get volume spec
 		src = split[0]
-		volSpec, err = handleVolumeToMount(src, dst, volStore, createDir)
+		volSpec, err = handleVolumeToMount(src, dst, volStore, createDir, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -111,6 +115,7 @@ func ProcessFlagV(s string, volStore volumestore.VolumeStore, createDir bool) (*
 			Type:            volSpec.Type,
 			Name:            volSpec.Name,
 			AnonymousVolume: volSpec.AnonymousVolume,
+			Driver:          volSpec.Driver,
 		}
 
 		// Parse volume options
@@ -199,18 +204,24 @@ func handleAnonymousVolumes(s string, volStore volumestore.VolumeStore) (volumeS
 	return res, nil
 }
 
-func handleNamedVolumes(source string, volStore volumestore.VolumeStore) (volumeSpec, error) {
+func handleNamedVolumes(source string, volStore volumestore.VolumeStore, opts ...volumestore.CreateOpt) (volumeSpec, error) {
 	var res volumeSpec
 	res.Name = source
 
 	// Create returns an existing volume or creates a new one if necessary.
-	vol, err := volStore.CreateWithoutLock(res.Name, nil)
+	vol, err := volStore.CreateWithoutLock(res.Name, nil, opts...)
 	if err != nil {
 		return res, fmt.Errorf("failed to get volume %q: %w", res.Name, err)
 	}
-	// src is now an absolute path
+	// Volumes created with options (e.g. `--opt type=nfs`) are mounted on first use
+	if err = volStore.MountWithoutLock(res.Name); err != nil {
+		return res, err
+	}
+	// src is now an absolute path.
+	// For volumes of other drivers, it is replaced by the path returned by the driver when mounting the volume.
 	res.Type = Volume
 	res.Source = vol.Mountpoint
+	res.Driver = vol.Driver
 
 	return res, nil
 }
@@ -265,3 +276,30 @@ func isNamedVolume(s string) bool {
 	// If the volume name is invalid, we assume it is a path
 	return err == nil
 }
+
+// NamedVolume returns the name of the volume of a -v specification, if it refers to a named volume.
+func NamedVolume(s string) (string, bool) {
+	split, err := splitVolumeSpec(s)
+	if err != nil || len(split) < 2 || !isNamedVolume(split[0]) {
+		return "", false
+	}
+	return split[0], true
+}
+
+// NamedVolumeOfMount returns the name of the volume of a --mount specification, if it refers to a named volume.
+func NamedVolumeOfMount(s string) (string, bool) {
+	var mountType, src string
+	for _, field := range strings.Split(s, ",") {
+		key, value, _ := strings.Cut(field, "=")
+		switch strings.ToLower(key) {
+		case "type":
+			mountType = value
+		case "source", "src":
+			src = value
+		}
+	}
+	if (mountType != "" && mountType != Volume) || !isNamedVolume(src) {
+		return "", false
+	}
+	return src, true
+}
@@ -67,6 +67,6 @@ func ProcessFlagTmpfs(s string) (*Processed, error) {
 	return nil, errdefs.ErrNotImplemented
 }
 
-func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, error) {
+func ProcessFlagMount(s string, volStore volumestore.VolumeStore, opts ...volumestore.CreateOpt) (*Processed, error) {
 	return nil, errdefs.ErrNotImplemented
 }
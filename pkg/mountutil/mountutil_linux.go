@@ -291,7 +291,9 @@ func ProcessFlagTmpfs(s string) (*Processed, error) {
 	return res, nil
 }
 
-func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, error) {
+// ProcessFlagMount parses a --mount specification.
+// opts are used to create named volumes that do not exist yet (e.g. `--volume-driver`).
+func ProcessFlagMount(s string, volStore volumestore.VolumeStore, opts ...volumestore.CreateOpt) (*Processed, error) {
 	fields := strings.Split(s, ",")
 	var (
 		mountType        string
@@ -431,7 +433,7 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 		return ProcessFlagTmpfs(fieldsStr)
 	case Volume, Bind:
 		// createDir=false for --mount option to disallow creating directories on host if not found
-		return ProcessFlagV(fieldsStr, volStore, false)
+		return ProcessFlagV(fieldsStr, volStore, false, opts...)
 	}
 	return nil, fmt.Errorf("invalid mount type '%s' must be a volume/bind/tmpfs", mountType)
 }
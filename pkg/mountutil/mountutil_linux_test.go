@@ -208,6 +208,22 @@ func TestProcessTmpfs(t *testing.T) {
 	}
 }
 
+func TestNamedVolumeOfMount(t *testing.T) {
+	testCases := map[string]string{
+		"type=volume,src=vol-1,dst=/app,readonly": "vol-1",
+		"source=vol-1,target=/app":                "vol-1",
+		"type=bind,src=/tmp,dst=/app":             "",
+		"type=volume,src=/tmp,dst=/app":           "",
+		"type=tmpfs,dst=/app":                     "",
+		"type=volume,dst=/app":                    "",
+	}
+	for k, expected := range testCases {
+		name, ok := NamedVolumeOfMount(k)
+		assert.Equal(t, expected, name, k)
+		assert.Equal(t, expected != "", ok, k)
+	}
+}
+
 func TestProcessFlagV(t *testing.T) {
 	tests := []struct {
 		rawSpec string
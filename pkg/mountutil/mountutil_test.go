@@ -27,12 +27,16 @@ type MockVolumeStore struct {
 	volumestore.VolumeStore
 }
 
-func (mv *MockVolumeStore) CreateWithoutLock(name string, labels []string) (*native.Volume, error) {
+func (mv *MockVolumeStore) CreateWithoutLock(name string, labels []string, opts ...volumestore.CreateOpt) (*native.Volume, error) {
 	if runtime.GOOS == "windows" {
 		return &native.Volume{Name: "test_volume", Mountpoint: "C:\\test\\directory"}, nil
 	}
 	return &native.Volume{Name: "test_volume", Mountpoint: "/test/volume"}, nil
 }
 
+func (mv *MockVolumeStore) MountWithoutLock(name string) error {
+	return nil
+}
+
 //nolint:unused
 var mockVolumeStore = &MockVolumeStore{}
@@ -32,11 +32,11 @@ func splitVolumeSpec(s string) ([]string, error) {
 	return split, nil
 }
 
-func handleVolumeToMount(source string, dst string, volStore volumestore.VolumeStore, createDir bool) (volumeSpec, error) {
+func handleVolumeToMount(source string, dst string, volStore volumestore.VolumeStore, createDir bool, opts ...volumestore.CreateOpt) (volumeSpec, error) {
 	switch {
 	// Handle named volumes
 	case isNamedVolume(source):
-		return handleNamedVolumes(source, volStore)
+		return handleNamedVolumes(source, volStore, opts...)
 
 	// Handle bind volumes (file paths)
 	default:
@@ -0,0 +1,98 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumedriver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containerd/errdefs"
+)
+
+var (
+	// PluginSockDirs are the directories where plugins listen on "<name>.sock" or "<name>/<name>.sock".
+	// Same as Docker.
+	PluginSockDirs = []string{"/run/docker/plugins"}
+	// PluginSpecDirs are the directories where plugins are registered with "<name>.spec" files,
+	// containing the address of the plugin (e.g. "unix:///run/foo.sock" or "tcp://localhost:8080"),
+	// or with "<name>.json" files, containing {"Name": "<name>", "Addr": "<address>"}.
+	// Same as Docker.
+	PluginSpecDirs = []string{"/etc/docker/plugins", "/usr/lib/docker/plugins"}
+)
+
+// lookupPlugin returns the address of the plugin with that name.
+func lookupPlugin(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid plugin name %q (%w)", name, errdefs.ErrInvalidArgument)
+	}
+	for _, dir := range PluginSockDirs {
+		for _, sock := range []string{filepath.Join(dir, name+".sock"), filepath.Join(dir, name, name+".sock")} {
+			if fi, err := os.Stat(sock); err == nil && fi.Mode()&os.ModeSocket != 0 {
+				return "unix://" + sock, nil
+			}
+		}
+	}
+	for _, dir := range PluginSpecDirs {
+		addr, err := readSpec(filepath.Join(dir, name+".spec"))
+		if err == nil {
+			return addr, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		addr, err = readJSONSpec(filepath.Join(dir, name+".json"))
+		if err == nil {
+			return addr, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("plugin %q not found in %v nor in %v: %w", name, PluginSockDirs, PluginSpecDirs, errdefs.ErrNotFound)
+}
+
+func readSpec(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	addr := strings.TrimSpace(string(b))
+	if addr == "" {
+		return "", fmt.Errorf("plugin spec %q is empty", path)
+	}
+	return addr, nil
+}
+
+func readJSONSpec(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var spec struct {
+		Name string
+		Addr string
+	}
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return "", fmt.Errorf("failed to parse plugin spec %q: %w", path, err)
+	}
+	if spec.Addr == "" {
+		return "", fmt.Errorf("plugin spec %q has no address", path)
+	}
+	return spec.Addr, nil
+}
@@ -0,0 +1,228 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumedriver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+const (
+	// pluginContentType is the content type of the requests and responses of the Docker plugin protocol
+	pluginContentType = "application/vnd.docker.plugins.v1.2+json"
+	// pluginTimeout is the timeout of a single call to a plugin, mounting a remote volume can take a while
+	pluginTimeout = 90 * time.Second
+	// volumeDriverInterface is the interface plugins implement to be used as volume drivers
+	volumeDriverInterface = "VolumeDriver"
+)
+
+// plugin is a volume driver implemented by an out-of-process plugin,
+// speaking the Docker volume plugin protocol (JSON over HTTP, usually over a unix socket).
+// See https://docs.docker.com/engine/extend/plugins_volume/
+type plugin struct {
+	name    string
+	baseURL string
+	client  *http.Client
+}
+
+func newPlugin(name, addr string) (*plugin, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q of plugin %q: %w", addr, name, err)
+	}
+	transport := &http.Transport{}
+	p := &plugin{
+		name: name,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   pluginTimeout,
+		},
+	}
+	switch u.Scheme {
+	case "unix":
+		sock := u.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", sock)
+		}
+		// The host is ignored when dialing the unix socket
+		p.baseURL = "http://plugin"
+	case "tcp":
+		p.baseURL = "http://" + u.Host
+	case "http", "https":
+		p.baseURL = u.Scheme + "://" + u.Host
+	default:
+		return nil, fmt.Errorf("unsupported address %q of plugin %q", addr, name)
+	}
+	return p, nil
+}
+
+// pluginResponse is embedded in all the responses of volume plugins
+type pluginResponse struct {
+	Err string `json:",omitempty"`
+}
+
+func (r *pluginResponse) err() error {
+	if r.Err != "" {
+		return errors.New(r.Err)
+	}
+	return nil
+}
+
+type pluginError interface {
+	err() error
+}
+
+// call invokes a method of the plugin, e.g. "VolumeDriver.Create".
+func (p *plugin) call(method string, req any, resp pluginError) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, p.baseURL+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Accept", pluginContentType)
+	httpReq.Header.Set("Content-Type", pluginContentType)
+	httpResp, err := p.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("volume plugin %q: %s: %w", p.name, method, err)
+	}
+	defer httpResp.Body.Close()
+	b, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("volume plugin %q: %s: %w", p.name, method, err)
+	}
+	if err := json.Unmarshal(b, resp); err != nil {
+		if httpResp.StatusCode != http.StatusOK {
+			return fmt.Errorf("volume plugin %q: %s: %s: %s", p.name, method, httpResp.Status, bytes.TrimSpace(b))
+		}
+		return fmt.Errorf("volume plugin %q: %s: failed to decode the response: %w", p.name, method, err)
+	}
+	if err := resp.err(); err != nil {
+		return fmt.Errorf("volume plugin %q: %s: %w", p.name, method, err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("volume plugin %q: %s: %s", p.name, method, httpResp.Status)
+	}
+	return nil
+}
+
+// activate performs the handshake with the plugin, and checks that it implements the volume driver interface.
+func (p *plugin) activate() error {
+	var resp struct {
+		pluginResponse
+		Implements []string
+	}
+	if err := p.call("Plugin.Activate", struct{}{}, &resp); err != nil {
+		return err
+	}
+	if !slices.Contains(resp.Implements, volumeDriverInterface) {
+		return fmt.Errorf("plugin %q does not implement %s (implements %v)", p.name, volumeDriverInterface, resp.Implements)
+	}
+	return nil
+}
+
+func (p *plugin) Name() string {
+	return p.name
+}
+
+func (p *plugin) Create(name string, opts map[string]string) error {
+	req := struct {
+		Name string
+		Opts map[string]string `json:",omitempty"`
+	}{name, opts}
+	var resp pluginResponse
+	return p.call("VolumeDriver.Create", req, &resp)
+}
+
+func (p *plugin) Remove(name string) error {
+	req := struct {
+		Name string
+	}{name}
+	var resp pluginResponse
+	return p.call("VolumeDriver.Remove", req, &resp)
+}
+
+func (p *plugin) Mount(name, id string) (string, error) {
+	req := struct {
+		Name string
+		ID   string
+	}{name, id}
+	var resp struct {
+		pluginResponse
+		Mountpoint string
+	}
+	if err := p.call("VolumeDriver.Mount", req, &resp); err != nil {
+		return "", err
+	}
+	if resp.Mountpoint == "" {
+		return "", fmt.Errorf("volume plugin %q: VolumeDriver.Mount: no mountpoint returned for volume %q", p.name, name)
+	}
+	return resp.Mountpoint, nil
+}
+
+func (p *plugin) Unmount(name, id string) error {
+	req := struct {
+		Name string
+		ID   string
+	}{name, id}
+	var resp pluginResponse
+	return p.call("VolumeDriver.Unmount", req, &resp)
+}
+
+func (p *plugin) Path(name string) (string, error) {
+	req := struct {
+		Name string
+	}{name}
+	var resp struct {
+		pluginResponse
+		Mountpoint string
+	}
+	if err := p.call("VolumeDriver.Path", req, &resp); err != nil {
+		return "", err
+	}
+	return resp.Mountpoint, nil
+}
+
+func (p *plugin) List() ([]string, error) {
+	var resp struct {
+		pluginResponse
+		Volumes []struct {
+			Name       string
+			Mountpoint string
+		}
+	}
+	if err := p.call("VolumeDriver.List", struct{}{}, &resp); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(resp.Volumes))
+	for i, vol := range resp.Volumes {
+		names[i] = vol.Name
+	}
+	return names, nil
+}
@@ -0,0 +1,174 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumedriver
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/errdefs"
+)
+
+// fakePlugin implements the volume plugin protocol, keeping track of the mounts by volume name.
+type fakePlugin struct {
+	mu      sync.Mutex
+	volumes map[string]map[string]struct{}
+}
+
+func (fp *fakePlugin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+	var req struct {
+		Name string
+		ID   string
+		Opts map[string]string
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	resp := map[string]any{}
+	switch r.URL.Path {
+	case "/Plugin.Activate":
+		resp["Implements"] = []string{"VolumeDriver"}
+	case "/VolumeDriver.Create":
+		if req.Opts["fail"] != "" {
+			resp["Err"] = req.Opts["fail"]
+			break
+		}
+		fp.volumes[req.Name] = map[string]struct{}{}
+	case "/VolumeDriver.Remove":
+		if len(fp.volumes[req.Name]) > 0 {
+			resp["Err"] = "volume is in use"
+			break
+		}
+		delete(fp.volumes, req.Name)
+	case "/VolumeDriver.Mount":
+		fp.volumes[req.Name][req.ID] = struct{}{}
+		resp["Mountpoint"] = "/mnt/" + req.Name
+	case "/VolumeDriver.Unmount":
+		delete(fp.volumes[req.Name], req.ID)
+	case "/VolumeDriver.Path":
+		resp["Mountpoint"] = "/mnt/" + req.Name
+	case "/VolumeDriver.List":
+		var vols []map[string]string
+		for name := range fp.volumes {
+			vols = append(vols, map[string]string{"Name": name})
+		}
+		resp["Volumes"] = vols
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", pluginContentType)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func startFakePlugin(t *testing.T, dir, name string) *fakePlugin {
+	l, err := net.Listen("unix", filepath.Join(dir, name+".sock"))
+	assert.NilError(t, err)
+	fp := &fakePlugin{volumes: make(map[string]map[string]struct{})}
+	srv := &http.Server{Handler: fp}
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	t.Cleanup(func() {
+		srv.Close()
+	})
+	return fp
+}
+
+func TestPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not supported")
+	}
+	// Unix socket paths are limited to ~100 characters, t.TempDir() may be too long
+	dir, err := os.MkdirTemp("", "plugins")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	oldSockDirs, oldSpecDirs := PluginSockDirs, PluginSpecDirs
+	PluginSockDirs, PluginSpecDirs = []string{dir}, []string{dir}
+	defer func() {
+		PluginSockDirs, PluginSpecDirs = oldSockDirs, oldSpecDirs
+	}()
+
+	fp := startFakePlugin(t, dir, "fake")
+
+	_, err = Get("notfound")
+	assert.ErrorIs(t, err, errdefs.ErrNotFound)
+
+	driver, err := Get("fake")
+	assert.NilError(t, err)
+	assert.Equal(t, driver.Name(), "fake")
+
+	assert.NilError(t, driver.Create("vol", map[string]string{"size": "1g"}))
+	assert.ErrorContains(t, driver.Create("broken", map[string]string{"fail": "no space left"}), "no space left")
+
+	names, err := driver.List()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, names, []string{"vol"})
+
+	mountpoint, err := driver.Mount("vol", "container1")
+	assert.NilError(t, err)
+	assert.Equal(t, mountpoint, "/mnt/vol")
+	fp.mu.Lock()
+	_, ok := fp.volumes["vol"]["container1"]
+	fp.mu.Unlock()
+	assert.Assert(t, ok)
+
+	path, err := driver.Path("vol")
+	assert.NilError(t, err)
+	assert.Equal(t, path, "/mnt/vol")
+
+	assert.ErrorContains(t, driver.Remove("vol"), "volume is in use")
+	assert.NilError(t, driver.Unmount("vol", "container1"))
+	assert.NilError(t, driver.Remove("vol"))
+
+	names, err = driver.List()
+	assert.NilError(t, err)
+	assert.Equal(t, len(names), 0)
+}
+
+func TestLookupPluginSpec(t *testing.T) {
+	dir := t.TempDir()
+
+	oldSockDirs, oldSpecDirs := PluginSockDirs, PluginSpecDirs
+	PluginSockDirs, PluginSpecDirs = []string{dir}, []string{dir}
+	defer func() {
+		PluginSockDirs, PluginSpecDirs = oldSockDirs, oldSpecDirs
+	}()
+
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "spec.spec"), []byte("tcp://localhost:8080\n"), 0o644))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "json.json"), []byte(`{"Name": "json", "Addr": "http://localhost:8081"}`), 0o644))
+
+	addr, err := lookupPlugin("spec")
+	assert.NilError(t, err)
+	assert.Equal(t, addr, "tcp://localhost:8080")
+
+	addr, err = lookupPlugin("json")
+	assert.NilError(t, err)
+	assert.Equal(t, addr, "http://localhost:8081")
+
+	_, err = lookupPlugin("../spec")
+	assert.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+}
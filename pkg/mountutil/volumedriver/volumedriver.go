@@ -0,0 +1,91 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package volumedriver implements volume drivers other than the built-in "local" driver of the volume store,
+// in particular out-of-process drivers speaking the Docker volume plugin protocol.
+//
+// The volume store keeps track of the volumes of all drivers (name, labels, driver and options), while the data of
+// the volumes is managed by the drivers.
+package volumedriver
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/containerd/errdefs"
+)
+
+// VolumeDriver manages the data of volumes.
+type VolumeDriver interface {
+	// Name returns the name of the driver
+	Name() string
+	// Create creates a volume, with driver specific options
+	Create(name string, opts map[string]string) error
+	// Remove removes a volume and its data
+	Remove(name string) error
+	// Mount makes a volume available to the caller identified by id (e.g. a container ID), and returns its path on the host.
+	// The same id has to be used to Unmount the volume.
+	Mount(name, id string) (string, error)
+	// Unmount releases a volume mounted by the caller identified by id
+	Unmount(name, id string) error
+	// Path returns the path of a mounted volume on the host
+	Path(name string) (string, error)
+	// List returns the names of the volumes known to the driver
+	List() ([]string, error)
+}
+
+var (
+	driversMu sync.Mutex
+	drivers   = make(map[string]VolumeDriver)
+)
+
+// Register makes an in-process volume driver available by its name.
+// Drivers registered this way take precedence over volume plugins with the same name.
+func Register(driver VolumeDriver) error {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if _, ok := drivers[driver.Name()]; ok {
+		return fmt.Errorf("volume driver %q is already registered (%w)", driver.Name(), errdefs.ErrAlreadyExists)
+	}
+	drivers[driver.Name()] = driver
+	return nil
+}
+
+// Get returns the volume driver registered with that name, or else connects to the volume plugin with that name.
+func Get(name string) (VolumeDriver, error) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if driver, ok := drivers[name]; ok {
+		return driver, nil
+	}
+	addr, err := lookupPlugin(name)
+	if err != nil {
+		if errors.Is(err, errdefs.ErrNotFound) {
+			return nil, fmt.Errorf("volume driver %q: %w", name, err)
+		}
+		return nil, err
+	}
+	plugin, err := newPlugin(name, addr)
+	if err != nil {
+		return nil, err
+	}
+	if err = plugin.activate(); err != nil {
+		return nil, err
+	}
+	drivers[name] = plugin
+	return plugin, nil
+}
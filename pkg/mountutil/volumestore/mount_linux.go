@@ -0,0 +1,77 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumestore
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+
+	mobymount "github.com/moby/sys/mount"
+
+	"github.com/containerd/containerd/v2/core/mount"
+)
+
+func mountVolume(opts map[string]string, target string) error {
+	if mounted, err := isMountpoint(target); err != nil {
+		return err
+	} else if mounted {
+		return nil
+	}
+
+	mountOpts := opts[OptionMountOptions]
+	switch opts[OptionType] {
+	case "nfs", "cifs":
+		// The kernel does not resolve host names, mount.nfs(8) and mount.cifs(8) normally do that for us
+		if addr := getAddress(mountOpts); addr != "" && net.ParseIP(addr) == nil {
+			ipAddr, err := net.ResolveIPAddr("ip", addr)
+			if err != nil {
+				return fmt.Errorf("failed to resolve %q: %w", addr, err)
+			}
+			mountOpts = strings.Replace(mountOpts, "addr="+addr, "addr="+ipAddr.String(), 1)
+		}
+	}
+
+	return mobymount.Mount(opts[OptionDevice], target, opts[OptionType], mountOpts)
+}
+
+func unmountVolume(target string) error {
+	// mobymount.Unmount ignores targets that are not mounted
+	return mobymount.Unmount(target)
+}
+
+func isMountpoint(dir string) (bool, error) {
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return false, err
+	}
+	info, err := mount.Lookup(dir)
+	if err != nil {
+		return false, err
+	}
+	return info.Mountpoint == dir, nil
+}
+
+func getAddress(opts string) string {
+	for _, opt := range strings.Split(opts, ",") {
+		if addr, ok := strings.CutPrefix(opt, "addr="); ok {
+			return addr
+		}
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package volumestore
+
+import (
+	"fmt"
+
+	"github.com/containerd/nerdctl/v2/pkg/store"
+)
+
+// LocalDriver is the name of the built-in volume driver, which stores the volume data in a directory
+// of the volume store, or mounts a filesystem there when the volume has options.
+const LocalDriver = "local"
+
+// CreateOpt allows configuring a volume at creation
+type CreateOpt func(*createOptions)
+
+type createOptions struct {
+	driver  string
+	options map[string]string
+}
+
+// WithDriver sets the driver of the volume, along with the driver specific options.
+// Options of the local driver are validated with ValidateOptions.
+func WithDriver(driver string, options map[string]string) CreateOpt {
+	return func(o *createOptions) {
+		if driver != "" {
+			o.driver = driver
+		}
+		o.options = options
+	}
+}
+
+func newCreateOptions(opts ...CreateOpt) (*createOptions, error) {
+	o := &createOptions{
+		driver: LocalDriver,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.driver == LocalDriver {
+		if err := ValidateOptions(o.options); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// Options of the local volume driver. They mirror the arguments of `mount -t <type> -o <o> <device> <mountpoint>`.
+const (
+	// OptionType is the filesystem type, e.g., "tmpfs", "nfs", "ext4", or "none" for a bind mount ("o=bind")
+	OptionType = "type"
+	// OptionDevice is the device to mount, e.g., "tmpfs", ":/exported/path" for nfs, "/dev/sdb1", or a host path
+	OptionDevice = "device"
+	// OptionMountOptions is the comma-separated list of mount options, e.g., "addr=10.0.0.1,rw" or "size=100m"
+	OptionMountOptions = "o"
+)
+
+// mandatoryOptions lists, for each option, the options that must be set along with it
+var mandatoryOptions = map[string][]string{
+	OptionType:         {OptionDevice},
+	OptionDevice:       {OptionType},
+	OptionMountOptions: {OptionType, OptionDevice},
+}
+
+// ValidateOptions checks the options of a volume of the local driver
+func ValidateOptions(opts map[string]string) error {
+	for opt := range opts {
+		required, ok := mandatoryOptions[opt]
+		if !ok {
+			return fmt.Errorf("invalid option: %q (%w)", opt, store.ErrInvalidArgument)
+		}
+		for _, req := range required {
+			if opts[req] == "" {
+				return fmt.Errorf("option %q requires option %q to be set (%w)", opt, req, store.ErrInvalidArgument)
+			}
+		}
+	}
+	return nil
+}
@@ -48,9 +48,9 @@ type VolumeStore interface {
 	// Get returns an existing volume
 	Get(name string, size bool) (*native.Volume, error)
 	// Create will either return an existing volume, or create a new one
-	// NOTE that different labels will NOT create a new volume if there is one by that name already,
-	// but instead return the existing one with the (possibly different) labels
-	Create(name string, labels []string) (vol *native.Volume, err error)
+	// NOTE that different labels or driver will NOT create a new volume if there is one by that name already,
+	// but instead return the existing one with the (possibly different) labels and driver
+	Create(name string, labels []string, opts ...CreateOpt) (vol *native.Volume, err error)
 	// List returns all existing volumes.
 	// Note that list is expensive as it reads all volumes individual info
 	List(size bool) (map[string]native.Volume, error)
@@ -67,7 +67,14 @@ type VolumeStore interface {
 	// This method does NOT lock (unlike Create).
 	// It is meant to be used between `Lock` and `Release`, and is specifically useful when multiple different volume
 	// creation will have to happen in different method calls (eg: container create).
-	CreateWithoutLock(name string, labels []string) (*native.Volume, error)
+	CreateWithoutLock(name string, labels []string, opts ...CreateOpt) (*native.Volume, error)
+	// MountWithoutLock mounts the filesystem described by the options of a local volume on its mountpoint,
+	// unless it is mounted already. This is a no-op for volumes created without options, or with another driver.
+	// Like CreateWithoutLock, it is meant to be used between `Lock` and `Release`.
+	MountWithoutLock(name string) error
+	// UnmountWithoutLock unmounts the filesystem of a volume created with options, if it is mounted.
+	// It is meant to be used between `Lock` and `Release`, after checking that no container is using the volume.
+	UnmountWithoutLock(name string) error
 	// Release: see store implementation
 	Release() error
 }
@@ -147,7 +154,7 @@ func (vs *volumeStore) Get(name string, size bool) (vol *native.Volume, err erro
 // volStore.Lock()
 // defer volStore.Release()
 // volStore.CreateWithoutLock(...)
-func (vs *volumeStore) CreateWithoutLock(name string, labels []string) (vol *native.Volume, err error) {
+func (vs *volumeStore) CreateWithoutLock(name string, labels []string, opts ...CreateOpt) (vol *native.Volume, err error) {
 	defer func() {
 		if err != nil {
 			err = errors.Join(ErrVolumeStore, err)
@@ -158,10 +165,15 @@ func (vs *volumeStore) CreateWithoutLock(name string, labels []string) (vol *nat
 		return nil, err
 	}
 
-	return vs.rawCreate(name, labels)
+	createOpts, err := newCreateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return vs.rawCreate(name, labels, createOpts)
 }
 
-func (vs *volumeStore) Create(name string, labels []string) (vol *native.Volume, err error) {
+func (vs *volumeStore) Create(name string, labels []string, opts ...CreateOpt) (vol *native.Volume, err error) {
 	defer func() {
 		if err != nil {
 			err = errors.Join(ErrVolumeStore, err)
@@ -172,14 +184,70 @@ func (vs *volumeStore) Create(name string, labels []string) (vol *native.Volume,
 		return nil, err
 	}
 
+	createOpts, err := newCreateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
 	err = vs.Locker.WithLock(func() error {
-		vol, err = vs.rawCreate(name, labels)
+		vol, err = vs.rawCreate(name, labels, createOpts)
 		return err
 	})
 
 	return vol, err
 }
 
+// MountWithoutLock mounts the filesystem of a volume created with options on its mountpoint.
+// Just like CreateWithoutLock, it does NOT lock for you, as it is meant to be called during container creation.
+func (vs *volumeStore) MountWithoutLock(name string) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Join(ErrVolumeStore, err)
+		}
+	}()
+
+	if err = identifiers.ValidateDockerCompat(name); err != nil {
+		return err
+	}
+
+	content, err := vs.manager.Get(name, volumeJSONFileName)
+	if err != nil {
+		return err
+	}
+
+	opts := options(content)
+	if driver(content) != LocalDriver || len(opts) == 0 {
+		return nil
+	}
+
+	target, err := vs.manager.Location(name, dataDirName)
+	if err != nil {
+		return err
+	}
+
+	if err = mountVolume(opts, target); err != nil {
+		return fmt.Errorf("failed to mount volume %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// UnmountWithoutLock unmounts the filesystem of a volume created with options.
+// It does NOT lock for you, as checking that the volume is no longer used has to happen inside the same lock.
+func (vs *volumeStore) UnmountWithoutLock(name string) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Join(ErrVolumeStore, err)
+		}
+	}()
+
+	if err = identifiers.ValidateDockerCompat(name); err != nil {
+		return err
+	}
+
+	return vs.rawUnmount(name)
+}
+
 func (vs *volumeStore) Count() (count int, err error) {
 	defer func() {
 		if err != nil {
@@ -256,13 +324,15 @@ func (vs *volumeStore) Remove(generator func() ([]string, []error, error)) (remo
 
 			// Erroring on Exists is a hard error
 			// !doesExist is a soft error
-			// Inability to delete is a hard error
+			// Inability to unmount or delete is a hard error
 			if doesExist, err := vs.manager.Exists(name); err != nil {
 				return err
 			} else if !doesExist {
 				// TODO: see above
 				warns = append(warns, fmt.Errorf("volume %q: %w", name, store.ErrNotFound))
 				continue
+			} else if err = vs.rawUnmount(name); err != nil {
+				return err
 			} else if err = vs.manager.Delete(name); err != nil {
 				return err
 			}
@@ -306,6 +376,10 @@ func (vs *volumeStore) Prune(filter func(vol []*native.Volume) ([]string, error)
 		}
 
 		for _, name := range toDelete {
+			// Never delete the content of a filesystem that is still mounted
+			if err = vs.rawUnmount(name); err != nil {
+				return err
+			}
 			err = vs.manager.Delete(name)
 			if err != nil {
 				return err
@@ -323,8 +397,10 @@ func (vs *volumeStore) rawGet(name string, size bool) (vol *native.Volume, err e
 	}
 
 	vol = &native.Volume{
-		Name:   name,
-		Labels: labels(content),
+		Name:    name,
+		Driver:  driver(content),
+		Labels:  labels(content),
+		Options: options(content),
 	}
 
 	vol.Mountpoint, err = vs.manager.Location(name, dataDirName)
@@ -342,21 +418,32 @@ func (vs *volumeStore) rawGet(name string, size bool) (vol *native.Volume, err e
 	return vol, nil
 }
 
-func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volume, err error) {
+func (vs *volumeStore) rawCreate(name string, labels []string, createOpts *createOptions) (vol *native.Volume, err error) {
 	volOpts := struct {
-		Labels map[string]string `json:"labels"`
+		Labels  map[string]string `json:"labels"`
+		Driver  string            `json:"driver,omitempty"`
+		Options map[string]string `json:"options,omitempty"`
 	}{}
 
 	if len(labels) > 0 {
 		volOpts.Labels = strutil.ConvertKVStringsToMap(labels)
 	}
 
+	if createOpts.driver != LocalDriver {
+		volOpts.Driver = createOpts.driver
+	}
+
+	if len(createOpts.options) > 0 {
+		volOpts.Options = createOpts.options
+	}
+
 	// Failure here must exit, no need to clean-up
 	labelsJSON, err := json.MarshalIndent(volOpts, "", "    ")
 	if err != nil {
 		return nil, err
 	}
 
+	content := labelsJSON
 	if doesExist, err := vs.manager.Exists(name, volumeJSONFileName); err != nil {
 		return nil, err
 	} else if !doesExist {
@@ -366,11 +453,16 @@ func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volu
 	} else {
 		log.L.Warnf("volume %q already exists and will be returned as-is", name)
 		// FIXME: we do not check if the existing volume has the same labels as requested - should we?
+		if content, err = vs.manager.Get(name, volumeJSONFileName); err != nil {
+			return nil, err
+		}
 	}
 
 	// At this point, we either have an existing volume, or created a new one successfully
 	vol = &native.Volume{
-		Name: name,
+		Name:    name,
+		Driver:  driver(content),
+		Options: options(content),
 	}
 
 	if err = vs.manager.GroupEnsure(name, dataDirName); err != nil {
@@ -384,6 +476,32 @@ func (vs *volumeStore) rawCreate(name string, labels []string) (vol *native.Volu
 	return vol, nil
 }
 
+func (vs *volumeStore) rawUnmount(name string) error {
+	content, err := vs.manager.Get(name, volumeJSONFileName)
+	if err != nil {
+		// A volume without metadata cannot have been mounted by us
+		if errors.Is(err, store.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if driver(content) != LocalDriver || len(options(content)) == 0 {
+		return nil
+	}
+
+	target, err := vs.manager.Location(name, dataDirName)
+	if err != nil {
+		return err
+	}
+
+	if err = unmountVolume(target); err != nil {
+		return fmt.Errorf("failed to unmount volume %q: %w", name, err)
+	}
+
+	return nil
+}
+
 // Private helpers
 func labels(b []byte) *map[string]string {
 	type volumeOpts struct {
@@ -395,3 +513,25 @@ func labels(b []byte) *map[string]string {
 	}
 	return vo.Labels
 }
+
+func options(b []byte) map[string]string {
+	type volumeOpts struct {
+		Options map[string]string `json:"options,omitempty"`
+	}
+	var vo volumeOpts
+	if err := json.Unmarshal(b, &vo); err != nil {
+		return nil
+	}
+	return vo.Options
+}
+
+func driver(b []byte) string {
+	type volumeOpts struct {
+		Driver string `json:"driver,omitempty"`
+	}
+	var vo volumeOpts
+	if err := json.Unmarshal(b, &vo); err != nil || vo.Driver == "" {
+		return LocalDriver
+	}
+	return vo.Driver
+}
@@ -0,0 +1,68 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package netutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containernetworking/cni/libcni"
+	types100 "github.com/containernetworking/cni/pkg/types/100"
+)
+
+// DefaultInterfacePrefix is the prefix of the names of the interfaces created by go-cni in the container
+// network namespace: the interface of the i-th network of the container is named "eth<i>".
+const DefaultInterfacePrefix = "eth"
+
+// InterfaceName returns the name of the interface of the i-th network of a container.
+func InterfaceName(i int) string {
+	return fmt.Sprintf("%s%d", DefaultInterfacePrefix, i)
+}
+
+// NextInterfaceName returns the first interface name that is not used by the given network:interface map.
+func NextInterfaceName(interfaces map[string]string) string {
+	used := make(map[string]struct{}, len(interfaces))
+	for _, ifName := range interfaces {
+		used[ifName] = struct{}{}
+	}
+	for i := 0; ; i++ {
+		if _, ok := used[InterfaceName(i)]; !ok {
+			return InterfaceName(i)
+		}
+	}
+}
+
+// AttachNetwork invokes CNI ADD for a single network.
+// Unlike go-cni, it allows choosing the name of the interface created in the container network namespace
+// through rt.IfName, so that networks can be attached to a container one by one.
+func (e *CNIEnv) AttachNetwork(ctx context.Context, network *NetworkConfig, rt *libcni.RuntimeConf) (*types100.Result, error) {
+	cniConfig := libcni.NewCNIConfig([]string{e.Path}, nil)
+	res, err := cniConfig.AddNetworkList(ctx, network.NetworkConfigList, rt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach network %q: %w", network.Name, err)
+	}
+	return types100.NewResultFromResult(res)
+}
+
+// DetachNetwork invokes CNI DEL for a single network, previously attached with the interface rt.IfName.
+func (e *CNIEnv) DetachNetwork(ctx context.Context, network *NetworkConfig, rt *libcni.RuntimeConf) error {
+	cniConfig := libcni.NewCNIConfig([]string{e.Path}, nil)
+	if err := cniConfig.DelNetworkList(ctx, network.NetworkConfigList, rt); err != nil {
+		return fmt.Errorf("failed to detach network %q: %w", network.Name, err)
+	}
+	return nil
+}
@@ -29,6 +29,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/containernetworking/cni/libcni"
 	types100 "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	b4nndclient "github.com/rootless-containers/bypass4netns/pkg/api/daemon/client"
@@ -116,7 +117,7 @@ func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetcon
 	}
 	defer filesystem.Unlock(lock)
 
-	opts, err := newHandlerOpts(&state, dataStore, cniPath, cniNetconfPath, bridgeIP)
+	opts, err := newHandlerOpts(&state, event, dataStore, cniPath, cniNetconfPath, bridgeIP)
 	if err != nil {
 		return err
 	}
@@ -131,7 +132,7 @@ func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetcon
 	}
 }
 
-func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, bridgeIP string) (*handlerOpts, error) {
+func newHandlerOpts(state *specs.State, event, dataStore, cniPath, cniNetconfPath, bridgeIP string) (*handlerOpts, error) {
 	o := &handlerOpts{
 		state:     state,
 		dataStore: dataStore,
@@ -186,11 +187,17 @@ func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, brid
 		var netw *netutil.NetworkConfig
 		for _, netstr := range networks {
 			if netw, err = e.NetworkByNameOrID(netstr); err != nil {
+				// A network disconnected with `nerdctl network disconnect` may have been removed since the container started
+				if event == "postStop" {
+					log.L.WithError(err).Warnf("skipping network %q", netstr)
+					continue
+				}
 				return nil, err
 			}
 			cniOpts = append(cniOpts, cni.WithConfListBytes(netw.Bytes))
 			o.cniNames = append(o.cniNames, netstr)
 		}
+		o.cniEnv = e
 		o.cni, err = cni.New(cniOpts...)
 		if err != nil {
 			return nil, err
@@ -208,6 +215,12 @@ func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath, brid
 		}
 	}
 
+	if aliasesJSON := o.state.Annotations[labels.NetworkAliases]; aliasesJSON != "" {
+		if err := json.Unmarshal([]byte(aliasesJSON), &o.networkAliases); err != nil {
+			return nil, err
+		}
+	}
+
 	if portsJSON := o.state.Annotations[labels.Ports]; portsJSON != "" {
 		if err := json.Unmarshal([]byte(portsJSON), &o.ports); err != nil {
 			return nil, err
@@ -256,6 +269,8 @@ type handlerOpts struct {
 	ports             []cni.PortMapping
 	cni               cni.CNI
 	cniNames          []string
+	cniEnv            *netutil.CNIEnv
+	networkAliases    map[string][]string // network:aliases
 	fullID            string
 	rootlessKitClient rlkclient.Client
 	bypassClient      b4nndclient.Client
@@ -463,6 +478,7 @@ func applyNetworkSettings(opts *handlerOpts) (err error) {
 		Domainname: opts.state.Annotations[labels.Domainname],
 		ExtraHosts: opts.extraHosts,
 		Name:       opts.state.Annotations[labels.Name],
+		Aliases:    opts.networkAliases,
 	}
 
 	// When containerd gets bounced, containers that were previously running and that are restarted will go again
@@ -549,6 +565,8 @@ func onCreateRuntime(opts *handlerOpts) error {
 	err = lf.Transform(func(lf *state.Store) error {
 		lf.StartedAt = time.Now()
 		lf.CreateError = netError != nil
+		// The networks connected or disconnected during the previous run are already reflected in the annotations
+		lf.Interfaces = nil
 		return nil
 	})
 	if err != nil {
@@ -564,7 +582,10 @@ func onPostStop(opts *handlerOpts) error {
 		return err
 	}
 
-	var shouldExit bool
+	var (
+		shouldExit bool
+		interfaces map[string]string
+	)
 	err = lf.Transform(func(lf *state.Store) error {
 		// See https://github.com/containerd/nerdctl/issues/3357
 		// Check if we actually errored during runtimeCreate
@@ -573,6 +594,8 @@ func onPostStop(opts *handlerOpts) error {
 		// Reset CreateError, and return.
 		shouldExit = lf.CreateError
 		lf.CreateError = false
+		interfaces = lf.Interfaces
+		lf.Interfaces = nil
 		return nil
 	})
 	if err != nil {
@@ -628,7 +651,14 @@ func onPostStop(opts *handlerOpts) error {
 		namespaceOpts = append(namespaceOpts, ipAddressOpts...)
 		namespaceOpts = append(namespaceOpts, macAddressOpts...)
 		namespaceOpts = append(namespaceOpts, ip6AddressOpts...)
-		if err := opts.cni.Remove(ctx, opts.fullID, "", namespaceOpts...); err != nil {
+		if interfaces != nil {
+			// The networks have been changed with `nerdctl network connect` or `nerdctl network disconnect`,
+			// so the interface names cannot be derived from the order of the networks anymore.
+			// The interfaces have been forgotten already, so this is a soft failure and the names are released anyway.
+			if err := removeInterfaces(ctx, opts, interfaces); err != nil {
+				log.L.WithError(err).Warnf("failed to remove the networks of container %s", opts.fullID)
+			}
+		} else if err := opts.cni.Remove(ctx, opts.fullID, "", namespaceOpts...); err != nil {
 			log.L.WithError(err).Errorf("failed to call cni.Remove")
 			return err
 		}
@@ -661,6 +691,31 @@ func onPostStop(opts *handlerOpts) error {
 	return nil
 }
 
+// removeInterfaces invokes CNI DEL for each network:interface of interfaces.
+// The networks that do not exist anymore are skipped, like in newHandlerOpts.
+func removeInterfaces(ctx context.Context, opts *handlerOpts, interfaces map[string]string) error {
+	var errs []error
+	for netstr, ifName := range interfaces {
+		netw, err := opts.cniEnv.NetworkByNameOrID(netstr)
+		if err != nil {
+			log.L.WithError(err).Warnf("skipping the removal of interface %s of network %q", ifName, netstr)
+			continue
+		}
+		rt := &libcni.RuntimeConf{
+			ContainerID: opts.fullID,
+			IfName:      ifName,
+			Args:        [][2]string{{"IgnoreUnknown", "1"}},
+		}
+		if len(opts.ports) > 0 {
+			rt.CapabilityArgs = map[string]interface{}{"portMappings": opts.ports}
+		}
+		if err := opts.cniEnv.DetachNetwork(ctx, netw, rt); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // cleanupIptablesRules cleans up iptables rules related to the container
 func cleanupIptablesRules(containerID string) error {
 	// Check if iptables command exists
@@ -58,6 +58,10 @@ type Store struct {
 	// StartedAt reflects the time at which we received the oci-hook onCreateRuntime event
 	StartedAt   time.Time `json:"started_at"`
 	CreateError bool      `json:"create_error"`
+	// Interfaces maps the CNI networks the running container is attached to, to the name of their interface
+	// in the container network namespace. It is reset by the onCreateRuntime event, and updated by
+	// `nerdctl network connect` and `nerdctl network disconnect`.
+	Interfaces map[string]string `json:"interfaces,omitempty"`
 }
 
 // Load will populate the struct with existing in-store lifecycle information
@@ -18,6 +18,7 @@ package portutil
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/containerd/nerdctl/v2/pkg/portutil/iptable"
 	"github.com/containerd/nerdctl/v2/pkg/portutil/procnet"
@@ -32,6 +33,8 @@ const (
 )
 
 var (
+	// allocateMu serializes the allocations, as containers may be created concurrently in the same process (e.g., by compose)
+	allocateMu    sync.Mutex
 	allocateStart = uint64(49153)
 )
 
@@ -45,6 +48,8 @@ func filter(ss []procnet.NetworkDetail, filterFunc func(detail procnet.NetworkDe
 }
 
 func portAllocate(protocol string, ip string, count uint64) (uint64, uint64, error) {
+	allocateMu.Lock()
+	defer allocateMu.Unlock()
 	usedPorts, err := getUsedPorts(ip, protocol)
 	if err != nil {
 		return 0, 0, err
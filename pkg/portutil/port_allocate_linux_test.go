@@ -0,0 +1,50 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package portutil
+
+import (
+	"os/exec"
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPortAllocateConcurrently(t *testing.T) {
+	if _, err := exec.LookPath("iptables"); err != nil {
+		t.Skip("iptables is required to list the used ports")
+	}
+	const n = 8
+	var wg sync.WaitGroup
+	starts := make([]uint64, n)
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			starts[i], _, errs[i] = portAllocate("tcp", "", 1)
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[uint64]bool)
+	for i := range n {
+		assert.NilError(t, errs[i])
+		assert.Assert(t, !seen[starts[i]], "port %d allocated twice", starts[i])
+		seen[starts[i]] = true
+	}
+}
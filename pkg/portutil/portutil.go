@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 
 	"github.com/docker/go-connections/nat"
@@ -139,6 +140,70 @@ func ParseFlagP(s string) ([]cni.PortMapping, error) {
 	return mr, nil
 }
 
+// ParseFlagExpose parses the ports exposed with --expose, like "80", "80/udp" and "8000-8010/tcp",
+// and returns them in the "80/tcp" form of the ExposedPorts of the image config.
+func ParseFlagExpose(s string) ([]string, error) {
+	proto, port := nat.SplitProtoPort(s)
+	proto = strings.ToLower(proto)
+	switch proto {
+	case "tcp", "udp", "sctp":
+	default:
+		return nil, fmt.Errorf("invalid protocol %q", proto)
+	}
+	startPort, endPort, err := nat.ParsePortRange(port)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port to expose: %s", s)
+	}
+	var res []string
+	for i := startPort; i <= endPort; i++ {
+		res = append(res, fmt.Sprintf("%d/%s", i, proto))
+	}
+	return res, nil
+}
+
+// PublishAll allocates host ports for the exposed ports, like "80" and "80/tcp",
+// that are not already published in the given port mappings.
+func PublishAll(exposed []string, published []cni.PortMapping) ([]cni.PortMapping, error) {
+	type portProto struct {
+		port  int32
+		proto string
+	}
+	seen := make(map[portProto]struct{})
+	for _, pm := range published {
+		seen[portProto{pm.ContainerPort, pm.Protocol}] = struct{}{}
+	}
+	var ports []string
+	for _, e := range exposed {
+		p, err := ParseFlagExpose(e)
+		if err != nil {
+			return nil, err
+		}
+		ports = append(ports, p...)
+	}
+	// allocate the host ports in a stable order
+	sort.Strings(ports)
+
+	var res []cni.PortMapping
+	for _, p := range ports {
+		proto, port := nat.SplitProtoPort(p)
+		containerPort, err := nat.ParsePort(port)
+		if err != nil {
+			return nil, err
+		}
+		key := portProto{int32(containerPort), proto}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		pm, err := ParseFlagP(p)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, pm...)
+	}
+	return res, nil
+}
+
 // ParsePortsLabel parses JSON-marshalled string from label map
 // (under `labels.Ports` key) and returns []cni.PortMapping.
 func ParsePortsLabel(labelMap map[string]string) ([]cni.PortMapping, error) {
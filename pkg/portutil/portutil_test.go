@@ -435,3 +435,62 @@ func TestParseFlagP(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFlagExpose(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "port",
+			s:    "80",
+			want: []string{"80/tcp"},
+		},
+		{
+			name: "port with protocol",
+			s:    "53/UDP",
+			want: []string{"53/udp"},
+		},
+		{
+			name: "range",
+			s:    "8000-8002/tcp",
+			want: []string{"8000/tcp", "8001/tcp", "8002/tcp"},
+		},
+		{
+			name:    "invalid protocol",
+			s:       "80/foo",
+			wantErr: true,
+		},
+		{
+			name:    "invalid port",
+			s:       "http",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFlagExpose(tt.s)
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestPublishAllSkipsPublishedPorts(t *testing.T) {
+	published := []cni.PortMapping{
+		{HostPort: 8080, ContainerPort: 80, Protocol: "tcp", HostIP: "0.0.0.0"},
+		{HostPort: 5353, ContainerPort: 53, Protocol: "udp", HostIP: "0.0.0.0"},
+	}
+	got, err := PublishAll([]string{"80", "53/udp"}, published)
+	assert.NilError(t, err)
+	assert.Equal(t, len(got), 0)
+
+	_, err = PublishAll([]string{"80/foo"}, published)
+	assert.ErrorContains(t, err, "invalid protocol")
+}
@@ -0,0 +1,218 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package cosign implements the key-based signing and verification of images in the format of cosign,
+// without the cosign executable.
+//
+// Signatures are pushed to the repository of the image, in a manifest tagged "sha256-<hex>.sig" (the cosign convention).
+// The manifest refers to the image with its subject field, so that it can also be discovered with the OCI 1.1 referrers API.
+package cosign
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// ErrNoMatchingSignatures is returned by Verify when no signature of the image is verified by the key.
+var ErrNoMatchingSignatures = errors.New("no matching signatures")
+
+// Sign signs the image `rawRef` with `key`, and pushes the signature to the repository of the image.
+// The signatures previously pushed for the image are kept.
+func Sign(ctx context.Context, rawRef string, key crypto.Signer, hostsDirs []string) error {
+	parsedReference, err := referenceutil.Parse(rawRef)
+	if err != nil {
+		return err
+	}
+	reg, err := newRegistry(ctx, parsedReference, hostsDirs)
+	if err != nil {
+		return err
+	}
+	subject, err := reg.resolve(ctx, parsedReference.String())
+	if err != nil {
+		return err
+	}
+
+	payload, err := newPayload(parsedReference.Name(), subject.Digest)
+	if err != nil {
+		return err
+	}
+	signature, err := signPayload(key, payload)
+	if err != nil {
+		return fmt.Errorf("failed to sign %s: %w", rawRef, err)
+	}
+	layer := ocispec.Descriptor{
+		MediaType: SimpleSigningMediaType,
+		Digest:    digest.FromBytes(payload),
+		Size:      int64(len(payload)),
+		Annotations: map[string]string{
+			SignatureAnnotation: base64.StdEncoding.EncodeToString(signature),
+		},
+	}
+
+	sigRef := reg.tagRef(signatureTag(subject.Digest))
+	existing, err := reg.fetchManifest(ctx, sigRef)
+	if err != nil && !errdefs.IsNotFound(err) {
+		return err
+	}
+	layers := append(existing.Layers, layer)
+
+	config, err := json.Marshal(ocispec.Image{
+		RootFS: ocispec.RootFS{
+			Type:    "layers",
+			DiffIDs: layerDigests(layers),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	configDesc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageConfig,
+		Digest:    digest.FromBytes(config),
+		Size:      int64(len(config)),
+	}
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: SignatureArtifactType,
+		Config:       configDesc,
+		Layers:       layers,
+		Subject: &ocispec.Descriptor{
+			MediaType: subject.MediaType,
+			Digest:    subject.Digest,
+			Size:      subject.Size,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	manifestDesc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromBytes(manifest),
+		Size:      int64(len(manifest)),
+	}
+
+	if err := reg.push(ctx, sigRef, layer, payload); err != nil {
+		return fmt.Errorf("failed to push the signature payload: %w", err)
+	}
+	if err := reg.push(ctx, sigRef, configDesc, config); err != nil {
+		return fmt.Errorf("failed to push the signature config: %w", err)
+	}
+	if err := reg.push(ctx, sigRef, manifestDesc, manifest); err != nil {
+		return fmt.Errorf("failed to push the signature manifest: %w", err)
+	}
+	log.G(ctx).Debugf("pushed the signature of %s to %s", parsedReference.String(), sigRef)
+	return nil
+}
+
+// Verify verifies that the image `rawRef` has a signature made with the private key of `key`.
+// It returns the digest of the verified image.
+//
+// The transparency log is not verified: the signatures are accepted without a rekor entry,
+// like `cosign verify --insecure-ignore-tlog`.
+func Verify(ctx context.Context, rawRef string, key crypto.PublicKey, hostsDirs []string) (digest.Digest, error) {
+	parsedReference, err := referenceutil.Parse(rawRef)
+	if err != nil {
+		return "", err
+	}
+	reg, err := newRegistry(ctx, parsedReference, hostsDirs)
+	if err != nil {
+		return "", err
+	}
+	subject, err := reg.resolve(ctx, parsedReference.String())
+	if err != nil {
+		return "", err
+	}
+
+	var manifests []ocispec.Manifest
+	sigRef := reg.tagRef(signatureTag(subject.Digest))
+	if manifest, err := reg.fetchManifest(ctx, sigRef); err == nil {
+		manifests = append(manifests, manifest)
+	} else if !errdefs.IsNotFound(err) {
+		return "", err
+	}
+	// Signatures not tagged, e.g., pushed by cosign with --registry-referrers-mode=oci-1-1
+	referrers, err := reg.referrers(ctx, subject.Digest, SignatureArtifactType)
+	if err != nil {
+		log.G(ctx).WithError(err).Debugf("failed to list the referrers of %s", subject.Digest)
+	}
+	for _, desc := range referrers {
+		manifest, err := reg.fetchManifest(ctx, reg.digestRef(desc.Digest))
+		if err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to fetch the signature manifest %s", desc.Digest)
+			continue
+		}
+		manifests = append(manifests, manifest)
+	}
+
+	var errs []error
+	for _, manifest := range manifests {
+		for _, layer := range manifest.Layers {
+			if layer.MediaType != SimpleSigningMediaType {
+				continue
+			}
+			err := verifyLayer(ctx, reg, layer, key, subject.Digest)
+			if err == nil {
+				log.G(ctx).Debugf("verified the signature %s of %s", layer.Digest, parsedReference.String())
+				return subject.Digest, nil
+			}
+			errs = append(errs, fmt.Errorf("signature %s: %w", layer.Digest, err))
+		}
+	}
+	if len(errs) == 0 {
+		return "", fmt.Errorf("%w: no signatures found for image %s", ErrNoMatchingSignatures, parsedReference.String())
+	}
+	return "", fmt.Errorf("%w: no valid signature found for image %s: %w", ErrNoMatchingSignatures, parsedReference.String(), errors.Join(errs...))
+}
+
+func verifyLayer(ctx context.Context, reg *registry, layer ocispec.Descriptor, key crypto.PublicKey, dgst digest.Digest) error {
+	encoded, ok := layer.Annotations[SignatureAnnotation]
+	if !ok {
+		return errors.New("no signature annotation")
+	}
+	signature, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	payload, err := reg.fetch(ctx, layer)
+	if err != nil {
+		return err
+	}
+	if err := verifyPayload(key, payload, signature); err != nil {
+		return err
+	}
+	return checkPayload(payload, dgst)
+}
+
+func layerDigests(layers []ocispec.Descriptor) []digest.Digest {
+	digests := make([]digest.Digest, len(layers))
+	for i, l := range layers {
+		digests[i] = l.Digest
+	}
+	return digests
+}
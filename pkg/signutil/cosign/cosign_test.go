@@ -0,0 +1,241 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cosign
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+)
+
+var (
+	uploadPath    = regexp.MustCompile(`^/v2/(.+)/blobs/uploads/(.*)$`)
+	referrersPath = regexp.MustCompile(`^/v2/(.+)/referrers/(.+)$`)
+	contentPath   = regexp.MustCompile(`^/v2/(.+)/(manifests|blobs)/(.+)$`)
+)
+
+// fakeRegistry is a minimal in-memory OCI distribution registry, with a single repository.
+type fakeRegistry struct {
+	mu        sync.Mutex
+	blobs     map[digest.Digest][]byte
+	manifests map[digest.Digest][]byte
+	tags      map[string]digest.Digest
+	referrers bool
+}
+
+func newFakeRegistry(referrers bool) *fakeRegistry {
+	return &fakeRegistry{
+		blobs:     map[digest.Digest][]byte{},
+		manifests: map[digest.Digest][]byte{},
+		tags:      map[string]digest.Digest{},
+		referrers: referrers,
+	}
+}
+
+func (r *fakeRegistry) putManifest(tag string, b []byte) digest.Digest {
+	dgst := digest.FromBytes(b)
+	r.manifests[dgst] = b
+	if tag != "" {
+		r.tags[tag] = dgst
+	}
+	return dgst
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if req.URL.Path == "/v2/" {
+		return
+	}
+	if m := uploadPath.FindStringSubmatch(req.URL.Path); m != nil {
+		switch req.Method {
+		case http.MethodPost:
+			w.Header().Set("Location", "/v2/"+m[1]+"/blobs/uploads/upload")
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodPut:
+			b, _ := io.ReadAll(req.Body)
+			dgst := digest.Digest(req.URL.Query().Get("digest"))
+			if digest.FromBytes(b) != dgst {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			r.blobs[dgst] = b
+			w.Header().Set("Docker-Content-Digest", dgst.String())
+			w.WriteHeader(http.StatusCreated)
+		}
+		return
+	}
+	if m := referrersPath.FindStringSubmatch(req.URL.Path); m != nil {
+		if !r.referrers {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		index := ocispec.Index{Versioned: specs.Versioned{SchemaVersion: 2}, MediaType: ocispec.MediaTypeImageIndex, Manifests: []ocispec.Descriptor{}}
+		for dgst, b := range r.manifests {
+			var manifest ocispec.Manifest
+			if json.Unmarshal(b, &manifest) == nil && manifest.Subject != nil && manifest.Subject.Digest.String() == m[2] {
+				index.Manifests = append(index.Manifests, ocispec.Descriptor{
+					MediaType:    manifest.MediaType,
+					ArtifactType: manifest.ArtifactType,
+					Digest:       dgst,
+					Size:         int64(len(b)),
+				})
+			}
+		}
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+		json.NewEncoder(w).Encode(index)
+		return
+	}
+	m := contentPath.FindStringSubmatch(req.URL.Path)
+	if m == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	ref := m[3]
+	if m[2] == "manifests" && req.Method == http.MethodPut {
+		b, _ := io.ReadAll(req.Body)
+		tag := ref
+		if strings.HasPrefix(ref, "sha256:") {
+			tag = ""
+		}
+		dgst := r.putManifest(tag, b)
+		w.Header().Set("Docker-Content-Digest", dgst.String())
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	var (
+		b   []byte
+		ok  bool
+		typ = "application/octet-stream"
+	)
+	if m[2] == "blobs" {
+		b, ok = r.blobs[digest.Digest(ref)]
+	} else {
+		dgst := digest.Digest(ref)
+		if tagged, isTag := r.tags[ref]; isTag {
+			dgst = tagged
+		}
+		b, ok = r.manifests[dgst]
+		typ = ocispec.MediaTypeImageManifest
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", typ)
+	w.Header().Set("Docker-Content-Digest", digest.FromBytes(b).String())
+	w.Header().Set("Content-Length", fmt.Sprint(len(b)))
+	if req.Method == http.MethodGet {
+		w.Write(b)
+	}
+}
+
+func setupRegistry(t *testing.T, referrers bool) (*fakeRegistry, string, digest.Digest) {
+	reg := newFakeRegistry(referrers)
+	srv := httptest.NewServer(reg)
+	t.Cleanup(srv.Close)
+	image, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+	})
+	assert.NilError(t, err)
+	dgst := reg.putManifest("v1", image)
+	return reg, strings.TrimPrefix(srv.URL, "http://") + "/foo", dgst
+}
+
+func newKeys(t *testing.T) (crypto.Signer, crypto.PublicKey) {
+	privPEM, pubPEM, err := GenerateKeyPair(nil)
+	assert.NilError(t, err)
+	priv, err := LoadPrivateKey(privPEM, nil)
+	assert.NilError(t, err)
+	pub, err := LoadPublicKey(pubPEM)
+	assert.NilError(t, err)
+	return priv, pub
+}
+
+func TestSignVerify(t *testing.T) {
+	ctx := context.Background()
+	reg, repo, dgst := setupRegistry(t, false)
+	priv, pub := newKeys(t)
+	_, otherPub := newKeys(t)
+
+	_, err := Verify(ctx, repo+":v1", pub, nil)
+	assert.ErrorContains(t, err, "no signatures found")
+
+	assert.NilError(t, Sign(ctx, repo+":v1", priv, nil))
+	verified, err := Verify(ctx, repo+":v1", pub, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, verified, dgst)
+
+	_, err = Verify(ctx, repo+":v1", otherPub, nil)
+	assert.ErrorContains(t, err, "no valid signature found")
+
+	// Signing again keeps the previous signatures
+	assert.NilError(t, Sign(ctx, repo+"@"+dgst.String(), priv, nil))
+	var manifest ocispec.Manifest
+	assert.NilError(t, json.Unmarshal(reg.manifests[reg.tags[signatureTag(dgst)]], &manifest))
+	assert.Equal(t, len(manifest.Layers), 2)
+	assert.Equal(t, manifest.Subject.Digest, dgst)
+	assert.Equal(t, manifest.ArtifactType, SignatureArtifactType)
+}
+
+func TestVerifyReferrers(t *testing.T) {
+	ctx := context.Background()
+	reg, repo, dgst := setupRegistry(t, true)
+	priv, pub := newKeys(t)
+
+	// A signature discoverable only with the referrers API, like the ones of `cosign sign --registry-referrers-mode=oci-1-1`
+	payload, err := newPayload(repo, dgst)
+	assert.NilError(t, err)
+	signature, err := signPayload(priv, payload)
+	assert.NilError(t, err)
+	reg.blobs[digest.FromBytes(payload)] = payload
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: SignatureArtifactType,
+		Config:       ocispec.DescriptorEmptyJSON,
+		Layers: []ocispec.Descriptor{{
+			MediaType:   SimpleSigningMediaType,
+			Digest:      digest.FromBytes(payload),
+			Size:        int64(len(payload)),
+			Annotations: map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(signature)},
+		}},
+		Subject: &ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: dgst},
+	})
+	assert.NilError(t, err)
+	reg.putManifest("", manifest)
+
+	verified, err := Verify(ctx, repo+":v1", pub, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, verified, dgst)
+}
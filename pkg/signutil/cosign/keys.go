@@ -0,0 +1,208 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cosign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// SigstorePrivateKeyPemType is the PEM type of the private keys generated by cosign.
+	SigstorePrivateKeyPemType = "ENCRYPTED SIGSTORE PRIVATE KEY"
+	// CosignPrivateKeyPemType is the PEM type of the private keys generated by older versions of cosign.
+	CosignPrivateKeyPemType = "ENCRYPTED COSIGN PRIVATE KEY"
+	// PublicKeyPemType is the PEM type of the public keys.
+	PublicKeyPemType = "PUBLIC KEY"
+
+	kdfName    = "scrypt"
+	cipherName = "nacl/secretbox"
+	// Same scrypt parameters as cosign
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+	// Upper bound of the scrypt N parameter of keys we decrypt, to avoid exhausting the memory
+	maxScryptN = 1 << 20
+	keyLen     = 32
+	saltLen    = 32
+	nonceLen   = 24
+)
+
+// encryptedKey is the JSON envelope of encrypted cosign private keys
+// (the format of github.com/secure-systems-lab/go-securesystemslib/encrypted).
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// GenerateKeyPair generates an ECDSA P-256 key pair, compatible with `cosign generate-key-pair`.
+// It returns the PEM-encoded private key, encrypted with `password`, and the PEM-encoded public key.
+func GenerateKeyPair(password []byte) (privateKeyPEM []byte, publicKeyPEM []byte, err error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	encrypted, err := encrypt(der, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(priv.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKeyPEM = pem.EncodeToMemory(&pem.Block{Type: SigstorePrivateKeyPemType, Bytes: encrypted})
+	publicKeyPEM = pem.EncodeToMemory(&pem.Block{Type: PublicKeyPemType, Bytes: pubDER})
+	return privateKeyPEM, publicKeyPEM, nil
+}
+
+// LoadPrivateKey decrypts a PEM-encoded cosign private key with `password`.
+func LoadPrivateKey(b []byte, password []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("invalid private key: no PEM block found")
+	}
+	if block.Type != SigstorePrivateKeyPemType && block.Type != CosignPrivateKeyPemType {
+		return nil, fmt.Errorf("unsupported private key PEM type %q", block.Type)
+	}
+	der, err := decrypt(block.Bytes, password)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey, *rsa.PrivateKey, ed25519.PrivateKey:
+		return k.(crypto.Signer), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// LoadPublicKey parses a PEM-encoded public key.
+func LoadPublicKey(b []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("invalid public key: no PEM block found")
+	}
+	if block.Type != PublicKeyPemType {
+		return nil, fmt.Errorf("unsupported public key PEM type %q", block.Type)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+func encrypt(plaintext, password []byte) ([]byte, error) {
+	var ek encryptedKey
+	ek.KDF.Name = kdfName
+	ek.KDF.Params.N = scryptN
+	ek.KDF.Params.R = scryptR
+	ek.KDF.Params.P = scryptP
+	ek.KDF.Salt = make([]byte, saltLen)
+	if _, err := io.ReadFull(rand.Reader, ek.KDF.Salt); err != nil {
+		return nil, err
+	}
+	ek.Cipher.Name = cipherName
+	ek.Cipher.Nonce = make([]byte, nonceLen)
+	if _, err := io.ReadFull(rand.Reader, ek.Cipher.Nonce); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(password, ek.KDF.Salt, scryptN, scryptR, scryptP, keyLen)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		k     [keyLen]byte
+		nonce [nonceLen]byte
+	)
+	copy(k[:], key)
+	copy(nonce[:], ek.Cipher.Nonce)
+	ek.Ciphertext = secretbox.Seal(nil, plaintext, &nonce, &k)
+	return json.Marshal(ek)
+}
+
+func decrypt(b, password []byte) ([]byte, error) {
+	var ek encryptedKey
+	if err := json.Unmarshal(b, &ek); err != nil {
+		return nil, fmt.Errorf("invalid encrypted private key: %w", err)
+	}
+	if ek.KDF.Name != kdfName {
+		return nil, fmt.Errorf("unsupported key derivation function %q", ek.KDF.Name)
+	}
+	if ek.Cipher.Name != cipherName {
+		return nil, fmt.Errorf("unsupported cipher %q", ek.Cipher.Name)
+	}
+	if p := ek.KDF.Params; p.N <= 0 || p.N > maxScryptN || p.R <= 0 || p.P <= 0 {
+		return nil, fmt.Errorf("unsupported scrypt parameters N=%d, r=%d, p=%d", p.N, p.R, p.P)
+	}
+	if len(ek.Cipher.Nonce) != nonceLen {
+		return nil, fmt.Errorf("invalid nonce length %d", len(ek.Cipher.Nonce))
+	}
+	key, err := scrypt.Key(password, ek.KDF.Salt, ek.KDF.Params.N, ek.KDF.Params.R, ek.KDF.Params.P, keyLen)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		k     [keyLen]byte
+		nonce [nonceLen]byte
+	)
+	copy(k[:], key)
+	copy(nonce[:], ek.Cipher.Nonce)
+	plaintext, ok := secretbox.Open(nil, ek.Ciphertext, &nonce, &k)
+	if !ok {
+		return nil, errors.New("failed to decrypt the private key: wrong password?")
+	}
+	return plaintext, nil
+}
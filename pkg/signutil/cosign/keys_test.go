@@ -0,0 +1,54 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cosign
+
+import (
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+)
+
+func TestKeyPair(t *testing.T) {
+	privPEM, pubPEM, err := GenerateKeyPair([]byte("secret"))
+	assert.NilError(t, err)
+
+	_, err = LoadPrivateKey(privPEM, []byte("wrong"))
+	assert.ErrorContains(t, err, "failed to decrypt")
+	_, err = LoadPrivateKey(pubPEM, []byte("secret"))
+	assert.ErrorContains(t, err, "unsupported private key PEM type")
+
+	priv, err := LoadPrivateKey(privPEM, []byte("secret"))
+	assert.NilError(t, err)
+	pub, err := LoadPublicKey(pubPEM)
+	assert.NilError(t, err)
+
+	dgst := digest.FromString("image")
+	payload, err := newPayload("example.com/foo", dgst)
+	assert.NilError(t, err)
+	signature, err := signPayload(priv, payload)
+	assert.NilError(t, err)
+	assert.NilError(t, verifyPayload(pub, payload, signature))
+	assert.NilError(t, checkPayload(payload, dgst))
+	assert.ErrorContains(t, checkPayload(payload, digest.FromString("other")), "the signature is for the image")
+
+	_, otherPubPEM, err := GenerateKeyPair(nil)
+	assert.NilError(t, err)
+	otherPub, err := LoadPublicKey(otherPubPEM)
+	assert.NilError(t, err)
+	assert.ErrorContains(t, verifyPayload(otherPub, payload, signature), "invalid signature")
+}
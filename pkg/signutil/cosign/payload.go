@@ -0,0 +1,107 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cosign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/opencontainers/go-digest"
+)
+
+const (
+	// SimpleSigningMediaType is the media type of the signed payloads.
+	SimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// SignatureArtifactType is the artifact type of the signature manifests.
+	SignatureArtifactType = "application/vnd.dev.cosign.artifact.sig.v1+json"
+	// SignatureAnnotation is the annotation of the payload layers holding the base64-encoded signature.
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	simpleSigningType = "cosign container image signature"
+)
+
+// simpleSigning is the payload signed by cosign ("Red Hat simple signing" format).
+type simpleSigning struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]any `json:"optional"`
+}
+
+func newPayload(repository string, dgst digest.Digest) ([]byte, error) {
+	var p simpleSigning
+	p.Critical.Identity.DockerReference = repository
+	p.Critical.Image.DockerManifestDigest = dgst.String()
+	p.Critical.Type = simpleSigningType
+	return json.Marshal(p)
+}
+
+// checkPayload checks that `payload` is a signature of the image `dgst`.
+func checkPayload(payload []byte, dgst digest.Digest) error {
+	var p simpleSigning
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("invalid signature payload: %w", err)
+	}
+	if p.Critical.Type != simpleSigningType {
+		return fmt.Errorf("invalid signature payload type %q", p.Critical.Type)
+	}
+	if p.Critical.Image.DockerManifestDigest != dgst.String() {
+		return fmt.Errorf("the signature is for the image %s, not %s", p.Critical.Image.DockerManifestDigest, dgst)
+	}
+	return nil
+}
+
+func signPayload(key crypto.Signer, payload []byte) ([]byte, error) {
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		return key.Sign(rand.Reader, payload, crypto.Hash(0))
+	}
+	hash := sha256.Sum256(payload)
+	// ECDSA keys produce ASN.1 signatures, RSA keys PKCS #1 v1.5 signatures, like cosign
+	return key.Sign(rand.Reader, hash[:], crypto.SHA256)
+}
+
+func verifyPayload(key crypto.PublicKey, payload, signature []byte) error {
+	hash := sha256.Sum256(payload)
+	var ok bool
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		ok = ecdsa.VerifyASN1(k, hash[:], signature)
+	case *rsa.PublicKey:
+		ok = rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], signature) == nil
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(k, payload, signature)
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+	if !ok {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/containerd/v2/core/remotes/docker"
+	dockerconfig "github.com/containerd/containerd/v2/core/remotes/docker/config"
+	"github.com/containerd/containerd/v2/pkg/reference"
+	"github.com/containerd/errdefs"
+
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// maxManifestSize is the maximum size of the manifests and payloads read from the registry.
+const maxManifestSize = 4 << 20
+
+// registry accesses the repository of an image, using the hosts.toml configuration and the credentials of nerdctl.
+type registry struct {
+	ref      *referenceutil.ImageReference
+	hosts    docker.RegistryHosts
+	resolver remotes.Resolver
+}
+
+func newRegistry(ctx context.Context, ref *referenceutil.ImageReference, hostsDirs []string) (*registry, error) {
+	ho, err := dockerconfigresolver.NewHostOptions(ctx, ref.Domain, dockerconfigresolver.WithHostsDirs(hostsDirs))
+	if err != nil {
+		return nil, err
+	}
+	hosts := dockerconfig.ConfigureHosts(ctx, *ho)
+	return &registry{
+		ref:      ref,
+		hosts:    hosts,
+		resolver: docker.NewResolver(docker.ResolverOptions{Hosts: hosts}),
+	}, nil
+}
+
+// tagRef returns the reference of `tag` in the repository.
+func (r *registry) tagRef(tag string) string {
+	return r.ref.Name() + ":" + tag
+}
+
+// digestRef returns the reference of `dgst` in the repository.
+func (r *registry) digestRef(dgst digest.Digest) string {
+	return r.ref.Name() + "@" + dgst.String()
+}
+
+func (r *registry) resolve(ctx context.Context, ref string) (ocispec.Descriptor, error) {
+	_, desc, err := r.resolver.Resolve(ctx, ref)
+	return desc, err
+}
+
+func (r *registry) fetch(ctx context.Context, desc ocispec.Descriptor) ([]byte, error) {
+	if desc.Size > maxManifestSize {
+		return nil, fmt.Errorf("%s is too large (%d bytes)", desc.Digest, desc.Size)
+	}
+	fetcher, err := r.resolver.Fetcher(ctx, r.digestRef(desc.Digest))
+	if err != nil {
+		return nil, err
+	}
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(io.LimitReader(rc, desc.Size+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) != desc.Size || digest.FromBytes(b) != desc.Digest {
+		return nil, fmt.Errorf("content of %s does not match its descriptor", desc.Digest)
+	}
+	return b, nil
+}
+
+// fetchManifest fetches the manifest `ref` (a tag or digest reference).
+func (r *registry) fetchManifest(ctx context.Context, ref string) (ocispec.Manifest, error) {
+	desc, err := r.resolve(ctx, ref)
+	if err != nil {
+		return ocispec.Manifest{}, err
+	}
+	b, err := r.fetch(ctx, desc)
+	if err != nil {
+		return ocispec.Manifest{}, err
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return ocispec.Manifest{}, fmt.Errorf("invalid manifest %s: %w", ref, err)
+	}
+	return manifest, nil
+}
+
+// push pushes `data` to the repository. Manifests are pushed to the tag of `ref`.
+func (r *registry) push(ctx context.Context, ref string, desc ocispec.Descriptor, data []byte) error {
+	pusher, err := r.resolver.Pusher(ctx, ref)
+	if err != nil {
+		return err
+	}
+	w, err := pusher.Push(ctx, desc)
+	if err != nil {
+		if errdefs.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	defer w.Close()
+	if _, err := io.Copy(w, bytes.NewReader(data)); err != nil {
+		return err
+	}
+	if err := w.Commit(ctx, desc.Size, desc.Digest); err != nil && !errdefs.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// referrers lists the manifests referring to `dgst` with the artifact type `artifactType`,
+// using the referrers API of OCI distribution 1.1.
+// It returns an error wrapping errdefs.ErrNotImplemented if the registry does not support the referrers API.
+func (r *registry) referrers(ctx context.Context, dgst digest.Digest, artifactType string) ([]ocispec.Descriptor, error) {
+	refspec, err := reference.Parse(r.digestRef(dgst))
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = docker.ContextWithRepositoryScope(ctx, refspec, false)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := r.hosts(refspec.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, host := range hosts {
+		if host.Capabilities&docker.HostCapabilityResolve == 0 {
+			continue
+		}
+		descs, err := fetchReferrers(ctx, host, r.ref.Path, dgst, artifactType)
+		if err == nil {
+			return descs, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", host.Host, err))
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no host to list the referrers of %s: %w", dgst, errdefs.ErrNotImplemented)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func fetchReferrers(ctx context.Context, host docker.RegistryHost, repository string, dgst digest.Digest, artifactType string) ([]ocispec.Descriptor, error) {
+	u := url.URL{
+		Scheme:   host.Scheme,
+		Host:     host.Host,
+		Path:     path.Join(host.Path, repository, "referrers", dgst.String()),
+		RawQuery: url.Values{"artifactType": []string{artifactType}}.Encode(),
+	}
+	client := host.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	for retry := true; ; retry = false {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range host.Header {
+			req.Header[k] = v
+		}
+		req.Header.Set("Accept", ocispec.MediaTypeImageIndex)
+		if host.Authorizer != nil {
+			if err := host.Authorizer.Authorize(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && retry && host.Authorizer != nil {
+			resp.Body.Close()
+			if err := host.Authorizer.AddResponses(ctx, []*http.Response{resp}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		defer resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusOK:
+		case http.StatusNotFound:
+			// Registries supporting the referrers API return an empty index when there is no referrer
+			return nil, fmt.Errorf("referrers API not supported: %w", errdefs.ErrNotImplemented)
+		default:
+			return nil, fmt.Errorf("unexpected status listing the referrers of %s: %s", dgst, resp.Status)
+		}
+		var index ocispec.Index
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&index); err != nil {
+			return nil, fmt.Errorf("invalid referrers index: %w", err)
+		}
+		// The registry may ignore the artifactType filter
+		var descs []ocispec.Descriptor
+		for _, desc := range index.Manifests {
+			if desc.ArtifactType == artifactType {
+				descs = append(descs, desc)
+			}
+		}
+		return descs, nil
+	}
+}
+
+// signatureTag returns the tag of the signatures of the image `dgst`, e.g., "sha256-<hex>.sig".
+func signatureTag(dgst digest.Digest) string {
+	return strings.Replace(dgst.String(), ":", "-", 1) + ".sig"
+}
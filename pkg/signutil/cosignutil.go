@@ -20,17 +20,41 @@ import (
 	"bufio"
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 
+	"golang.org/x/term"
+
 	"github.com/containerd/log"
 
+	"github.com/containerd/nerdctl/v2/pkg/errutil"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/signutil/cosign"
 )
 
 // SignCosign signs an image(`rawRef`) using a cosign private key (`keyRef`)
-func SignCosign(rawRef string, keyRef string) error {
+// `hostsDirs` are used to resolve image `rawRef` and to push the signature.
+// Private key files are handled natively, KMS URIs and keyless mode require the cosign executable.
+func SignCosign(ctx context.Context, rawRef string, keyRef string, hostsDirs []string) error {
+	if IsCosignKeyFile(keyRef) {
+		b, err := os.ReadFile(keyRef)
+		if err != nil {
+			return err
+		}
+		password, err := cosignPassword()
+		if err != nil {
+			return err
+		}
+		key, err := cosign.LoadPrivateKey(b, password)
+		if err != nil {
+			return fmt.Errorf("failed to load cosign private key %q: %w", keyRef, err)
+		}
+		log.G(ctx).Debugf("signing image: %s", rawRef)
+		return cosign.Sign(ctx, rawRef, key, hostsDirs)
+	}
+
 	cosignExecutable, err := exec.LookPath("cosign")
 	if err != nil {
 		log.L.WithError(err).Error("cosign executable not found in path $PATH")
@@ -61,11 +85,16 @@ func SignCosign(rawRef string, keyRef string) error {
 	return cosignCmd.Wait()
 }
 
+// cosignNoMatchingSignaturesExitCode is the exit code of `cosign verify` when no signature is verified.
+const cosignNoMatchingSignaturesExitCode = 12
+
 // VerifyCosign verifies an image(`rawRef`) with a cosign public key(`keyRef`)
 // `hostsDirs` are used to resolve image `rawRef`
 // Either --cosign-certificate-identity or --cosign-certificate-identity-regexp and either --cosign-certificate-oidc-issuer or --cosign-certificate-oidc-issuer-regexp must be set for keyless flows.
+// The transparency log is verified by the cosign executable, unless `ignoreTlog` is set.
+// Key files are verified natively only when `ignoreTlog` is set, as nerdctl does not verify the transparency log.
 func VerifyCosign(ctx context.Context, rawRef string, keyRef string, hostsDirs []string,
-	certIdentity string, certIdentityRegexp string, certOidcIssuer string, certOidcIssuerRegexp string) (string, error) {
+	certIdentity string, certIdentityRegexp string, certOidcIssuer string, certOidcIssuerRegexp string, ignoreTlog bool) (string, error) {
 	digest, err := imgutil.ResolveDigest(ctx, rawRef, false, hostsDirs)
 	if err != nil {
 		log.G(ctx).WithError(err).Errorf("unable to resolve digest for an image %s: %v", rawRef, err)
@@ -78,10 +107,34 @@ func VerifyCosign(ctx context.Context, rawRef string, keyRef string, hostsDirs [
 
 	log.G(ctx).Debugf("verifying image: %s", ref)
 
+	if IsCosignKeyFile(keyRef) && ignoreTlog {
+		b, err := os.ReadFile(keyRef)
+		if err != nil {
+			return ref, err
+		}
+		key, err := cosign.LoadPublicKey(b)
+		if err != nil {
+			return ref, fmt.Errorf("failed to load cosign public key %q: %w", keyRef, err)
+		}
+		if _, err := cosign.Verify(ctx, ref, key, hostsDirs); err != nil {
+			if errors.Is(err, cosign.ErrNoMatchingSignatures) {
+				// exit with the same code as `cosign verify`
+				log.G(ctx).WithError(err).Error("failed to verify the cosign signature")
+				return ref, errutil.NewExitCoderErr(cosignNoMatchingSignaturesExitCode)
+			}
+			return ref, err
+		}
+		return ref, nil
+	}
+
 	cosignExecutable, err := exec.LookPath("cosign")
 	if err != nil {
 		log.G(ctx).WithError(err).Error("cosign executable not found in path $PATH")
 		log.G(ctx).Info("you might consider installing cosign from: https://docs.sigstore.dev/cosign/installation")
+		if IsCosignKeyFile(keyRef) {
+			log.G(ctx).Info("the transparency log is only verified by the cosign executable, " +
+				"use --cosign-insecure-ignore-tlog to verify the signatures of the key file without it")
+		}
 		return ref, err
 	}
 
@@ -112,6 +165,9 @@ func VerifyCosign(ctx context.Context, rawRef string, keyRef string, hostsDirs [
 		}
 		cosignCmd.Env = append(cosignCmd.Env, "COSIGN_EXPERIMENTAL=true")
 	}
+	if ignoreTlog {
+		cosignCmd.Args = append(cosignCmd.Args, "--insecure-ignore-tlog=true")
+	}
 
 	cosignCmd.Args = append(cosignCmd.Args, ref)
 
@@ -128,6 +184,27 @@ func VerifyCosign(ctx context.Context, rawRef string, keyRef string, hostsDirs [
 	return ref, nil
 }
 
+// IsCosignKeyFile returns true if `keyRef` is the path of a key file, as opposed to
+// a KMS URI (e.g. "awskms://...", "k8s://..."), a PKCS #11 URI, or empty (keyless mode).
+func IsCosignKeyFile(keyRef string) bool {
+	return keyRef != "" && !strings.Contains(keyRef, "://") && !strings.HasPrefix(keyRef, "pkcs11:")
+}
+
+// cosignPassword returns the password of cosign private keys, from $COSIGN_PASSWORD like cosign,
+// or from the terminal.
+func cosignPassword() ([]byte, error) {
+	if password, ok := os.LookupEnv("COSIGN_PASSWORD"); ok {
+		return []byte(password), nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, errors.New("COSIGN_PASSWORD must be set to decrypt the cosign private key")
+	}
+	fmt.Fprint(os.Stderr, "Enter password for private key: ")
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	return password, err
+}
+
 func processCosignIO(cosignCmd *exec.Cmd) error {
 	stdout, err := cosignCmd.StdoutPipe()
 	if err != nil {
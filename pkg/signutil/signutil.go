@@ -26,14 +26,15 @@ import (
 )
 
 // Sign signs an image using a signer and options provided in options.
-func Sign(rawRef string, experimental bool, options types.ImageSignOptions) error {
+// `hostsDirs` are used to resolve image `rawRef` and to push the signature.
+func Sign(ctx context.Context, rawRef string, hostsDirs []string, experimental bool, options types.ImageSignOptions) error {
 	switch options.Provider {
 	case "cosign":
 		if !experimental {
 			return fmt.Errorf("cosign only work with enable experimental feature")
 		}
 
-		if err := SignCosign(rawRef, options.CosignKey); err != nil {
+		if err := SignCosign(ctx, rawRef, options.CosignKey, hostsDirs); err != nil {
 			return err
 		}
 	case "notation":
@@ -60,7 +61,7 @@ func Verify(ctx context.Context, rawRef string, hostsDirs []string, experimental
 			return "", fmt.Errorf("cosign only work with enable experimental feature")
 		}
 
-		if ref, err = VerifyCosign(ctx, rawRef, options.CosignKey, hostsDirs, options.CosignCertificateIdentity, options.CosignCertificateIdentityRegexp, options.CosignCertificateOidcIssuer, options.CosignCertificateOidcIssuerRegexp); err != nil {
+		if ref, err = VerifyCosign(ctx, rawRef, options.CosignKey, hostsDirs, options.CosignCertificateIdentity, options.CosignCertificateIdentityRegexp, options.CosignCertificateOidcIssuer, options.CosignCertificateOidcIssuerRegexp, options.CosignInsecureIgnoreTlog); err != nil {
 			return "", err
 		}
 	case "notation":
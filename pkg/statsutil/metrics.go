@@ -0,0 +1,131 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statsutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Sample holds the cumulative statistics of a container, as exported by `nerdctl system metrics serve`.
+// Unlike StatsEntry, it holds raw values rather than rates, so that they can be aggregated by the monitoring system.
+type Sample struct {
+	// CPU time, in seconds
+	CPUUsage  float64
+	CPUUser   float64
+	CPUSystem float64
+	// Memory usage (excluding the inactive file cache) and limit, in bytes
+	Memory      float64
+	MemoryLimit float64
+	// Bytes received and transmitted on the network interfaces
+	NetworkRx float64
+	NetworkTx float64
+	// Bytes read from and written to block devices
+	BlockRead  float64
+	BlockWrite float64
+	// Number of processes and threads
+	Pids uint64
+}
+
+// MetricLabels identify the container of a Sample.
+type MetricLabels struct {
+	ID             string
+	Name           string
+	Namespace      string
+	Image          string
+	ComposeProject string
+}
+
+// LabeledSample is a Sample of the container identified by Labels.
+type LabeledSample struct {
+	Labels MetricLabels
+	Sample
+}
+
+// MetricsContentType is the content type of the Prometheus text exposition format written by WriteMetrics.
+const MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type metricDef struct {
+	name  string
+	help  string
+	typ   string
+	value func(*Sample) float64
+}
+
+var metricDefs = []metricDef{
+	{"nerdctl_container_cpu_usage_seconds_total", "Total CPU time consumed by the container.", "counter",
+		func(s *Sample) float64 { return s.CPUUsage }},
+	{"nerdctl_container_cpu_user_seconds_total", "CPU time consumed by the container in user mode.", "counter",
+		func(s *Sample) float64 { return s.CPUUser }},
+	{"nerdctl_container_cpu_system_seconds_total", "CPU time consumed by the container in kernel mode.", "counter",
+		func(s *Sample) float64 { return s.CPUSystem }},
+	{"nerdctl_container_memory_usage_bytes", "Memory usage of the container, excluding the inactive file cache.", "gauge",
+		func(s *Sample) float64 { return s.Memory }},
+	{"nerdctl_container_memory_limit_bytes", "Memory limit of the container, or the memory of the host when unlimited.", "gauge",
+		func(s *Sample) float64 { return s.MemoryLimit }},
+	{"nerdctl_container_network_receive_bytes_total", "Bytes received by the container on its network interfaces.", "counter",
+		func(s *Sample) float64 { return s.NetworkRx }},
+	{"nerdctl_container_network_transmit_bytes_total", "Bytes transmitted by the container on its network interfaces.", "counter",
+		func(s *Sample) float64 { return s.NetworkTx }},
+	{"nerdctl_container_blkio_read_bytes_total", "Bytes read by the container from block devices.", "counter",
+		func(s *Sample) float64 { return s.BlockRead }},
+	{"nerdctl_container_blkio_write_bytes_total", "Bytes written by the container to block devices.", "counter",
+		func(s *Sample) float64 { return s.BlockWrite }},
+	{"nerdctl_container_pids", "Number of processes and threads in the container.", "gauge",
+		func(s *Sample) float64 { return float64(s.Pids) }},
+}
+
+// WriteMetrics writes the samples in the Prometheus text exposition format.
+func WriteMetrics(w io.Writer, samples []LabeledSample) error {
+	bw := bufio.NewWriter(w)
+	for _, def := range metricDefs {
+		fmt.Fprintf(bw, "# HELP %s %s\n", def.name, def.help)
+		fmt.Fprintf(bw, "# TYPE %s %s\n", def.name, def.typ)
+		for i := range samples {
+			fmt.Fprintf(bw, "%s{%s} %s\n", def.name, formatLabels(samples[i].Labels),
+				strconv.FormatFloat(def.value(&samples[i].Sample), 'f', -1, 64))
+		}
+	}
+	return bw.Flush()
+}
+
+func formatLabels(l MetricLabels) string {
+	pairs := []struct{ name, value string }{
+		{"id", l.ID},
+		{"name", l.Name},
+		{"namespace", l.Namespace},
+		{"image", l.Image},
+		{"compose_project", l.ComposeProject},
+	}
+	var sb strings.Builder
+	for i, p := range pairs {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(p.name)
+		sb.WriteString("=\"")
+		sb.WriteString(labelValueReplacer.Replace(p.value))
+		sb.WriteByte('"')
+	}
+	return sb.String()
+}
+
+// labelValueReplacer escapes label values, see https://prometheus.io/docs/instrumenting/exposition_formats/
+var labelValueReplacer = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n")
@@ -0,0 +1,107 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statsutil
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	v1 "github.com/containerd/cgroups/v3/cgroup1/stats"
+	v2 "github.com/containerd/cgroups/v3/cgroup2/stats"
+
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+)
+
+// NewSample returns the Sample of the cgroup metrics `anydata` (*v1.Metrics or *v2.Metrics) and the network links `links`.
+func NewSample(anydata any, links []netlink.Link) (Sample, error) {
+	var s Sample
+	switch metrics := anydata.(type) {
+	case *v1.Metrics:
+		if metrics.CPU != nil && metrics.CPU.Usage != nil {
+			s.CPUUsage = float64(metrics.CPU.Usage.Total) / 1e9
+			s.CPUUser = float64(metrics.CPU.Usage.User) / 1e9
+			s.CPUSystem = float64(metrics.CPU.Usage.Kernel) / 1e9
+		}
+		if metrics.Memory != nil && metrics.Memory.Usage != nil {
+			s.Memory = calculateCgroupMemUsage(metrics)
+			s.MemoryLimit = getCgroupMemLimit(float64(metrics.Memory.Usage.Limit))
+		}
+		if metrics.Blkio != nil {
+			blkRead, blkWrite := calculateCgroupBlockIO(metrics)
+			s.BlockRead, s.BlockWrite = float64(blkRead), float64(blkWrite)
+		}
+		if metrics.Pids != nil {
+			s.Pids = metrics.Pids.Current
+		}
+	case *v2.Metrics:
+		if metrics.CPU != nil {
+			s.CPUUsage = float64(metrics.CPU.UsageUsec) / 1e6
+			s.CPUUser = float64(metrics.CPU.UserUsec) / 1e6
+			s.CPUSystem = float64(metrics.CPU.SystemUsec) / 1e6
+		}
+		if metrics.Memory != nil {
+			s.Memory = calculateCgroup2MemUsage(metrics)
+			s.MemoryLimit = getCgroupMemLimit(float64(metrics.Memory.UsageLimit))
+		}
+		if metrics.Io != nil {
+			ioRead, ioWrite := calculateCgroup2IO(metrics)
+			s.BlockRead, s.BlockWrite = float64(ioRead), float64(ioWrite)
+		}
+		if metrics.Pids != nil {
+			s.Pids = metrics.Pids.Current
+		}
+	default:
+		return s, errors.New("cannot convert metric data to cgroups.Metrics")
+	}
+	s.NetworkRx, s.NetworkTx = calculateCgroupNetwork(links)
+	return s, nil
+}
+
+// NetworkLinks returns the links of the network interfaces `interfaces` in the network namespace of the process `pid`,
+// excluding the loopback and inactive interfaces.
+func NetworkLinks(pid int, interfaces []native.NetInterface) ([]netlink.Link, error) {
+	ns, err := netns.GetFromPid(pid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the statistics in netns of pid %d: %w", pid, err)
+	}
+	defer ns.Close()
+
+	nlHandle, err := netlink.NewHandleAt(ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the statistics in netns %s: %w", ns, err)
+	}
+	defer nlHandle.Close()
+
+	var links []netlink.Link
+	for _, v := range interfaces {
+		link, err := nlHandle.LinkByIndex(v.Index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the statistics for %s in netns %s: %w", v.Name, ns, err)
+		}
+		attrs := link.Attrs()
+		if attrs.Flags&net.FlagUp == 0 || attrs.Flags&net.FlagLoopback != 0 || strings.HasPrefix(attrs.Name, "lo") {
+			continue
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
@@ -0,0 +1,70 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statsutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	samples := []LabeledSample{
+		{
+			Labels: MetricLabels{
+				ID:             "0123456789ab",
+				Name:           "web-1",
+				Namespace:      "default",
+				Image:          "docker.io/library/nginx:alpine",
+				ComposeProject: "demo",
+			},
+			Sample: Sample{
+				CPUUsage:    1.5,
+				Memory:      1048576,
+				MemoryLimit: 2147483648,
+				NetworkRx:   1024,
+				Pids:        3,
+			},
+		},
+		{
+			Labels: MetricLabels{
+				ID:        "ba9876543210",
+				Name:      `quote"back\slash`,
+				Namespace: "k8s.io",
+			},
+		},
+	}
+	var buf bytes.Buffer
+	assert.NilError(t, WriteMetrics(&buf, samples))
+	out := buf.String()
+
+	for _, expected := range []string{
+		"# HELP nerdctl_container_cpu_usage_seconds_total Total CPU time consumed by the container.\n",
+		"# TYPE nerdctl_container_cpu_usage_seconds_total counter\n",
+		"# TYPE nerdctl_container_memory_usage_bytes gauge\n",
+		`nerdctl_container_cpu_usage_seconds_total{id="0123456789ab",name="web-1",namespace="default",image="docker.io/library/nginx:alpine",compose_project="demo"} 1.5` + "\n",
+		`nerdctl_container_memory_limit_bytes{id="0123456789ab",name="web-1",namespace="default",image="docker.io/library/nginx:alpine",compose_project="demo"} 2147483648` + "\n",
+		`nerdctl_container_pids{id="0123456789ab",name="web-1",namespace="default",image="docker.io/library/nginx:alpine",compose_project="demo"} 3` + "\n",
+		`nerdctl_container_pids{id="ba9876543210",name="quote\"back\\slash",namespace="k8s.io",image="",compose_project=""} 0` + "\n",
+	} {
+		assert.Assert(t, strings.Contains(out, expected), "expected %q in:\n%s", expected, out)
+	}
+	// Each metric has a HELP and a TYPE line, and one series per sample
+	assert.Equal(t, strings.Count(out, "\n"), len(metricDefs)*(2+len(samples)))
+}
@@ -35,16 +35,63 @@ type SystemInfo struct {
 type StatsEntry struct {
 	Name             string
 	ID               string
+	Read             time.Time
 	CPUPercentage    float64
 	Memory           float64
 	MemoryLimit      float64
 	MemoryPercentage float64
+	MemoryStats      MemoryStats
 	NetworkRx        float64
 	NetworkTx        float64
+	Networks         map[string]NetworkStats `json:",omitempty"`
 	BlockRead        float64
 	BlockWrite       float64
 	PidsCurrent      uint64
-	IsInvalid        bool
+	// Pressure is only available on cgroup v2 with PSI enabled in the kernel
+	Pressure  *PressureStats `json:",omitempty"`
+	IsInvalid bool
+}
+
+// MemoryStats is the breakdown of the memory used by a container, in bytes
+type MemoryStats struct {
+	Anon   float64
+	File   float64
+	Kernel float64
+	Swap   float64
+}
+
+// NetworkStats represents the counters of a network interface of a container
+type NetworkStats struct {
+	RxBytes   uint64
+	RxPackets uint64
+	RxErrors  uint64
+	RxDropped uint64
+	TxBytes   uint64
+	TxPackets uint64
+	TxErrors  uint64
+	TxDropped uint64
+}
+
+// PressureStats represents the pressure stall information (PSI) of a container
+type PressureStats struct {
+	CPU    *Pressure `json:",omitempty"`
+	Memory *Pressure `json:",omitempty"`
+	IO     *Pressure `json:",omitempty"`
+}
+
+// Pressure represents the share of time some (resp. all) the tasks of a container were stalled on a resource
+type Pressure struct {
+	Some *PressureData `json:",omitempty"`
+	Full *PressureData `json:",omitempty"`
+}
+
+// PressureData holds the percentages of stalled time over the last 10, 60 and 300 seconds,
+// and the total stalled time in microseconds
+type PressureData struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  uint64
 }
 
 // FormattedStatsEntry represents a formatted StatsEntry
@@ -57,6 +104,10 @@ type FormattedStatsEntry struct {
 	NetIO    string
 	BlockIO  string
 	PIDs     string
+	// Pressure stall information, not shown in the table output
+	CPUPressure string
+	MemPressure string
+	IOPressure  string
 }
 
 // Stats represents an entity to store containers statistics synchronously
@@ -112,8 +163,11 @@ func (cs *Stats) SetErrorAndReset(err error) {
 	cs.Memory = 0
 	cs.MemoryPercentage = 0
 	cs.MemoryLimit = 0
+	cs.MemoryStats = MemoryStats{}
 	cs.NetworkRx = 0
 	cs.NetworkTx = 0
+	cs.Networks = nil
+	cs.Pressure = nil
 	cs.BlockRead = 0
 	cs.BlockWrite = 0
 	cs.PidsCurrent = 0
@@ -142,6 +196,10 @@ func RenderEntry(in *StatsEntry, noTrunc bool) FormattedStatsEntry {
 		NetIO:    in.NetIO(),
 		BlockIO:  in.BlockIO(),
 		PIDs:     in.PIDs(),
+
+		CPUPressure: in.CPUPressure(),
+		MemPressure: in.MemPressure(),
+		IOPressure:  in.IOPressure(),
 	}
 }
 
@@ -216,3 +274,32 @@ func (s *StatsEntry) PIDs() string {
 	}
 	return strconv.FormatUint(s.PidsCurrent, 10)
 }
+
+func (s *StatsEntry) CPUPressure() string {
+	if s.IsInvalid || s.Pressure == nil {
+		return "--"
+	}
+	return s.Pressure.CPU.String()
+}
+
+func (s *StatsEntry) MemPressure() string {
+	if s.IsInvalid || s.Pressure == nil {
+		return "--"
+	}
+	return s.Pressure.Memory.String()
+}
+
+func (s *StatsEntry) IOPressure() string {
+	if s.IsInvalid || s.Pressure == nil {
+		return "--"
+	}
+	return s.Pressure.IO.String()
+}
+
+// String returns the share of time some tasks were stalled over the last 10 seconds, e.g., "1.25%"
+func (p *Pressure) String() string {
+	if p == nil || p.Some == nil {
+		return "--"
+	}
+	return fmt.Sprintf("%.2f%%", p.Some.Avg10)
+}
@@ -48,12 +48,15 @@ func SetCgroupStatsFields(previousStats *ContainerStats, data *v1.Metrics, links
 	netRx, netTx := calculateCgroupNetwork(links)
 
 	return StatsEntry{
+		Read:             time.Now(),
 		CPUPercentage:    cpuPercent,
 		Memory:           mem,
 		MemoryPercentage: memPercent,
 		MemoryLimit:      memLimit,
+		MemoryStats:      getCgroupMemStats(data),
 		NetworkRx:        netRx,
 		NetworkTx:        netTx,
+		Networks:         getNetworkStats(links),
 		BlockRead:        float64(blkRead),
 		BlockWrite:       float64(blkWrite),
 		PidsCurrent:      pidsStatsCurrent,
@@ -71,15 +74,19 @@ func SetCgroup2StatsFields(previousStats *ContainerStats, metrics *v2.Metrics, l
 	netRx, netTx := calculateCgroupNetwork(links)
 
 	return StatsEntry{
+		Read:             time.Now(),
 		CPUPercentage:    cpuPercent,
 		Memory:           mem,
 		MemoryPercentage: memPercent,
 		MemoryLimit:      memLimit,
+		MemoryStats:      getCgroup2MemStats(metrics),
 		NetworkRx:        netRx,
 		NetworkTx:        netTx,
+		Networks:         getNetworkStats(links),
 		BlockRead:        float64(blkRead),
 		BlockWrite:       float64(blkWrite),
 		PidsCurrent:      pidsStatsCurrent,
+		Pressure:         getCgroup2Pressure(metrics),
 	}, nil
 
 }
@@ -212,3 +219,96 @@ func calculateCgroupNetwork(links []netlink.Link) (float64, float64) {
 	}
 	return rx, tx
 }
+
+func getNetworkStats(links []netlink.Link) map[string]NetworkStats {
+	if len(links) == 0 {
+		return nil
+	}
+	networks := make(map[string]NetworkStats, len(links))
+	for _, l := range links {
+		stats := l.Attrs().Statistics
+		if stats == nil {
+			continue
+		}
+		networks[l.Attrs().Name] = NetworkStats{
+			RxBytes:   stats.RxBytes,
+			RxPackets: stats.RxPackets,
+			RxErrors:  stats.RxErrors,
+			RxDropped: stats.RxDropped,
+			TxBytes:   stats.TxBytes,
+			TxPackets: stats.TxPackets,
+			TxErrors:  stats.TxErrors,
+			TxDropped: stats.TxDropped,
+		}
+	}
+	return networks
+}
+
+func getCgroupMemStats(metrics *v1.Metrics) MemoryStats {
+	m := metrics.Memory
+	stats := MemoryStats{
+		Anon: float64(m.TotalRSS),
+		File: float64(m.TotalCache),
+	}
+	if m.Kernel != nil {
+		stats.Kernel = float64(m.Kernel.Usage)
+	}
+	// memory.memsw.usage_in_bytes is the sum of the memory and swap usage
+	if m.Swap != nil && m.Usage != nil && m.Swap.Usage > m.Usage.Usage {
+		stats.Swap = float64(m.Swap.Usage - m.Usage.Usage)
+	}
+	return stats
+}
+
+func getCgroup2MemStats(metrics *v2.Metrics) MemoryStats {
+	m := metrics.Memory
+	return MemoryStats{
+		Anon:   float64(m.Anon),
+		File:   float64(m.File),
+		Kernel: float64(m.KernelStack + m.Slab + m.Sock),
+		Swap:   float64(m.SwapUsage),
+	}
+}
+
+func getCgroup2Pressure(metrics *v2.Metrics) *PressureStats {
+	var cpu, mem, io *v2.PSIStats
+	if metrics.CPU != nil {
+		cpu = metrics.CPU.PSI
+	}
+	if metrics.Memory != nil {
+		mem = metrics.Memory.PSI
+	}
+	if metrics.Io != nil {
+		io = metrics.Io.PSI
+	}
+	if cpu == nil && mem == nil && io == nil {
+		return nil
+	}
+	return &PressureStats{
+		CPU:    convertPSI(cpu),
+		Memory: convertPSI(mem),
+		IO:     convertPSI(io),
+	}
+}
+
+func convertPSI(psi *v2.PSIStats) *Pressure {
+	if psi == nil {
+		return nil
+	}
+	return &Pressure{
+		Some: convertPSIData(psi.Some),
+		Full: convertPSIData(psi.Full),
+	}
+}
+
+func convertPSIData(data *v2.PSIData) *PressureData {
+	if data == nil {
+		return nil
+	}
+	return &PressureData{
+		Avg10:  data.Avg10,
+		Avg60:  data.Avg60,
+		Avg300: data.Avg300,
+		Total:  data.Total,
+	}
+}
@@ -0,0 +1,90 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statsutil
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	v2 "github.com/containerd/cgroups/v3/cgroup2/stats"
+)
+
+func TestSetCgroup2StatsFields(t *testing.T) {
+	metrics := &v2.Metrics{
+		CPU: &v2.CPUStat{
+			UsageUsec: 1000,
+			PSI: &v2.PSIStats{
+				Some: &v2.PSIData{Avg10: 1.5, Avg60: 0.5, Avg300: 0.1, Total: 12345},
+			},
+		},
+		Memory: &v2.MemoryStat{
+			Usage:        4096 * 10,
+			UsageLimit:   4096 * 100,
+			InactiveFile: 4096 * 2,
+			Anon:         4096 * 6,
+			File:         4096 * 3,
+			KernelStack:  4096,
+			Slab:         4096 * 2,
+			Sock:         4096,
+			SwapUsage:    4096 * 5,
+			PSI: &v2.PSIStats{
+				Some: &v2.PSIData{Avg10: 20},
+				Full: &v2.PSIData{Avg10: 10},
+			},
+		},
+		Io:   &v2.IOStat{},
+		Pids: &v2.PidsStat{Current: 3},
+	}
+	entry, err := SetCgroup2StatsFields(&ContainerStats{Time: time.Now()}, metrics, nil)
+	assert.NilError(t, err)
+
+	assert.Equal(t, entry.Memory, float64(4096*8))
+	assert.Equal(t, entry.MemoryLimit, float64(4096*100))
+	assert.DeepEqual(t, entry.MemoryStats, MemoryStats{
+		Anon:   4096 * 6,
+		File:   4096 * 3,
+		Kernel: 4096 * 4,
+		Swap:   4096 * 5,
+	})
+	assert.Equal(t, entry.PidsCurrent, uint64(3))
+
+	assert.Assert(t, entry.Pressure != nil)
+	assert.DeepEqual(t, entry.Pressure.CPU, &Pressure{
+		Some: &PressureData{Avg10: 1.5, Avg60: 0.5, Avg300: 0.1, Total: 12345},
+	})
+	assert.Equal(t, entry.Pressure.Memory.Full.Avg10, 10.0)
+	assert.Assert(t, entry.Pressure.IO == nil)
+	assert.Equal(t, entry.CPUPressure(), "1.50%")
+	assert.Equal(t, entry.MemPressure(), "20.00%")
+	assert.Equal(t, entry.IOPressure(), "--")
+}
+
+func TestSetCgroup2StatsFieldsWithoutPSI(t *testing.T) {
+	metrics := &v2.Metrics{
+		CPU:    &v2.CPUStat{},
+		Memory: &v2.MemoryStat{},
+		Io:     &v2.IOStat{},
+		Pids:   &v2.PidsStat{},
+	}
+	entry, err := SetCgroup2StatsFields(&ContainerStats{Time: time.Now()}, metrics, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, entry.Pressure == nil)
+	assert.Assert(t, entry.Networks == nil)
+	assert.Equal(t, entry.CPUPressure(), "--")
+}
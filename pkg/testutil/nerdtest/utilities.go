@@ -33,6 +33,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+	"github.com/containerd/nerdctl/v2/pkg/signutil/cosign"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
 )
 
@@ -165,17 +166,10 @@ func GenerateCosignKeyPair(data test.Data, helpers test.Helpers, password string
 	path := "cosign-key-pair"
 	data.Temp().Dir(path)
 
-	pass, message := require.Binary("cosign").Check(data, helpers)
-	if !pass {
-		helpers.T().Skip(message)
-	}
-
-	cmd := helpers.Custom("cosign", "generate-key-pair")
-	cmd.WithCwd(data.Temp().Path(path))
-	cmd.Setenv("COSIGN_PASSWORD", password)
-	cmd.Run(&test.Expected{})
+	priPEM, pubPEM, err := cosign.GenerateKeyPair([]byte(password))
+	assert.NilError(helpers.T(), err)
 
-	return data.Temp().Path(path, "cosign.key"), data.Temp().Path(path, "cosign.pub")
+	return data.Temp().Save(string(priPEM), path, "cosign.key"), data.Temp().Save(string(pubPEM), path, "cosign.pub")
 }
 
 func FindIPv6(output string) net.IP {
@@ -0,0 +1,116 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package trustpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/containerd/log"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+	"github.com/containerd/nerdctl/v2/pkg/signutil"
+)
+
+// ErrDenied is returned when the trust policy denies an image.
+var ErrDenied = errors.New("denied by the image trust policy")
+
+// Enforce enforces the trust policy configured in globalOptions (if any) on the image `rawRef`.
+// It returns the reference to pull, which is pinned to the verified digest when signatures were verified.
+func Enforce(ctx context.Context, rawRef string, globalOptions types.GlobalCommandOptions) (string, error) {
+	if globalOptions.TrustPolicy == "" {
+		return rawRef, nil
+	}
+	p, err := Load(globalOptions.TrustPolicy)
+	if err != nil {
+		return "", err
+	}
+	return p.Enforce(ctx, rawRef, globalOptions.HostsDir, globalOptions.InsecureRegistry)
+}
+
+// Enforce enforces the policy on the image `rawRef`, see the package-level Enforce.
+// `hostsDirs` and `insecure` are used to resolve the image and to fetch its signatures.
+func (p *Policy) Enforce(ctx context.Context, rawRef string, hostsDirs []string, insecure bool) (string, error) {
+	parsedReference, err := referenceutil.Parse(rawRef)
+	if err != nil {
+		return "", err
+	}
+
+	scope, reqs := "", p.Default
+	if parsedReference.Protocol == "" {
+		tagOrDigest := ""
+		if parsedReference.Digest != "" {
+			tagOrDigest = "@" + parsedReference.Digest.String()
+		} else if parsedReference.Tag != "" {
+			tagOrDigest = ":" + parsedReference.Tag
+		}
+		scope, reqs = p.Match(TransportDocker, parsedReference.Name(), tagOrDigest)
+	}
+	denied := func(err error) error {
+		if scope == "" {
+			return fmt.Errorf("%w: image %s (default requirements): %w", ErrDenied, parsedReference.String(), err)
+		}
+		return fmt.Errorf("%w: image %s (scope %q): %w", ErrDenied, parsedReference.String(), scope, err)
+	}
+
+	ref := rawRef
+	for _, r := range reqs {
+		switch r.Type {
+		case TypeReject:
+			return "", denied(errors.New("rejected"))
+		case TypeInsecureAcceptAnything:
+			continue
+		}
+		if parsedReference.Protocol != "" {
+			return "", denied(fmt.Errorf("requirement %q cannot be verified for images on %s", r.Type, parsedReference.Protocol))
+		}
+		// Pin the digest once, so that all the requirements are verified against the same manifest,
+		// which is also the one pulled afterwards.
+		if !strings.Contains(ref, "@") {
+			dgst, err := imgutil.ResolveDigest(ctx, rawRef, insecure, hostsDirs)
+			if err != nil {
+				return "", err
+			}
+			ref = rawRef + "@" + dgst
+		}
+		log.G(ctx).Debugf("verifying the trust policy requirement %q for %s", r.Type, ref)
+		if err := r.verify(ctx, ref, hostsDirs); err != nil {
+			return "", denied(err)
+		}
+	}
+	return ref, nil
+}
+
+func (r *Requirement) verify(ctx context.Context, ref string, hostsDirs []string) error {
+	var err error
+	switch r.Type {
+	case TypeSignedBy:
+		_, err = signutil.VerifyCosign(ctx, ref, r.KeyPath, hostsDirs, "", "", "", "", true)
+	case TypeSigstoreSigned:
+		_, err = signutil.VerifyCosign(ctx, ref, r.KeyPath, hostsDirs,
+			r.CertificateIdentity, r.CertificateIdentityRegexp, r.CertificateOidcIssuer, r.CertificateOidcIssuerRegexp, r.InsecureIgnoreTlog)
+	case TypeNotationSigned:
+		_, err = signutil.VerifyNotation(ctx, ref, hostsDirs)
+	default:
+		return fmt.Errorf("unknown requirement type %q", r.Type)
+	}
+	return err
+}
@@ -0,0 +1,213 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package trustpolicy implements the image trust policy (`trust_policy` in nerdctl.toml),
+// a policy.json file that declares, per registry and per repository, which images may be pulled.
+//
+// The format follows containers-policy.json(5):
+//
+//	{
+//	  "default": [{"type": "reject"}],
+//	  "transports": {
+//	    "docker": {
+//	      "docker.io/library": [{"type": "insecureAcceptAnything"}],
+//	      "registry.example.com/prod": [{"type": "signedBy", "keyPath": "/etc/nerdctl/cosign.pub"}],
+//	      "*.example.com": [{"type": "notationSigned"}]
+//	    }
+//	  }
+//	}
+package trustpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containerd/nerdctl/v2/pkg/signutil"
+)
+
+// TransportDocker is the transport of images pulled from registries.
+const TransportDocker = "docker"
+
+// Requirement types.
+const (
+	// TypeReject rejects any image.
+	TypeReject = "reject"
+	// TypeInsecureAcceptAnything accepts any image.
+	TypeInsecureAcceptAnything = "insecureAcceptAnything"
+	// TypeSignedBy requires a signature made with the cosign private key matching the public key file KeyPath.
+	// The transparency log is not verified, so the signatures made by nerdctl (which are not uploaded to it) are accepted.
+	TypeSignedBy = "signedBy"
+	// TypeSigstoreSigned requires a cosign signature, verified either with KeyPath
+	// (a key file, KMS URI or Kubernetes secret) or in the keyless mode with the certificate identity and issuer.
+	// The transparency log is verified, unless InsecureIgnoreTlog is set.
+	TypeSigstoreSigned = "sigstoreSigned"
+	// TypeNotationSigned requires a notation signature, verified with the trust store and the trust policy of notation.
+	TypeNotationSigned = "notationSigned"
+)
+
+// Policy is the content of a trust policy file.
+type Policy struct {
+	// Default are the requirements of images that match no scope of Transports.
+	Default []Requirement `json:"default"`
+	// Transports maps transports to scopes to requirements.
+	// Scopes are registries ("registry.example.com:5000"), namespaces or repositories ("docker.io/library/alpine"),
+	// tagged or digested references ("docker.io/library/alpine:3.20"), or wildcard domains ("*.example.com").
+	Transports map[string]map[string][]Requirement `json:"transports,omitempty"`
+}
+
+// Requirement is a condition that an image must satisfy to be pulled.
+type Requirement struct {
+	Type string `json:"type"`
+	// KeyPath is the public key for TypeSignedBy and TypeSigstoreSigned.
+	KeyPath string `json:"keyPath,omitempty"`
+	// Certificate identity and OIDC issuer for TypeSigstoreSigned in the keyless mode.
+	CertificateIdentity         string `json:"certificateIdentity,omitempty"`
+	CertificateIdentityRegexp   string `json:"certificateIdentityRegexp,omitempty"`
+	CertificateOidcIssuer       string `json:"certificateOidcIssuer,omitempty"`
+	CertificateOidcIssuerRegexp string `json:"certificateOidcIssuerRegexp,omitempty"`
+	// InsecureIgnoreTlog skips the verification of the transparency log for TypeSigstoreSigned.
+	InsecureIgnoreTlog bool `json:"insecureIgnoreTlog,omitempty"`
+}
+
+// Load reads and validates the trust policy file at path.
+func Load(path string) (*Policy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the trust policy: %w", err)
+	}
+	var p Policy
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to parse the trust policy %s: %w", path, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid trust policy %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Validate checks that the policy is well-formed.
+func (p *Policy) Validate() error {
+	if err := validateRequirements(p.Default); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	for transport, scopes := range p.Transports {
+		for scope, reqs := range scopes {
+			if scope == "" {
+				return fmt.Errorf("transport %q: empty scope", transport)
+			}
+			if err := validateRequirements(reqs); err != nil {
+				return fmt.Errorf("transport %q, scope %q: %w", transport, scope, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateRequirements(reqs []Requirement) error {
+	if len(reqs) == 0 {
+		return errors.New("no requirements")
+	}
+	for _, r := range reqs {
+		if err := r.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Requirement) validate() error {
+	keyless := r.CertificateIdentity != "" || r.CertificateIdentityRegexp != "" || r.CertificateOidcIssuer != "" || r.CertificateOidcIssuerRegexp != ""
+	switch r.Type {
+	case TypeReject, TypeInsecureAcceptAnything, TypeNotationSigned:
+		if r.KeyPath != "" || keyless || r.InsecureIgnoreTlog {
+			return fmt.Errorf("requirement %q does not take parameters", r.Type)
+		}
+	case TypeSignedBy:
+		if !signutil.IsCosignKeyFile(r.KeyPath) {
+			return fmt.Errorf("requirement %q needs keyPath to be the path of a public key file", r.Type)
+		}
+		if keyless {
+			return fmt.Errorf("requirement %q does not take certificate parameters", r.Type)
+		}
+		if r.InsecureIgnoreTlog {
+			return fmt.Errorf("requirement %q never verifies the transparency log, and does not take insecureIgnoreTlog", r.Type)
+		}
+	case TypeSigstoreSigned:
+		if (r.KeyPath == "") == !keyless {
+			return fmt.Errorf("requirement %q needs either keyPath or certificate parameters", r.Type)
+		}
+		if keyless {
+			if r.CertificateIdentity == "" && r.CertificateIdentityRegexp == "" {
+				return fmt.Errorf("requirement %q needs certificateIdentity or certificateIdentityRegexp", r.Type)
+			}
+			if r.CertificateOidcIssuer == "" && r.CertificateOidcIssuerRegexp == "" {
+				return fmt.Errorf("requirement %q needs certificateOidcIssuer or certificateOidcIssuerRegexp", r.Type)
+			}
+		}
+	case "":
+		return errors.New("requirement without type")
+	default:
+		return fmt.Errorf("unknown requirement type %q", r.Type)
+	}
+	return nil
+}
+
+// Match returns the requirements for the image reference named `name` (e.g., "docker.io/library/alpine")
+// with the tag or digest `tagOrDigest` (e.g., ":latest" or "@sha256:..."),
+// and the scope they were found in ("" for the default requirements).
+//
+// Like containers-policy.json(5), the most specific scope wins:
+// the tagged or digested reference, the repository, its parent namespaces up to the registry,
+// then the wildcard domains of the registry from the longest to the shortest.
+func (p *Policy) Match(transport, name, tagOrDigest string) (string, []Requirement) {
+	scopes := p.Transports[transport]
+	if tagOrDigest != "" {
+		if reqs, ok := scopes[name+tagOrDigest]; ok {
+			return name + tagOrDigest, reqs
+		}
+	}
+	for scope := name; scope != ""; {
+		if reqs, ok := scopes[scope]; ok {
+			return scope, reqs
+		}
+		i := strings.LastIndex(scope, "/")
+		if i < 0 {
+			break
+		}
+		scope = scope[:i]
+	}
+	host, _, _ := strings.Cut(name, "/")
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	for domain := host; ; {
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+		if reqs, ok := scopes["*."+parent]; ok {
+			return "*." + parent, reqs
+		}
+		domain = parent
+	}
+	return "", p.Default
+}
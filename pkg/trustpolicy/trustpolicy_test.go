@@ -0,0 +1,110 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package trustpolicy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const testPolicy = `{
+  "default": [{"type": "reject"}],
+  "transports": {
+    "docker": {
+      "docker.io/library": [{"type": "insecureAcceptAnything"}],
+      "docker.io/library/alpine:3.20": [{"type": "reject"}],
+      "registry.example.com:5000": [{"type": "signedBy", "keyPath": "/etc/nerdctl/cosign.pub"}],
+      "*.example.com": [{"type": "notationSigned"}],
+      "*.com": [{"type": "sigstoreSigned", "certificateIdentity": "me@example.com", "certificateOidcIssuer": "https://example.com"}]
+    }
+  }
+}`
+
+func loadTestPolicy(t *testing.T, content string) (*Policy, error) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	assert.NilError(t, os.WriteFile(path, []byte(content), 0o644))
+	return Load(path)
+}
+
+func TestMatch(t *testing.T) {
+	p, err := loadTestPolicy(t, testPolicy)
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		name        string
+		tagOrDigest string
+		scope       string
+		reqType     string
+	}{
+		{"docker.io/library/alpine", ":latest", "docker.io/library", TypeInsecureAcceptAnything},
+		{"docker.io/library/alpine", ":3.20", "docker.io/library/alpine:3.20", TypeReject},
+		{"docker.io/library/nginx", "", "docker.io/library", TypeInsecureAcceptAnything},
+		{"docker.io/someone/app", ":latest", "", TypeReject},
+		{"registry.example.com:5000/foo/bar", ":latest", "registry.example.com:5000", TypeSignedBy},
+		{"registry.example.com/foo", ":latest", "*.example.com", TypeNotationSigned},
+		{"a.b.example.com/foo", ":latest", "*.example.com", TypeNotationSigned},
+		{"ghcr.io/foo", ":latest", "", TypeReject},
+		{"quay.com/foo", ":latest", "*.com", TypeSigstoreSigned},
+	}
+	for _, tc := range testCases {
+		scope, reqs := p.Match(TransportDocker, tc.name, tc.tagOrDigest)
+		assert.Equal(t, scope, tc.scope, tc.name+tc.tagOrDigest)
+		assert.Equal(t, reqs[0].Type, tc.reqType, tc.name+tc.tagOrDigest)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	for _, content := range []string{
+		`{}`,
+		`{"default": []}`,
+		`{"default": [{"type": "unknown"}]}`,
+		`{"default": [{"type": "reject", "keyPath": "/cosign.pub"}]}`,
+		`{"default": [{"type": "signedBy"}]}`,
+		`{"default": [{"type": "signedBy", "keyPath": "awskms:///alias"}]}`,
+		`{"default": [{"type": "sigstoreSigned"}]}`,
+		`{"default": [{"type": "sigstoreSigned", "certificateIdentity": "me@example.com"}]}`,
+		`{"default": [{"type": "signedBy", "keyPath": "/cosign.pub", "insecureIgnoreTlog": true}]}`,
+		`{"default": [{"type": "insecureAcceptAnything"}], "transports": {"docker": {"docker.io": []}}}`,
+		`{"default": [{"type": "insecureAcceptAnything"}], "unknown": true}`,
+	} {
+		_, err := loadTestPolicy(t, content)
+		assert.ErrorContains(t, err, "", content)
+	}
+}
+
+func TestEnforceWithoutSignatures(t *testing.T) {
+	p, err := loadTestPolicy(t, testPolicy)
+	assert.NilError(t, err)
+	ctx := context.Background()
+
+	ref, err := p.Enforce(ctx, "alpine", nil, false)
+	assert.NilError(t, err)
+	assert.Equal(t, ref, "alpine")
+
+	_, err = p.Enforce(ctx, "alpine:3.20", nil, false)
+	assert.Assert(t, errors.Is(err, ErrDenied))
+	assert.ErrorContains(t, err, `scope "docker.io/library/alpine:3.20"`)
+
+	_, err = p.Enforce(ctx, "ghcr.io/foo/bar", nil, false)
+	assert.Assert(t, errors.Is(err, ErrDenied))
+	assert.ErrorContains(t, err, "default requirements")
+}